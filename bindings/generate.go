@@ -0,0 +1,7 @@
+// Package bindings holds the abigen Go bindings of the EigenDA contracts, one package per
+// contract listed in manifest.json. Regenerate them after changing a contract with:
+//
+//	forge build && go generate ./bindings
+package bindings
+
+//go:generate go run ../cmd/bindgen -manifest manifest.json -artifacts ../out -out .
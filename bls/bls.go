@@ -0,0 +1,259 @@
+// Package bls implements the BN254 BLS signature scheme the way the EigenLayer middleware checks
+// it on chain: messages are hashed to G1 with BN254.hashToG1, signatures live in G1 and aggregate
+// pubkeys are checked in both G1 and G2 with BLSSignatureChecker.trySignatureAndApkVerification.
+package bls
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Layr-Labs/eigenda/contracts/hashing"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// sqrtExponent is (p+1)/4, the exponent BN254.findYFromX uses to take square roots.
+var sqrtExponent = new(big.Int).Rsh(new(big.Int).Add(fp.Modulus(), big.NewInt(1)), 2)
+
+// HashToG1 mirrors BN254.hashToG1: the first x counting up from digest with a point on the curve,
+// taking the root BN254.findYFromX returns.
+func HashToG1(digest [32]byte) bn254.G1Affine {
+	var x, one, three fp.Element
+	x.SetBigInt(new(big.Int).SetBytes(digest[:]))
+	one.SetOne()
+	three.SetUint64(3)
+
+	for {
+		var beta, y, ySquared fp.Element
+		beta.Square(&x).Mul(&beta, &x).Add(&beta, &three)
+		y.Exp(beta, sqrtExponent)
+		ySquared.Square(&y)
+		if ySquared.Equal(&beta) {
+			return bn254.G1Affine{X: x, Y: y}
+		}
+		x.Add(&x, &one)
+	}
+}
+
+// HashG1Point mirrors BN254.hashG1Point, which the registries use to derive operator ids from pubkeys.
+func HashG1Point(point structs.G1Point) [32]byte {
+	return [32]byte(crypto.Keccak256Hash(uint256Bytes(point.X), uint256Bytes(point.Y)))
+}
+
+// Sign returns the signature of msgHash by privateKey, privateKey times HashToG1(msgHash).
+func Sign(privateKey *big.Int, msgHash [32]byte) structs.G1Point {
+	point := HashToG1(msgHash)
+	point.ScalarMultiplication(&point, privateKey)
+	return G1Point(&point)
+}
+
+// AggregateG1 returns the sum of points, failing if any of them is not a valid G1 point.
+func AggregateG1(points ...structs.G1Point) (structs.G1Point, bool) {
+	var sum bn254.G1Affine
+	for _, point := range points {
+		p, ok := G1Affine(point)
+		if !ok {
+			return structs.G1Point{}, false
+		}
+		sum.Add(&sum, &p)
+	}
+	return G1Point(&sum), true
+}
+
+// SignersApk returns the aggregate pubkey BLSSignatureChecker.checkSignatures verifies the
+// signature with: the sum of quorumApks, less each non-signer's pubkey once for every one of
+// quorumNumbers its quorum bitmap has set. It fails if any point is not a valid G1 point or the
+// lengths of the slices do not match.
+func SignersApk(
+	quorumNumbers []byte,
+	quorumApks []structs.G1Point,
+	nonSignerPubkeys []structs.G1Point,
+	nonSignerQuorumBitmaps []*big.Int,
+) (structs.G1Point, bool) {
+	if len(quorumApks) != len(quorumNumbers) || len(nonSignerQuorumBitmaps) != len(nonSignerPubkeys) {
+		return structs.G1Point{}, false
+	}
+
+	signingQuorumBitmap := new(big.Int)
+	for _, quorumNumber := range quorumNumbers {
+		signingQuorumBitmap.SetBit(signingQuorumBitmap, int(quorumNumber), 1)
+	}
+
+	var apk bn254.G1Affine
+	for j, pubkey := range nonSignerPubkeys {
+		var count uint64
+		for _, word := range new(big.Int).And(nonSignerQuorumBitmaps[j], signingQuorumBitmap).Bits() {
+			count += uint64(bits.OnesCount(uint(word)))
+		}
+		if count == 0 {
+			continue
+		}
+		point, ok := G1Affine(pubkey)
+		if !ok {
+			return structs.G1Point{}, false
+		}
+		point.ScalarMultiplication(&point, new(big.Int).SetUint64(count))
+		apk.Add(&apk, &point)
+	}
+	apk.Neg(&apk)
+
+	for _, quorumApk := range quorumApks {
+		point, ok := G1Affine(quorumApk)
+		if !ok {
+			return structs.G1Point{}, false
+		}
+		apk.Add(&apk, &point)
+	}
+	return G1Point(&apk), true
+}
+
+// TrySignatureAndApkVerification mirrors BLSSignatureChecker.trySignatureAndApkVerification. It
+// checks that sigma is a signature of msgHash by the key whose pubkeys are apk in G1 and apkG2 in
+// G2, combining both pairings into one with a random linear combination by gamma. pairingSuccessful
+// is false where the precompiles would fail on an invalid point.
+func TrySignatureAndApkVerification(
+	msgHash [32]byte,
+	apk structs.G1Point,
+	apkG2 structs.G2Point,
+	sigma structs.G1Point,
+) (pairingSuccessful bool, signatureIsValid bool) {
+	gammaPreimage := make([]byte, 0, 32*9)
+	gammaPreimage = append(gammaPreimage, msgHash[:]...)
+	for _, coordinate := range []*big.Int{
+		apk.X, apk.Y,
+		apkG2.X[0], apkG2.X[1], apkG2.Y[0], apkG2.Y[1],
+		sigma.X, sigma.Y,
+	} {
+		gammaPreimage = append(gammaPreimage, uint256Bytes(coordinate)...)
+	}
+	gamma := new(big.Int).SetBytes(crypto.Keccak256(gammaPreimage))
+	gamma.Mod(gamma, fr.Modulus())
+
+	apkPoint, ok := G1Affine(apk)
+	if !ok {
+		return false, false
+	}
+	sigmaPoint, ok := G1Affine(sigma)
+	if !ok {
+		return false, false
+	}
+	apkG2Point, ok := G2Affine(apkG2)
+	if !ok {
+		return false, false
+	}
+
+	var lhs bn254.G1Affine
+	lhs.ScalarMultiplication(&apkPoint, gamma)
+	lhs.Add(&sigmaPoint, &lhs)
+
+	_, _, g1Gen, g2Gen := bn254.Generators()
+	var rhs bn254.G1Affine
+	rhs.ScalarMultiplication(&g1Gen, gamma)
+	msgPoint := HashToG1(msgHash)
+	rhs.Add(&msgPoint, &rhs)
+
+	var negG2Gen bn254.G2Affine
+	negG2Gen.Neg(&g2Gen)
+
+	valid, err := bn254.PairingCheck([]bn254.G1Affine{lhs, rhs}, []bn254.G2Affine{negG2Gen, apkG2Point})
+	if err != nil {
+		return false, false
+	}
+	return true, valid
+}
+
+// VerifySignature checks that sigma is a signature of msgHash by the key whose G2 pubkey is apkG2,
+// e(sigma, g2) == e(HashToG1(msgHash), apkG2). It returns false for invalid points.
+func VerifySignature(msgHash [32]byte, apkG2 structs.G2Point, sigma structs.G1Point) bool {
+	sigmaPoint, ok := G1Affine(sigma)
+	if !ok {
+		return false
+	}
+	apkG2Point, ok := G2Affine(apkG2)
+	if !ok {
+		return false
+	}
+
+	_, _, _, g2Gen := bn254.Generators()
+	var negG2Gen bn254.G2Affine
+	negG2Gen.Neg(&g2Gen)
+	msgPoint := HashToG1(msgHash)
+
+	valid, err := bn254.PairingCheck([]bn254.G1Affine{sigmaPoint, msgPoint}, []bn254.G2Affine{negG2Gen, apkG2Point})
+	return err == nil && valid
+}
+
+// VerifyAttestation checks that attestation.Sigma is a signature of the hash of batchHeader by
+// the key whose G2 pubkey is attestation.ApkG2. It does not check ApkG2 against the quorum APKs.
+func VerifyAttestation(batchHeader structs.BatchHeaderV2, attestation structs.Attestation) (bool, error) {
+	batchHeaderHash, err := hashing.HashBatchHeaderV2(batchHeader)
+	if err != nil {
+		return false, err
+	}
+	return VerifySignature(batchHeaderHash, attestation.ApkG2, attestation.Sigma), nil
+}
+
+// G1Point converts p to the contract representation.
+func G1Point(p *bn254.G1Affine) structs.G1Point {
+	return structs.G1Point{X: p.X.BigInt(new(big.Int)), Y: p.Y.BigInt(new(big.Int))}
+}
+
+// G2Point converts p to the contract representation, which stores the imaginary coefficient first.
+func G2Point(p *bn254.G2Affine) structs.G2Point {
+	return structs.G2Point{
+		X: [2]*big.Int{p.X.A1.BigInt(new(big.Int)), p.X.A0.BigInt(new(big.Int))},
+		Y: [2]*big.Int{p.Y.A1.BigInt(new(big.Int)), p.Y.A0.BigInt(new(big.Int))},
+	}
+}
+
+// G1Affine converts a G1 point the way the ecAdd precompile accepts it, returning false for
+// coordinates that are out of range or off the curve. (0, 0) is the point at infinity.
+func G1Affine(point structs.G1Point) (bn254.G1Affine, bool) {
+	var p bn254.G1Affine
+	if !isFieldElement(point.X) || !isFieldElement(point.Y) {
+		return p, false
+	}
+	p.X.SetBigInt(bigOrZero(point.X))
+	p.Y.SetBigInt(bigOrZero(point.Y))
+	return p, p.IsOnCurve()
+}
+
+// G2Affine converts a G2 point the way the pairing precompile accepts it. Solidity stores the
+// imaginary coefficient first, so X[0] and Y[0] map to A1.
+func G2Affine(point structs.G2Point) (bn254.G2Affine, bool) {
+	var p bn254.G2Affine
+	for _, coordinate := range []*big.Int{point.X[0], point.X[1], point.Y[0], point.Y[1]} {
+		if !isFieldElement(coordinate) {
+			return p, false
+		}
+	}
+	p.X.A1.SetBigInt(bigOrZero(point.X[0]))
+	p.X.A0.SetBigInt(bigOrZero(point.X[1]))
+	p.Y.A1.SetBigInt(bigOrZero(point.Y[0]))
+	p.Y.A0.SetBigInt(bigOrZero(point.Y[1]))
+	return p, p.IsOnCurve() && p.IsInSubGroup()
+}
+
+func isFieldElement(v *big.Int) bool {
+	return v == nil || (v.Sign() >= 0 && v.Cmp(fp.Modulus()) < 0)
+}
+
+func bigOrZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+// uint256Bytes returns the 32 byte big endian encoding of v.
+func uint256Bytes(v *big.Int) []byte {
+	out := make([]byte, 32)
+	if v != nil {
+		v.FillBytes(out)
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package bls_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	contractEigenDAServiceManager "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	"github.com/Layr-Labs/eigenda/contracts/bls"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+// keyPair returns the G1 and G2 pubkeys of privateKey.
+func keyPair(privateKey int64) (structs.G1Point, structs.G2Point) {
+	_, _, g1, g2 := bn254.Generators()
+	var pubkeyG1 bn254.G1Affine
+	pubkeyG1.ScalarMultiplication(&g1, big.NewInt(privateKey))
+	var pubkeyG2 bn254.G2Affine
+	pubkeyG2.ScalarMultiplication(&g2, big.NewInt(privateKey))
+	return bls.G1Point(&pubkeyG1), bls.G2Point(&pubkeyG2)
+}
+
+// TestTrySignatureAndApkVerification compares TrySignatureAndApkVerification with
+// BLSSignatureChecker.trySignatureAndApkVerification through the ServiceManager.
+func TestTrySignatureAndApkVerification(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	opts := &bind.CallOpts{Context: context.Background()}
+
+	msgHash := [32]byte(crypto.Keccak256Hash([]byte("batch header")))
+	otherHash := [32]byte(crypto.Keccak256Hash([]byte("other batch header")))
+	apk, apkG2 := keyPair(42)
+	otherApk, otherApkG2 := keyPair(43)
+	zeroG1 := structs.G1Point{X: big.NewInt(0), Y: big.NewInt(0)}
+	zeroG2 := structs.G2Point{X: [2]*big.Int{big.NewInt(0), big.NewInt(0)}, Y: [2]*big.Int{big.NewInt(0), big.NewInt(0)}}
+	notOnCurve := structs.G1Point{X: big.NewInt(1), Y: big.NewInt(1)}
+	g2NotOnCurve := structs.G2Point{X: [2]*big.Int{big.NewInt(1), big.NewInt(1)}, Y: [2]*big.Int{big.NewInt(1), big.NewInt(1)}}
+
+	for _, tc := range []struct {
+		name                   string
+		apk                    structs.G1Point
+		apkG2                  structs.G2Point
+		sigma                  structs.G1Point
+		wantPairing, wantValid bool
+		wantRevert             bool
+	}{
+		{name: "valid", apk: apk, apkG2: apkG2, sigma: bls.Sign(big.NewInt(42), msgHash), wantPairing: true, wantValid: true},
+		{name: "other message", apk: apk, apkG2: apkG2, sigma: bls.Sign(big.NewInt(42), otherHash), wantPairing: true},
+		{name: "other signer", apk: apk, apkG2: apkG2, sigma: bls.Sign(big.NewInt(43), msgHash), wantPairing: true},
+		{name: "G1 and G2 keys differ", apk: otherApk, apkG2: apkG2, sigma: bls.Sign(big.NewInt(42), msgHash), wantPairing: true},
+		{name: "G2 key of another signer", apk: apk, apkG2: otherApkG2, sigma: bls.Sign(big.NewInt(42), msgHash), wantPairing: true},
+		{name: "identity key", apk: zeroG1, apkG2: zeroG2, sigma: zeroG1, wantPairing: true, wantValid: true},
+		{name: "G2 key not on the curve", apk: apk, apkG2: g2NotOnCurve, sigma: bls.Sign(big.NewInt(42), msgHash)},
+		{name: "sigma not on the curve", apk: apk, apkG2: apkG2, sigma: notOnCurve, wantRevert: true},
+		{name: "apk not on the curve", apk: notOnCurve, apkG2: apkG2, sigma: bls.Sign(big.NewInt(42), msgHash), wantRevert: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			pairing, valid := bls.TrySignatureAndApkVerification(msgHash, tc.apk, tc.apkG2, tc.sigma)
+			if pairing != tc.wantPairing || valid != tc.wantValid {
+				t.Errorf("TrySignatureAndApkVerification = %v, %v, want %v, %v", pairing, valid, tc.wantPairing, tc.wantValid)
+			}
+
+			got, err := f.ServiceManager.TrySignatureAndApkVerification(opts, msgHash,
+				structs.MustConvert[contractEigenDAServiceManager.BN254G1Point](tc.apk),
+				structs.MustConvert[contractEigenDAServiceManager.BN254G2Point](tc.apkG2),
+				structs.MustConvert[contractEigenDAServiceManager.BN254G1Point](tc.sigma))
+			if tc.wantRevert {
+				// the precompiles fail on the invalid point before the pairing, so the contract reverts
+				if err == nil {
+					t.Errorf("contract = %+v, want a revert", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.PairingSuccessful != pairing || got.SiganatureIsValid != valid {
+				t.Errorf("contract = %v, %v, Go = %v, %v", got.PairingSuccessful, got.SiganatureIsValid, pairing, valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+// Package cert serializes EigenDA certs for rollup inboxes. An encoded cert is a version byte
+// followed by the cert, either in the canonical ABI encoding of the arguments of
+// EigenDACertVerifier.verifyDACertV1 or verifyDACertV2, or as a compact RLP list of the same
+// structs with their fields in Solidity order.
+//
+// Decoding is strict: an encoding is only accepted if encoding the decoded cert again gives the
+// same bytes, so every cert has exactly one encoding per version and trailing bytes are rejected.
+package cert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	contractEigenDACertVerifier "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDACertVerifier"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// Version is the first byte of an encoded cert, naming the cert generation and its encoding.
+// Zero is not a version, so zeroed storage never decodes.
+type Version byte
+
+const (
+	VersionV1ABI Version = 0x01
+	VersionV1RLP Version = 0x02
+	VersionV2ABI Version = 0x03
+	VersionV2RLP Version = 0x04
+)
+
+func (v Version) String() string {
+	switch v {
+	case VersionV1ABI:
+		return "v1-abi"
+	case VersionV1RLP:
+		return "v1-rlp"
+	case VersionV2ABI:
+		return "v2-abi"
+	case VersionV2RLP:
+		return "v2-rlp"
+	default:
+		return fmt.Sprintf("unknown(0x%02x)", byte(v))
+	}
+}
+
+// Encoding selects how the cert following the version byte is encoded.
+type Encoding uint8
+
+const (
+	EncodingABI Encoding = iota
+	EncodingRLP
+)
+
+// Errors returned by Decode.
+var (
+	ErrEmpty          = errors.New("encoded cert is empty")
+	ErrUnknownVersion = errors.New("unknown cert version")
+	ErrTrailingBytes  = errors.New("trailing bytes after cert")
+	ErrNonCanonical   = errors.New("cert is not canonically encoded")
+)
+
+// Cert is a *CertV1 or a *CertV2.
+type Cert interface {
+	version(encoding Encoding) Version
+}
+
+// CertV1 holds the arguments of EigenDACertVerifier.verifyDACertV1.
+type CertV1 struct {
+	BlobHeader            structs.BlobHeader
+	BlobVerificationProof structs.BlobVerificationProof
+}
+
+// CertV2 holds the arguments of EigenDACertVerifier.verifyDACertV2.
+type CertV2 struct {
+	BatchHeader                 structs.BatchHeaderV2
+	BlobInclusionInfo           structs.BlobInclusionInfo
+	NonSignerStakesAndSignature structs.NonSignerStakesAndSignature
+	SignedQuorumNumbers         []byte
+}
+
+func (*CertV1) version(encoding Encoding) Version {
+	if encoding == EncodingRLP {
+		return VersionV1RLP
+	}
+	return VersionV1ABI
+}
+
+func (*CertV2) version(encoding Encoding) Version {
+	if encoding == EncodingRLP {
+		return VersionV2RLP
+	}
+	return VersionV2ABI
+}
+
+var (
+	verifyDACertV1Inputs = mustMethodInputs("verifyDACertV1")
+	verifyDACertV2Inputs = mustMethodInputs("verifyDACertV2")
+)
+
+func mustMethodInputs(name string) abi.Arguments {
+	parsed, err := contractEigenDACertVerifier.ContractEigenDACertVerifierMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	method, ok := parsed.Methods[name]
+	if !ok {
+		panic("EigenDACertVerifier has no method " + name)
+	}
+	return method.Inputs
+}
+
+// Encode returns the version byte of cert in encoding followed by the encoded cert. Every point
+// coordinate must be set and fit a uint256, in either encoding.
+func Encode(cert Cert, encoding Encoding) ([]byte, error) {
+	if encoding != EncodingABI && encoding != EncodingRLP {
+		return nil, fmt.Errorf("unknown cert encoding %d", encoding)
+	}
+	if err := checkUint256s(reflect.ValueOf(cert), "cert"); err != nil {
+		return nil, err
+	}
+	var body []byte
+	var err error
+	switch cert := cert.(type) {
+	case *CertV1:
+		if encoding == EncodingABI {
+			body, err = verifyDACertV1Inputs.Pack(cert.BlobHeader, cert.BlobVerificationProof)
+		} else {
+			body, err = rlp.EncodeToBytes(cert)
+		}
+	case *CertV2:
+		if encoding == EncodingABI {
+			body, err = verifyDACertV2Inputs.Pack(
+				cert.BatchHeader,
+				cert.BlobInclusionInfo,
+				cert.NonSignerStakesAndSignature,
+				cert.SignedQuorumNumbers,
+			)
+		} else {
+			body, err = rlp.EncodeToBytes(cert)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported cert type %T", cert)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(cert.version(encoding))}, body...), nil
+}
+
+// Decode decodes a cert encoded by Encode, returning its version.
+func Decode(data []byte) (Cert, Version, error) {
+	if len(data) == 0 {
+		return nil, 0, ErrEmpty
+	}
+	version, body := Version(data[0]), data[1:]
+
+	var cert Cert
+	var err error
+	switch version {
+	case VersionV1ABI:
+		cert, err = decodeV1ABI(body)
+	case VersionV2ABI:
+		cert, err = decodeV2ABI(body)
+	case VersionV1RLP:
+		cert, err = decodeRLP(body, new(CertV1))
+	case VersionV2RLP:
+		cert, err = decodeRLP(body, new(CertV2))
+	default:
+		return nil, version, fmt.Errorf("%w: %s", ErrUnknownVersion, version)
+	}
+	if err != nil {
+		return nil, version, fmt.Errorf("decoding %s cert: %w", version, err)
+	}
+
+	// the ABI decoder accepts padding, offsets and trailing bytes a fresh encoding would not
+	// have, and RLP can hold integers wider than the ABI types
+	encoding := EncodingABI
+	if version == VersionV1RLP || version == VersionV2RLP {
+		encoding = EncodingRLP
+	}
+	canonical, err := Encode(cert, encoding)
+	if err != nil {
+		return nil, version, fmt.Errorf("%w: %w", ErrNonCanonical, err)
+	}
+	if !bytes.Equal(canonical, data) {
+		if len(data) > len(canonical) && bytes.HasPrefix(data, canonical) {
+			return nil, version, ErrTrailingBytes
+		}
+		return nil, version, ErrNonCanonical
+	}
+	return cert, version, nil
+}
+
+// DecodeV1 decodes a V1 cert in either encoding.
+func DecodeV1(data []byte) (*CertV1, error) {
+	cert, version, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	certV1, ok := cert.(*CertV1)
+	if !ok {
+		return nil, fmt.Errorf("expected a V1 cert, got %s", version)
+	}
+	return certV1, nil
+}
+
+// DecodeV2 decodes a V2 cert in either encoding.
+func DecodeV2(data []byte) (*CertV2, error) {
+	cert, version, err := Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	certV2, ok := cert.(*CertV2)
+	if !ok {
+		return nil, fmt.Errorf("expected a V2 cert, got %s", version)
+	}
+	return certV2, nil
+}
+
+func decodeV1ABI(body []byte) (*CertV1, error) {
+	args, err := verifyDACertV1Inputs.Unpack(body)
+	if err != nil {
+		return nil, err
+	}
+	cert := new(CertV1)
+	if cert.BlobHeader, err = structs.Convert[structs.BlobHeader](args[0]); err != nil {
+		return nil, err
+	}
+	if cert.BlobVerificationProof, err = structs.Convert[structs.BlobVerificationProof](args[1]); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+func decodeV2ABI(body []byte) (*CertV2, error) {
+	args, err := verifyDACertV2Inputs.Unpack(body)
+	if err != nil {
+		return nil, err
+	}
+	cert := new(CertV2)
+	if cert.BatchHeader, err = structs.Convert[structs.BatchHeaderV2](args[0]); err != nil {
+		return nil, err
+	}
+	if cert.BlobInclusionInfo, err = structs.Convert[structs.BlobInclusionInfo](args[1]); err != nil {
+		return nil, err
+	}
+	if cert.NonSignerStakesAndSignature, err = structs.Convert[structs.NonSignerStakesAndSignature](args[2]); err != nil {
+		return nil, err
+	}
+	cert.SignedQuorumNumbers = args[3].([]byte)
+	return cert, nil
+}
+
+func decodeRLP[T Cert](body []byte, cert T) (T, error) {
+	if err := rlp.DecodeBytes(body, cert); err != nil {
+		if errors.Is(err, rlp.ErrMoreThanOneValue) {
+			return cert, ErrTrailingBytes
+		}
+		return cert, err
+	}
+	return cert, nil
+}
+
+// checkUint256s checks that every *big.Int reachable from v is set and fits a uint256. The ABI
+// encoder panics on nil integers and silently truncates wide ones.
+func checkUint256s(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if n, ok := v.Interface().(*big.Int); ok {
+			if n == nil || n.Sign() < 0 || n.BitLen() > 256 {
+				return fmt.Errorf("%s is not a uint256: %v", path, n)
+			}
+			return nil
+		}
+		if v.IsNil() {
+			return fmt.Errorf("%s is nil", path)
+		}
+		return checkUint256s(v.Elem(), path)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if err := checkUint256s(v.Field(i), path+"."+v.Type().Field(i).Name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := checkUint256s(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package cert_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Layr-Labs/eigenda/contracts/cert"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/certs"
+)
+
+// sameCert compares certs as printed, since decoding gives empty slices where a cert may hold nil
+// ones.
+func sameCert(a, b cert.Cert) bool {
+	return fmt.Sprintf("%+v", a) == fmt.Sprintf("%+v", b)
+}
+
+func TestRoundTrip(t *testing.T) {
+	// certs of zero values have no coordinates, which Encode refuses, so only their points are set
+	emptyV1 := &cert.CertV1{BlobHeader: structs.BlobHeader{Commitment: certs.G1Point(0, 0)}}
+	emptyV2 := &cert.CertV2{
+		BlobInclusionInfo: structs.BlobInclusionInfo{BlobCertificate: structs.BlobCertificate{BlobHeader: structs.BlobHeaderV2{
+			Commitment: structs.BlobCommitment{Commitment: certs.G1Point(0, 0), LengthCommitment: certs.G2Point(0, 0, 0, 0), LengthProof: certs.G2Point(0, 0, 0, 0)},
+		}}},
+		NonSignerStakesAndSignature: structs.NonSignerStakesAndSignature{ApkG2: certs.G2Point(0, 0, 0, 0), Sigma: certs.G1Point(0, 0)},
+	}
+
+	for _, tc := range []struct {
+		name        string
+		cert        cert.Cert
+		encoding    cert.Encoding
+		wantVersion cert.Version
+	}{
+		{name: "v1 abi", cert: certs.V1(), encoding: cert.EncodingABI, wantVersion: cert.VersionV1ABI},
+		{name: "v1 rlp", cert: certs.V1(), encoding: cert.EncodingRLP, wantVersion: cert.VersionV1RLP},
+		{name: "v2 abi", cert: certs.V2(), encoding: cert.EncodingABI, wantVersion: cert.VersionV2ABI},
+		{name: "v2 rlp", cert: certs.V2(), encoding: cert.EncodingRLP, wantVersion: cert.VersionV2RLP},
+		{name: "empty v1 abi", cert: emptyV1, encoding: cert.EncodingABI, wantVersion: cert.VersionV1ABI},
+		{name: "empty v1 rlp", cert: emptyV1, encoding: cert.EncodingRLP, wantVersion: cert.VersionV1RLP},
+		{name: "empty v2 abi", cert: emptyV2, encoding: cert.EncodingABI, wantVersion: cert.VersionV2ABI},
+		{name: "empty v2 rlp", cert: emptyV2, encoding: cert.EncodingRLP, wantVersion: cert.VersionV2RLP},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := cert.Encode(tc.cert, tc.encoding)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cert.Version(data[0]) != tc.wantVersion {
+				t.Errorf("encoded with version %s, want %s", cert.Version(data[0]), tc.wantVersion)
+			}
+
+			decoded, version, err := cert.Decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != tc.wantVersion {
+				t.Errorf("decoded version %s, want %s", version, tc.wantVersion)
+			}
+			if !sameCert(decoded, tc.cert) {
+				t.Errorf("decoded %+v, want %+v", decoded, tc.cert)
+			}
+			again, err := cert.Encode(decoded, tc.encoding)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(again, data) {
+				t.Errorf("decoded cert encodes to %x, want %x", again, data)
+			}
+
+			// DecodeV1 and DecodeV2 only accept their own generation
+			_, errV1 := cert.DecodeV1(data)
+			_, errV2 := cert.DecodeV2(data)
+			if _, isV1 := tc.cert.(*cert.CertV1); isV1 == (errV1 != nil) || isV1 == (errV2 == nil) {
+				t.Errorf("DecodeV1 = %v, DecodeV2 = %v", errV1, errV2)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cert     cert.Cert
+		encoding cert.Encoding
+	}{
+		{name: "v1 abi", cert: certs.V1(), encoding: cert.EncodingABI},
+		{name: "v1 rlp", cert: certs.V1(), encoding: cert.EncodingRLP},
+		{name: "v2 abi", cert: certs.V2(), encoding: cert.EncodingABI},
+		{name: "v2 rlp", cert: certs.V2(), encoding: cert.EncodingRLP},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := cert.Encode(tc.cert, tc.encoding)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, trailing := range [][]byte{{0}, {1}, make([]byte, 32)} {
+				if _, _, err := cert.Decode(append(bytes.Clone(data), trailing...)); !errors.Is(err, cert.ErrTrailingBytes) {
+					t.Errorf("%d trailing bytes: Decode = %v, want %v", len(trailing), err, cert.ErrTrailingBytes)
+				}
+			}
+			for _, n := range []int{1, 2, len(data) / 2, len(data) - 1} {
+				if _, _, err := cert.Decode(data[:n]); err == nil {
+					t.Errorf("truncated to %d bytes: Decode succeeded", n)
+				}
+			}
+		})
+	}
+
+	if _, _, err := cert.Decode(nil); !errors.Is(err, cert.ErrEmpty) {
+		t.Errorf("Decode of nothing = %v, want %v", err, cert.ErrEmpty)
+	}
+
+	data, err := cert.Encode(certs.V1(), cert.EncodingABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []byte{0x00, 0x05, 0xff} {
+		withVersion := append([]byte{version}, data[1:]...)
+		if _, gotVersion, err := cert.Decode(withVersion); !errors.Is(err, cert.ErrUnknownVersion) || gotVersion != cert.Version(version) {
+			t.Errorf("version 0x%02x: Decode = %s, %v, want %v", version, gotVersion, err, cert.ErrUnknownVersion)
+		}
+	}
+
+	// the ABI decoder ignores the padding of byte strings, which a fresh encoding zeroes; the last
+	// word of the cert holds the two QuorumIndices bytes
+	padded := bytes.Clone(data)
+	padded[len(padded)-1] = 1
+	if _, _, err := cert.Decode(padded); !errors.Is(err, cert.ErrNonCanonical) {
+		t.Errorf("dirty padding: Decode = %v, want %v", err, cert.ErrNonCanonical)
+	}
+
+	// RLP holds integers of any size, which do not fit the uint256 coordinates of the ABI
+	wide := certs.V1()
+	wide.BlobHeader.Commitment.X = new(big.Int).Lsh(big.NewInt(1), 256)
+	body, err := rlp.EncodeToBytes(wide)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := cert.Decode(append([]byte{byte(cert.VersionV1RLP)}, body...)); !errors.Is(err, cert.ErrNonCanonical) {
+		t.Errorf("coordinate wider than 256 bits: Decode = %v, want %v", err, cert.ErrNonCanonical)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	nilCoordinate := certs.V1()
+	nilCoordinate.BlobHeader.Commitment.Y = nil
+	negative := certs.V2()
+	negative.NonSignerStakesAndSignature.QuorumApks[1].X = big.NewInt(-1)
+	wide := certs.V2()
+	wide.NonSignerStakesAndSignature.ApkG2.Y[1] = new(big.Int).Lsh(big.NewInt(1), 256)
+
+	for _, tc := range []struct {
+		name     string
+		cert     cert.Cert
+		encoding cert.Encoding
+	}{
+		{name: "nil coordinate", cert: nilCoordinate, encoding: cert.EncodingABI},
+		{name: "nil coordinate rlp", cert: nilCoordinate, encoding: cert.EncodingRLP},
+		{name: "negative coordinate", cert: negative, encoding: cert.EncodingABI},
+		{name: "coordinate wider than 256 bits", cert: wide, encoding: cert.EncodingRLP},
+		{name: "nil cert", cert: (*cert.CertV2)(nil), encoding: cert.EncodingABI},
+		{name: "unknown encoding", cert: certs.V1(), encoding: cert.Encoding(2)},
+	} {
+		if data, err := cert.Encode(tc.cert, tc.encoding); err == nil {
+			t.Errorf("%s: Encode = %x, want an error", tc.name, data)
+		}
+	}
+}
+
+func TestVersionString(t *testing.T) {
+	for version, want := range map[cert.Version]string{
+		cert.VersionV1ABI:  "v1-abi",
+		cert.VersionV1RLP:  "v1-rlp",
+		cert.VersionV2ABI:  "v2-abi",
+		cert.VersionV2RLP:  "v2-rlp",
+		cert.Version(0):    "unknown(0x00)",
+		cert.Version(0xab): "unknown(0xab)",
+	} {
+		if got := version.String(); got != want {
+			t.Errorf("Version(0x%02x).String() = %q, want %q", byte(version), got, want)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+// Package challenger is a reference fraud-proof challenger for MockRollup. It follows the
+// postCommitment transactions sent to a rollup, fetches the blob behind each posted commitment,
+// and challenges the commitment at every point where the blob opens to a different value than the
+// rollup expects, with a G2 KZG proof that challengeCommitment accepts.
+//
+// MockRollup emits no event, so the challenger scans the transactions of each block for
+// successful postCommitment calls to the rollup. Commitments are keyed by block timestamp, so of
+// several postings in one block only the last is stored and challenged. Calls made through other
+// contracts are not seen. Like the indexer, it polls and stays Confirmations blocks behind the head;
+// it keeps no state across restarts and does not handle reorgs deeper than Confirmations.
+//
+// A posting whose blob cannot be fetched, or does not match the posted commitment, does not hold up
+// the scan: it is set aside and retried on the next passes, up to BlobAttempts times.
+package challenger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	contractMockRollup "github.com/Layr-Labs/eigenda/contracts/bindings/MockRollup"
+	"github.com/Layr-Labs/eigenda/contracts/kzg"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+var (
+	// ErrNotPostCommitment is returned when a transaction does not call postCommitment.
+	ErrNotPostCommitment = errors.New("transaction is not a postCommitment call")
+	// ErrCommitmentMismatch is logged when the blob a BlobSource returns does not commit to the
+	// posted commitment, so no proof about it would be accepted.
+	ErrCommitmentMismatch = errors.New("blob does not match the posted commitment")
+	// ErrTauMismatch is returned when the SRS of the challenger is not the setup of the rollup.
+	ErrTauMismatch = errors.New("SRS tau does not match the tau of the rollup")
+)
+
+// ChainReader is the subset of an ethclient the challenger reads the chain and sends challenges
+// through.
+type ChainReader interface {
+	bind.ContractBackend
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Posting is a commitment stored by a postCommitment transaction.
+type Posting struct {
+	BlockNumber uint64
+	TxHash      common.Hash
+	// Timestamp is the block timestamp the commitment is stored under.
+	Timestamp             *big.Int
+	Confirmer             common.Address
+	BlobHeader            structs.BlobHeader
+	BlobVerificationProof structs.BlobVerificationProof
+}
+
+// BlobSource fetches the blob behind a posted commitment, such as from the EigenDA retrieval
+// service with the batch and blob index of its verification proof.
+type BlobSource interface {
+	Blob(ctx context.Context, posting *Posting) ([]byte, error)
+}
+
+// ExpectedValue is the value the rollup expects a posted blob to open to at Point. Value must not
+// be nil.
+type ExpectedValue struct {
+	Point uint64
+	Value *big.Int
+}
+
+// ValueSource supplies the values the rollup expects a posted blob to open to, such as those
+// derived from what its sequencer published.
+type ValueSource interface {
+	ExpectedValues(ctx context.Context, posting *Posting) ([]ExpectedValue, error)
+}
+
+// Challenge is a challengeCommitment transaction sent by the challenger.
+type Challenge struct {
+	Posting  *Posting
+	Point    uint64
+	Expected *big.Int
+	// Value is what the posted blob opens to at Point, which Proof proves.
+	Value  *big.Int
+	Proof  structs.G2Point
+	TxHash common.Hash
+}
+
+// Config tunes the challenger. Zero values fall back to DefaultConfig.
+type Config struct {
+	// StartBlock is the first block scanned, usually the deployment block of the rollup.
+	StartBlock uint64
+	// Confirmations is how many blocks the challenger stays behind the head.
+	Confirmations uint64
+	// PollInterval is how long Run waits for new blocks once it has caught up.
+	PollInterval time.Duration
+	// BlobAttempts is how many passes try to fetch and check the blob of a posting before the
+	// challenger gives up on it.
+	BlobAttempts int
+	Logger       *slog.Logger
+}
+
+// DefaultConfig returns the defaults used for unset Config fields.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: 12 * time.Second,
+		BlobAttempts: 5,
+		Logger:       slog.Default(),
+	}
+}
+
+func withDefaults(config Config) Config {
+	defaults := DefaultConfig()
+	if config.PollInterval == 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.BlobAttempts == 0 {
+		config.BlobAttempts = defaults.BlobAttempts
+	}
+	if config.Logger == nil {
+		config.Logger = defaults.Logger
+	}
+	return config
+}
+
+// Challenger challenges the commitments posted to one MockRollup.
+type Challenger struct {
+	client        ChainReader
+	rollupAddress common.Address
+	rollup        *contractMockRollup.ContractMockRollup
+	srs           *kzg.SRS
+	tau           structs.G1Point
+	blobs         BlobSource
+	values        ValueSource
+	transactOpts  *bind.TransactOpts
+	config        Config
+
+	// nextBlock is the next block to scan.
+	nextBlock uint64
+	// challenged holds the points already challenged for each timestamp, so a block retried after
+	// a failed challenge does not resend the ones that went out.
+	challenged map[challengeKey]bool
+	// parked holds the postings whose blob could not be checked, retried at the start of each pass.
+	parked []*parkedPosting
+}
+
+type parkedPosting struct {
+	posting  *Posting
+	attempts int
+}
+
+type challengeKey struct {
+	timestamp string
+	point     uint64
+}
+
+// New returns a challenger of the MockRollup at rollup, opening commitments with srs and sending
+// challenges with transactOpts. It checks that srs has the tau the rollup was deployed with.
+func New(
+	ctx context.Context,
+	client ChainReader,
+	rollup common.Address,
+	srs *kzg.SRS,
+	blobs BlobSource,
+	values ValueSource,
+	transactOpts *bind.TransactOpts,
+	config Config,
+) (*Challenger, error) {
+	contract, err := contractMockRollup.NewContractMockRollup(rollup, client)
+	if err != nil {
+		return nil, err
+	}
+	rollupTau, err := contract.Tau(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, err
+	}
+	tau, err := srs.Tau()
+	if err != nil {
+		return nil, err
+	}
+	if tau.X.Cmp(rollupTau.X) != 0 || tau.Y.Cmp(rollupTau.Y) != 0 {
+		return nil, ErrTauMismatch
+	}
+
+	config = withDefaults(config)
+	return &Challenger{
+		client:        client,
+		rollupAddress: rollup,
+		rollup:        contract,
+		srs:           srs,
+		tau:           tau,
+		blobs:         blobs,
+		values:        values,
+		transactOpts:  transactOpts,
+		config:        config,
+		nextBlock:     config.StartBlock,
+		challenged:    make(map[challengeKey]bool),
+	}, nil
+}
+
+// Run challenges until ctx is done, syncing every PollInterval. Failed passes are logged and retried.
+func (c *Challenger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := c.Sync(ctx); err != nil && ctx.Err() == nil {
+			c.config.Logger.Warn("Challenging posted commitments failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync scans the blocks up to the confirmed head, sends the challenges they call for and returns
+// them. It does not wait for the challenges to be mined. A posting whose blob cannot be fetched or
+// does not match the posted commitment is logged and retried by the next passes, while the scan
+// goes on. Any other failure stops the pass, and the next one retries the block.
+func (c *Challenger) Sync(ctx context.Context) ([]*Challenge, error) {
+	head, err := c.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if head.Number.Uint64() < c.config.Confirmations {
+		return nil, nil
+	}
+	confirmedHead := head.Number.Uint64() - c.config.Confirmations
+
+	var challenges []*Challenge
+	parked := c.parked
+	c.parked = nil
+	for i, p := range parked {
+		sent, err := c.check(ctx, p.posting, p.attempts)
+		challenges = append(challenges, sent...)
+		if err != nil {
+			c.parked = append(c.parked, parked[i:]...)
+			return challenges, fmt.Errorf("posting %s: %w", p.posting.TxHash, err)
+		}
+	}
+
+	for ; c.nextBlock <= confirmedHead; c.nextBlock++ {
+		posting, err := c.posting(ctx, c.nextBlock)
+		if err != nil {
+			return challenges, fmt.Errorf("block %d: %w", c.nextBlock, err)
+		}
+		if posting == nil {
+			continue
+		}
+		sent, err := c.check(ctx, posting, 0)
+		challenges = append(challenges, sent...)
+		if err != nil {
+			return challenges, fmt.Errorf("posting %s: %w", posting.TxHash, err)
+		}
+	}
+	return challenges, nil
+}
+
+// check challenges posting, which attempts passes have failed to check the blob of. If its blob
+// cannot be checked again, check parks it for the next pass, or gives up on it after BlobAttempts.
+func (c *Challenger) check(ctx context.Context, posting *Posting, attempts int) ([]*Challenge, error) {
+	coeffs, commitment, err := c.blob(ctx, posting)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		attempts++
+		if attempts >= c.config.BlobAttempts {
+			c.config.Logger.Error("Giving up on posted commitment",
+				"posting", posting.TxHash, "block", posting.BlockNumber, "attempts", attempts, "err", err)
+			return nil, nil
+		}
+		c.config.Logger.Warn("Checking posted commitment failed, retrying next pass",
+			"posting", posting.TxHash, "block", posting.BlockNumber, "attempts", attempts, "err", err)
+		c.parked = append(c.parked, &parkedPosting{posting: posting, attempts: attempts})
+		return nil, nil
+	}
+	return c.challenge(ctx, posting, coeffs, commitment)
+}
+
+// posting returns the last successful postCommitment call to the rollup in the block, if any.
+func (c *Challenger) posting(ctx context.Context, blockNumber uint64) (*Posting, error) {
+	block, err := c.client.BlockByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	txs := block.Transactions()
+	for i := len(txs) - 1; i >= 0; i-- {
+		tx := txs[i]
+		if tx.To() == nil || *tx.To() != c.rollupAddress {
+			continue
+		}
+		blobHeader, blobVerificationProof, err := DecodePostCommitment(tx.Data())
+		if errors.Is(err, ErrNotPostCommitment) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("transaction %s: %w", tx.Hash(), err)
+		}
+		receipt, err := c.client.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			return nil, err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+
+		timestamp := new(big.Int).SetUint64(block.Time())
+		commitment, err := c.rollup.Commitments(&bind.CallOpts{Context: ctx, BlockNumber: block.Number()}, timestamp)
+		if err != nil {
+			return nil, err
+		}
+		return &Posting{
+			BlockNumber:           blockNumber,
+			TxHash:                tx.Hash(),
+			Timestamp:             timestamp,
+			Confirmer:             commitment.Confirmer,
+			BlobHeader:            blobHeader,
+			BlobVerificationProof: blobVerificationProof,
+		}, nil
+	}
+	return nil, nil
+}
+
+// blob fetches the blob of posting and returns its coefficients and commitment, checked against
+// the posted commitment.
+func (c *Challenger) blob(ctx context.Context, posting *Posting) ([]fr.Element, structs.G1Point, error) {
+	blob, err := c.blobs.Blob(ctx, posting)
+	if err != nil {
+		return nil, structs.G1Point{}, fmt.Errorf("fetching blob: %w", err)
+	}
+	coeffs, err := kzg.ToFieldElements(blob)
+	if err != nil {
+		return nil, structs.G1Point{}, err
+	}
+	commitment, err := kzg.Commit(c.srs, coeffs)
+	if err != nil {
+		return nil, structs.G1Point{}, err
+	}
+	posted := posting.BlobHeader.Commitment
+	if commitment.X.Cmp(posted.X) != 0 || commitment.Y.Cmp(posted.Y) != 0 {
+		return nil, structs.G1Point{}, ErrCommitmentMismatch
+	}
+	return coeffs, commitment, nil
+}
+
+// challenge opens the blob of posting, with coefficients coeffs and commitment commitment, at
+// every point with an expected value and challenges the points where the values differ.
+func (c *Challenger) challenge(ctx context.Context, posting *Posting, coeffs []fr.Element, commitment structs.G1Point) ([]*Challenge, error) {
+	expectedValues, err := c.values.ExpectedValues(ctx, posting)
+	if err != nil {
+		return nil, fmt.Errorf("fetching expected values: %w", err)
+	}
+
+	var challenges []*Challenge
+	for _, expected := range expectedValues {
+		key := challengeKey{timestamp: posting.Timestamp.String(), point: expected.Point}
+		if c.challenged[key] {
+			continue
+		}
+		// challengeCommitment only accepts points below the data length
+		if expected.Point >= uint64(posting.BlobHeader.DataLength) {
+			c.config.Logger.Debug("Skipping expected value past the data length",
+				"tx", posting.TxHash, "point", expected.Point, "dataLength", posting.BlobHeader.DataLength)
+			continue
+		}
+
+		point := new(big.Int).SetUint64(expected.Point)
+		proof, value, err := kzg.OpenG2(c.srs, coeffs, point)
+		if err != nil {
+			return challenges, err
+		}
+		if value.Cmp(new(fr.Element).SetBigInt(expected.Value).BigInt(new(big.Int))) == 0 {
+			continue
+		}
+		// openCommitment is a view, so check the proof as the rollup will before paying for it
+		if !kzg.VerifyOpening(point, value, c.tau, commitment, proof) {
+			return challenges, fmt.Errorf("proof for point %d does not open the commitment", expected.Point)
+		}
+
+		tx, err := c.rollup.ChallengeCommitment(
+			c.transactOpts,
+			posting.Timestamp,
+			point,
+			structs.MustConvert[contractMockRollup.BN254G2Point](proof),
+			value,
+		)
+		if err != nil {
+			return challenges, fmt.Errorf("challenging point %d: %w", expected.Point, err)
+		}
+		c.challenged[key] = true
+		c.config.Logger.Info("Challenged posted commitment",
+			"tx", tx.Hash(), "posting", posting.TxHash, "point", expected.Point, "expected", expected.Value, "value", value)
+		challenges = append(challenges, &Challenge{
+			Posting:  posting,
+			Point:    expected.Point,
+			Expected: expected.Value,
+			Value:    value,
+			Proof:    proof,
+			TxHash:   tx.Hash(),
+		})
+	}
+	return challenges, nil
+}
+
+// DecodePostCommitment decodes the arguments of a postCommitment call from its calldata.
+func DecodePostCommitment(data []byte) (structs.BlobHeader, structs.BlobVerificationProof, error) {
+	parsed, err := contractMockRollup.ContractMockRollupMetaData.GetAbi()
+	if err != nil {
+		return structs.BlobHeader{}, structs.BlobVerificationProof{}, err
+	}
+	method := parsed.Methods["postCommitment"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return structs.BlobHeader{}, structs.BlobVerificationProof{}, ErrNotPostCommitment
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return structs.BlobHeader{}, structs.BlobVerificationProof{}, fmt.Errorf("decoding postCommitment calldata: %w", err)
+	}
+
+	blobHeader, err := structs.Convert[structs.BlobHeader](args[0])
+	if err != nil {
+		return structs.BlobHeader{}, structs.BlobVerificationProof{}, err
+	}
+	blobVerificationProof, err := structs.Convert[structs.BlobVerificationProof](args[1])
+	if err != nil {
+		return structs.BlobHeader{}, structs.BlobVerificationProof{}, err
+	}
+	return blobHeader, blobVerificationProof, nil
+}
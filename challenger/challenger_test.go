@@ -0,0 +1,374 @@
+package challenger_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	contractMockRollup "github.com/Layr-Labs/eigenda/contracts/bindings/MockRollup"
+	"github.com/Layr-Labs/eigenda/contracts/challenger"
+	"github.com/Layr-Labs/eigenda/contracts/kzg"
+	"github.com/Layr-Labs/eigenda/contracts/merkle"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// blobs serves the blob posted for each commitment, as the EigenDA retrieval service would.
+type blobs map[string][]byte
+
+func (b blobs) Blob(_ context.Context, posting *challenger.Posting) ([]byte, error) {
+	blob, ok := b[commitmentKey(posting.BlobHeader.Commitment)]
+	if !ok {
+		return nil, fmt.Errorf("no blob for commitment %s", commitmentKey(posting.BlobHeader.Commitment))
+	}
+	return blob, nil
+}
+
+func commitmentKey(commitment structs.G1Point) string {
+	return commitment.X.String() + "," + commitment.Y.String()
+}
+
+// sequencerValues expects every posted blob to open to the blob the sequencer published at each
+// point below its data length.
+type sequencerValues struct {
+	coeffs []fr.Element
+}
+
+func (s *sequencerValues) ExpectedValues(_ context.Context, posting *challenger.Posting) ([]challenger.ExpectedValue, error) {
+	values := make([]challenger.ExpectedValue, posting.BlobHeader.DataLength)
+	for i := range values {
+		point := uint64(i)
+		values[i] = challenger.ExpectedValue{Point: point, Value: kzg.Evaluate(s.coeffs, new(big.Int).SetUint64(point))}
+	}
+	return values, nil
+}
+
+// rollupFixture is the EigenDA fixture with MockRollup deployed in the s = 2 test setup.
+type rollupFixture struct {
+	*fixture.Fixture
+	srs           *kzg.SRS
+	rollupAddress common.Address
+	rollup        *contractMockRollup.ContractMockRollup
+	startBlock    uint64
+}
+
+func newRollupFixture(t *testing.T) *rollupFixture {
+	t.Helper()
+	f := fixture.NewForTest(t, fixture.Config{})
+	srs := kzg.NewInsecureSRS(big.NewInt(2), 16, 15)
+	tau, err := srs.Tau()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rollupAddress, rollup, err := f.DeployMockRollup(tau)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployment, err := f.Client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &rollupFixture{Fixture: f, srs: srs, rollupAddress: rollupAddress, rollup: rollup, startBlock: deployment.Number.Uint64()}
+}
+
+func coefficients(t *testing.T, blob []byte) []fr.Element {
+	t.Helper()
+	coeffs, err := kzg.ToFieldElements(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return coeffs
+}
+
+// post confirms blob in an EigenDA batch and posts its commitment to the rollup, in a block of its
+// own. It returns the posting transaction and the commitment.
+func (f *rollupFixture) post(t *testing.T, blob []byte) (*types.Transaction, structs.G1Point) {
+	t.Helper()
+	coeffs := coefficients(t, blob)
+	commitment, err := kzg.Commit(f.srs, coeffs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobHeader := structs.BlobHeader{
+		Commitment: commitment,
+		DataLength: uint32(len(coeffs)),
+		QuorumBlobParams: []structs.QuorumBlobParam{
+			{QuorumNumber: 0, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55, ChunkLength: 1},
+			{QuorumNumber: 1, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55, ChunkLength: 1},
+		},
+	}
+	tree, err := merkle.NewTreeFromBlobHeaders([]structs.BlobHeader{blobHeader})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inclusionProof, err := tree.Proof(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := f.Client.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batchID, batchMetadata, err := f.ConfirmBatch(structs.BatchHeader{
+		BlobHeadersRoot:       tree.Root(),
+		QuorumNumbers:         []byte{0, 1},
+		SignedStakeForQuorums: []byte{100, 100},
+		ReferenceBlockNumber:  uint32(head.Number.Uint64()),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := f.rollup.PostCommitment(
+		f.TransactOpts(f.Confirmer),
+		structs.MustConvert[contractMockRollup.BlobHeader](blobHeader),
+		structs.MustConvert[contractMockRollup.BlobVerificationProof](structs.BlobVerificationProof{
+			BatchId:        batchID,
+			BlobIndex:      0,
+			BatchMetadata:  batchMetadata,
+			InclusionProof: inclusionProof,
+			QuorumIndices:  []byte{0, 1},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Mine(tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx, commitment
+}
+
+// newChallenger returns a challenger of the rollup from its deployment block on, with config
+// otherwise.
+func (f *rollupFixture) newChallenger(t *testing.T, srs *kzg.SRS, source challenger.BlobSource, values challenger.ValueSource, config challenger.Config) (*challenger.Challenger, error) {
+	t.Helper()
+	config.StartBlock, config.Logger = f.startBlock, testLogger
+	return challenger.New(context.Background(), f.Client, f.rollupAddress, srs, source, values,
+		f.TransactOpts(&f.Operators[0].Account), config)
+}
+
+// TestChallenger runs the challenger end to end: the sequencer publishes one batch, the confirmer
+// posts a commitment to another, and the challenger proves the difference with challengeCommitment.
+func TestChallenger(t *testing.T) {
+	f := newRollupFixture(t)
+	ctx := context.Background()
+
+	published := kzg.EncodePayload([]byte("transfer 10 from alice to bob; transfer 5 from bob to carol"))
+	posted := kzg.EncodePayload([]byte("transfer 10 from alice to bob; transfer 5 from bob to mallory"))
+	publishedCoeffs := coefficients(t, published)
+	postedCoeffs := coefficients(t, posted)
+
+	postTx, commitment := f.post(t, posted)
+	source := blobs{commitmentKey(commitment): posted}
+	c, err := f.newChallenger(t, f.srs, source, &sequencerValues{coeffs: publishedCoeffs}, challenger.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenges, err := c.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Backend.Commit()
+
+	// the blobs differ in their last symbol, so they open to the same value only at 0
+	var wantPoints, points []uint64
+	for point := uint64(0); point < uint64(len(postedCoeffs)); point++ {
+		x := new(big.Int).SetUint64(point)
+		if kzg.Evaluate(postedCoeffs, x).Cmp(kzg.Evaluate(publishedCoeffs, x)) != 0 {
+			wantPoints = append(wantPoints, point)
+		}
+	}
+	if len(wantPoints) == 0 {
+		t.Fatal("published and posted blobs open to the same values")
+	}
+	for _, challenge := range challenges {
+		points = append(points, challenge.Point)
+		if challenge.Posting.TxHash != postTx.Hash() || challenge.Posting.Confirmer != f.Confirmer.Address {
+			t.Errorf("point %d: challenged posting %s by %s, want %s by %s", challenge.Point,
+				challenge.Posting.TxHash, challenge.Posting.Confirmer, postTx.Hash(), f.Confirmer.Address)
+		}
+		x := new(big.Int).SetUint64(challenge.Point)
+		if challenge.Value.Cmp(kzg.Evaluate(postedCoeffs, x)) != 0 || challenge.Expected.Cmp(kzg.Evaluate(publishedCoeffs, x)) != 0 {
+			t.Errorf("point %d: expected %s, value %s", challenge.Point, challenge.Expected, challenge.Value)
+		}
+
+		receipt, err := f.Client.TransactionReceipt(ctx, challenge.TxHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("point %d: challenge %s reverted", challenge.Point, challenge.TxHash)
+		}
+		// challengeCommitment reports the outcome in its return value, which a call reads back
+		var out []interface{}
+		err = (&contractMockRollup.ContractMockRollupRaw{Contract: f.rollup}).Call(
+			&bind.CallOpts{Context: ctx},
+			&out,
+			"challengeCommitment",
+			challenge.Posting.Timestamp,
+			x,
+			structs.MustConvert[contractMockRollup.BN254G2Point](challenge.Proof),
+			challenge.Value,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accepted := out[0].(bool); !accepted {
+			t.Errorf("point %d: challengeCommitment rejected the proof", challenge.Point)
+		}
+	}
+	if !reflect.DeepEqual(points, wantPoints) {
+		t.Errorf("challenged points %v, want %v", points, wantPoints)
+	}
+
+	// the challenged blocks are not scanned again, and an honest posting is not challenged
+	_, honestCommitment := f.post(t, published)
+	source[commitmentKey(honestCommitment)] = published
+	if challenges, err := c.Sync(ctx); err != nil || len(challenges) != 0 {
+		t.Errorf("Sync after an honest posting = %d challenges, %v", len(challenges), err)
+	}
+}
+
+func TestChallengerErrors(t *testing.T) {
+	f := newRollupFixture(t)
+	ctx := context.Background()
+	blob := kzg.EncodePayload([]byte("transfer 10 from alice to bob"))
+	_, commitment := f.post(t, blob)
+	values := &sequencerValues{coeffs: coefficients(t, kzg.EncodePayload([]byte("transfer 10 from alice to carol")))}
+
+	if _, err := f.newChallenger(t, kzg.NewInsecureSRS(big.NewInt(3), 16, 15), blobs{}, values, challenger.Config{}); !errors.Is(err, challenger.ErrTauMismatch) {
+		t.Errorf("New with another setup = %v, want %v", err, challenger.ErrTauMismatch)
+	}
+
+	// a blob that is not the posted one cannot be opened against the commitment, so the posting
+	// is retried until BlobAttempts passes have failed and then given up on
+	source := blobs{commitmentKey(commitment): kzg.EncodePayload([]byte("another blob"))}
+	c, err := f.newChallenger(t, f.srs, source, values, challenger.Config{BlobAttempts: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for pass := 1; pass <= 2; pass++ {
+		if challenges, err := c.Sync(ctx); err != nil || len(challenges) != 0 {
+			t.Errorf("pass %d with the wrong blob: Sync = %d challenges, %v", pass, len(challenges), err)
+		}
+	}
+	source[commitmentKey(commitment)] = blob
+	if challenges, err := c.Sync(ctx); err != nil || len(challenges) != 0 {
+		t.Errorf("Sync after giving up on the posting = %d challenges, %v", len(challenges), err)
+	}
+}
+
+// TestChallengerUncheckedBlob checks that a posting whose blob cannot be fetched does not hold up
+// the postings after it, and is challenged once its blob turns up.
+func TestChallengerUncheckedBlob(t *testing.T) {
+	f := newRollupFixture(t)
+	ctx := context.Background()
+	values := &sequencerValues{coeffs: coefficients(t, kzg.EncodePayload([]byte("transfer 10 from alice to carol")))}
+
+	missing := kzg.EncodePayload([]byte("transfer 10 from alice to bob"))
+	missingTx, missingCommitment := f.post(t, missing)
+	later := kzg.EncodePayload([]byte("transfer 10 from alice to mallory"))
+	laterTx, laterCommitment := f.post(t, later)
+
+	source := blobs{commitmentKey(laterCommitment): later}
+	c, err := f.newChallenger(t, f.srs, source, values, challenger.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	postings := func(challenges []*challenger.Challenge) map[common.Hash]bool {
+		postings := make(map[common.Hash]bool)
+		for _, challenge := range challenges {
+			postings[challenge.Posting.TxHash] = true
+		}
+		return postings
+	}
+
+	challenges, err := c.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := postings(challenges), map[common.Hash]bool{laterTx.Hash(): true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sync without the first blob challenged postings %v, want %v", got, want)
+	}
+
+	source[commitmentKey(missingCommitment)] = missing
+	challenges, err = c.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := postings(challenges), map[common.Hash]bool{missingTx.Hash(): true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sync once the first blob turned up challenged postings %v, want %v", got, want)
+	}
+}
+
+func TestDecodePostCommitment(t *testing.T) {
+	parsed, err := contractMockRollup.ContractMockRollupMetaData.GetAbi()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobHeader := structs.BlobHeader{
+		Commitment: structs.G1Point{X: big.NewInt(1), Y: big.NewInt(2)},
+		DataLength: 3,
+		QuorumBlobParams: []structs.QuorumBlobParam{
+			{QuorumNumber: 1, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55, ChunkLength: 4},
+		},
+	}
+	blobVerificationProof := structs.BlobVerificationProof{
+		BatchId:   5,
+		BlobIndex: 6,
+		BatchMetadata: structs.BatchMetadata{
+			BatchHeader: structs.BatchHeader{
+				BlobHeadersRoot:       [32]byte{7},
+				QuorumNumbers:         []byte{1},
+				SignedStakeForQuorums: []byte{80},
+				ReferenceBlockNumber:  8,
+			},
+			SignatoryRecordHash:     [32]byte{9},
+			ConfirmationBlockNumber: 10,
+		},
+		InclusionProof: []byte{11, 12},
+		QuorumIndices:  []byte{0},
+	}
+	data, err := parsed.Pack("postCommitment",
+		structs.MustConvert[contractMockRollup.BlobHeader](blobHeader),
+		structs.MustConvert[contractMockRollup.BlobVerificationProof](blobVerificationProof))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotHeader, gotProof, err := challenger.DecodePostCommitment(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotHeader, blobHeader) || !reflect.DeepEqual(gotProof, blobVerificationProof) {
+		t.Errorf("DecodePostCommitment = %+v, %+v, want %+v, %+v", gotHeader, gotProof, blobHeader, blobVerificationProof)
+	}
+
+	challenge, err := parsed.Pack("challengeCommitment", big.NewInt(1), big.NewInt(2),
+		contractMockRollup.BN254G2Point{X: [2]*big.Int{big.NewInt(0), big.NewInt(0)}, Y: [2]*big.Int{big.NewInt(0), big.NewInt(0)}},
+		big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{"challengeCommitment": challenge, "short": {1, 2}, "empty": nil} {
+		if _, _, err := challenger.DecodePostCommitment(data); !errors.Is(err, challenger.ErrNotPostCommitment) {
+			t.Errorf("%s: DecodePostCommitment = %v, want %v", name, err, challenger.ErrNotPostCommitment)
+		}
+	}
+	if _, _, err := challenger.DecodePostCommitment(data[:40]); err == nil || errors.Is(err, challenger.ErrNotPostCommitment) {
+		t.Errorf("truncated calldata: DecodePostCommitment = %v, want a decoding error", err)
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testdata holds a manifest with a contract and an interface, and their forge artifacts.
+const (
+	testManifest  = "testdata/manifest.json"
+	testArtifacts = "testdata/out"
+)
+
+func generate(t *testing.T) string {
+	t.Helper()
+	outDir := t.TempDir()
+	stale, err := run(testManifest, testArtifacts, outDir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 0 {
+		t.Fatalf("generating reported stale files %v", stale)
+	}
+	return outDir
+}
+
+func TestRunWritesBindings(t *testing.T) {
+	outDir := generate(t)
+
+	binding, err := os.ReadFile(filepath.Join(outDir, "Counter", bindingFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(binding, []byte("// Code generated - DO NOT EDIT.")) || !bytes.Contains(binding, []byte("package contractCounter\n")) {
+		t.Errorf("Counter binding is not an abigen binding of package contractCounter")
+	}
+	binding, err = os.ReadFile(filepath.Join(outDir, "ICounter", bindingFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(binding, []byte("package counterInterface\n")) {
+		t.Errorf("ICounter binding does not use the package name of the manifest")
+	}
+
+	abiJSON, err := os.ReadFile(filepath.Join(outDir, "Counter", abiFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want any
+	if err := json.Unmarshal(abiJSON, &got); err != nil {
+		t.Fatal(err)
+	}
+	a, err := loadArtifact(testArtifacts, contractSpec{Name: "Counter"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(a.ABI, &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("abi.json does not hold the artifact abi")
+	}
+	if !bytes.HasPrefix(abiJSON, []byte("[\n  {\n")) || !bytes.HasSuffix(abiJSON, []byte("]\n")) {
+		t.Errorf("abi.json is not indented:\n%s", abiJSON)
+	}
+
+	hash, err := os.ReadFile(filepath.Join(outDir, "Counter", deployedBytecodeHashFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	linked := strings.Replace(a.DeployedBytecode.Object, "__$0123456789abcdef0123456789abcdef01$__", strings.Repeat("00", 20), 1)
+	if wantHash := crypto.Keccak256Hash(common.FromHex(linked)).Hex() + "\n"; string(hash) != wantHash {
+		t.Errorf("deployed bytecode hash = %q, want %q", hash, wantHash)
+	}
+
+	if _, err := os.Stat(filepath.Join(outDir, "ICounter", deployedBytecodeHashFile)); !os.IsNotExist(err) {
+		t.Errorf("interface has a deployed bytecode hash: %v", err)
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	outDir := generate(t)
+
+	stale, err := run(testManifest, testArtifacts, outDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 0 {
+		t.Fatalf("fresh bindings reported stale: %v", stale)
+	}
+
+	counterABI := filepath.Join(outDir, "Counter", abiFile)
+	if err := os.WriteFile(counterABI, []byte("[]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	counterBinding := filepath.Join(outDir, "Counter", bindingFile)
+	if err := os.Remove(counterBinding); err != nil {
+		t.Fatal(err)
+	}
+	interfaceHash := filepath.Join(outDir, "ICounter", deployedBytecodeHashFile)
+	if err := os.WriteFile(interfaceHash, []byte(common.Hash{}.Hex()+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stale, err = run(testManifest, testArtifacts, outDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{counterABI, counterBinding, interfaceHash}
+	if !reflect.DeepEqual(stale, want) {
+		t.Errorf("stale = %v, want %v", stale, want)
+	}
+
+	// check mode writes nothing
+	if _, err := os.Stat(counterBinding); !os.IsNotExist(err) {
+		t.Errorf("check mode wrote %s", counterBinding)
+	}
+
+	// regenerating removes the hash that should not exist
+	if _, err := run(testManifest, testArtifacts, outDir, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(interfaceHash); !os.IsNotExist(err) {
+		t.Errorf("regenerating kept %s", interfaceHash)
+	}
+	stale, err = run(testManifest, testArtifacts, outDir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stale) != 0 {
+		t.Errorf("regenerated bindings reported stale: %v", stale)
+	}
+}
+
+func TestRunMissingArtifact(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(manifestPath, []byte(`{"contracts":[{"name":"Missing"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(manifestPath, testArtifacts, t.TempDir(), true); !os.IsNotExist(err) {
+		t.Errorf("run with a missing artifact = %v, want a not exist error", err)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{
+			name:     "defaults",
+			manifest: `{"contracts":[{"name":"Counter"}]}`,
+		},
+		{
+			name:     "no name",
+			manifest: `{"contracts":[{"name":"Counter"},{"source":"Counter.sol"}]}`,
+			wantErr:  "contract 1 has no name",
+		},
+		{
+			name:     "listed twice",
+			manifest: `{"contracts":[{"name":"Counter"},{"name":"Counter","package":"other"}]}`,
+			wantErr:  "contract Counter is listed twice",
+		},
+		{
+			name:     "malformed",
+			manifest: `{"contracts":`,
+			wantErr:  "parsing manifest",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "manifest.json")
+			if err := os.WriteFile(path, []byte(tc.manifest), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			m, err := loadManifest(path)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("loadManifest error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := m.Contracts[0]
+			if c.source() != "Counter.sol" || c.pkg() != "contractCounter" {
+				t.Errorf("defaults = %s, %s", c.source(), c.pkg())
+			}
+		})
+	}
+}
+
+func TestDeployedBytecodeHash(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		object string
+		want   string
+		ok     bool
+	}{
+		{name: "empty", object: "0x", ok: false},
+		{name: "blank", object: "  ", ok: false},
+		{name: "code", object: "0x6080", want: "6080", ok: true},
+		{name: "no prefix", object: "6080\n", want: "6080", ok: true},
+		{
+			name:   "unlinked library",
+			object: "0x73__$0123456789abcdef0123456789abcdef01$__50",
+			want:   "73" + strings.Repeat("00", 20) + "50",
+			ok:     true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			hash, ok, err := deployedBytecodeHash(tc.object)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tc.ok {
+				t.Fatalf("ok = %v, want %v", ok, tc.ok)
+			}
+			if ok && hash != crypto.Keccak256Hash(common.FromHex(tc.want)) {
+				t.Errorf("hash = %s, want keccak256(0x%s)", hash, tc.want)
+			}
+		})
+	}
+
+	if _, _, err := deployedBytecodeHash("0xzz"); err == nil {
+		t.Error("hashing invalid hex succeeded")
+	}
+}
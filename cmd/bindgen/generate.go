@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Files written into each binding directory.
+const (
+	bindingFile              = "binding.go"
+	abiFile                  = "abi.json"
+	deployedBytecodeHashFile = "deployed_bytecode_hash.txt"
+)
+
+// generatedFiles lists every file bindgen owns in a binding directory.
+var generatedFiles = []string{bindingFile, abiFile, deployedBytecodeHashFile}
+
+// linkPlaceholder matches the placeholder solc leaves in bytecode for each unlinked library address.
+var linkPlaceholder = regexp.MustCompile(`__\$[0-9a-fA-F]{34}\$__`)
+
+// render returns the contents of every file in the binding directory of c, keyed by file name.
+func render(c contractSpec, a *artifact) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	// abigen is given the raw artifact ABI, exactly as compile.sh used to, so that the ABI string
+	// embedded in the binding keeps the compiler's ordering.
+	code, err := bind.Bind(
+		[]string{c.pkg()},
+		[]string{string(a.ABI)},
+		[]string{a.Bytecode.Object},
+		nil,
+		c.pkg(),
+		bind.LangGo,
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("binding %s: %w", c.Name, err)
+	}
+	files[bindingFile] = []byte(code)
+
+	var abiJSON bytes.Buffer
+	if err := json.Indent(&abiJSON, a.ABI, "", "  "); err != nil {
+		return nil, fmt.Errorf("formatting abi of %s: %w", c.Name, err)
+	}
+	abiJSON.WriteByte('\n')
+	files[abiFile] = abiJSON.Bytes()
+
+	// Interfaces and abstract contracts have no runtime code to hash.
+	hash, ok, err := deployedBytecodeHash(a.DeployedBytecode.Object)
+	if err != nil {
+		return nil, fmt.Errorf("hashing deployed bytecode of %s: %w", c.Name, err)
+	}
+	if ok {
+		files[deployedBytecodeHashFile] = []byte(hash.Hex() + "\n")
+	}
+
+	return files, nil
+}
+
+// deployedBytecodeHash returns the keccak256 of the deployed bytecode in a forge artifact.
+// Unlinked library placeholders are hashed as the zero address, so the hash only changes when
+// the contract itself does.
+func deployedBytecodeHash(object string) (hash common.Hash, ok bool, err error) {
+	object = strings.TrimPrefix(strings.TrimSpace(object), "0x")
+	if object == "" {
+		return hash, false, nil
+	}
+	object = linkPlaceholder.ReplaceAllString(object, strings.Repeat("0", 40))
+	code, err := hexutil.Decode("0x" + object)
+	if err != nil {
+		return hash, false, err
+	}
+	return crypto.Keccak256Hash(code), true, nil
+}
@@ -0,0 +1,116 @@
+// Command bindgen generates the Go bindings under bindings/ from forge build artifacts.
+//
+// Every contract in the manifest gets a bindings/<Contract> directory holding the abigen
+// binding (binding.go), the contract ABI (abi.json) and, for contracts with runtime code, the
+// keccak256 of the deployed bytecode (deployed_bytecode_hash.txt). It is run through
+// go generate ./bindings after forge build.
+//
+// With -check nothing is written; bindgen exits non-zero when any checked-in file differs from
+// what the artifacts produce.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+func main() {
+	var (
+		manifestPath = flag.String("manifest", "bindings/manifest.json", "path of the manifest listing the contracts to bind")
+		artifactsDir = flag.String("artifacts", "out", "forge output directory holding the build artifacts")
+		outDir       = flag.String("out", "bindings", "directory the binding packages are written to")
+		check        = flag.Bool("check", false, "report stale bindings instead of writing them")
+	)
+	flag.Parse()
+
+	stale, err := run(*manifestPath, *artifactsDir, *outDir, *check)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "bindgen: %v\n", err)
+		os.Exit(1)
+	}
+	if len(stale) > 0 {
+		fmt.Fprintln(os.Stderr, "bindgen: bindings are stale, run `forge build && go generate ./bindings`:")
+		for _, path := range stale {
+			fmt.Fprintf(os.Stderr, "  %s\n", path)
+		}
+		os.Exit(1)
+	}
+}
+
+// run generates the binding of every contract in the manifest. In check mode it returns the
+// paths that are missing or out of date instead of writing them.
+func run(manifestPath, artifactsDir, outDir string, check bool) ([]string, error) {
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []string
+	for _, c := range m.Contracts {
+		a, err := loadArtifact(artifactsDir, c)
+		if err != nil {
+			return nil, err
+		}
+		files, err := render(c, a)
+		if err != nil {
+			return nil, err
+		}
+
+		dir := filepath.Join(outDir, c.Name)
+		if check {
+			stale = append(stale, staleFiles(dir, files)...)
+			continue
+		}
+		if err := writeFiles(dir, files); err != nil {
+			return nil, err
+		}
+	}
+	return stale, nil
+}
+
+// staleFiles returns the generated files in dir whose contents differ from files, including
+// generated files that should no longer exist.
+func staleFiles(dir string, files map[string][]byte) []string {
+	var stale []string
+	for _, name := range generatedFiles {
+		path := filepath.Join(dir, name)
+		want, generated := files[name]
+		got, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			if generated {
+				stale = append(stale, path)
+			}
+		case err != nil || !generated || !bytes.Equal(got, want):
+			stale = append(stale, path)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+func writeFiles(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, name := range generatedFiles {
+		path := filepath.Join(dir, name)
+		data, ok := files[name]
+		if !ok {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// manifest declares the contracts that get a Go binding.
+type manifest struct {
+	Contracts []contractSpec `json:"contracts"`
+}
+
+// contractSpec is one manifest entry. Only Name is required.
+type contractSpec struct {
+	// Name is the contract name, which is also the binding directory under the output directory.
+	Name string `json:"name"`
+	// Source is the Solidity file declaring the contract, used to locate the forge artifact at
+	// <artifacts>/<Source>/<Name>.json. It defaults to <Name>.sol.
+	Source string `json:"source,omitempty"`
+	// Package is the Go package name of the binding. It defaults to contract<Name>.
+	Package string `json:"package,omitempty"`
+}
+
+func (c contractSpec) source() string {
+	if c.Source != "" {
+		return c.Source
+	}
+	return c.Name + ".sol"
+}
+
+func (c contractSpec) pkg() string {
+	if c.Package != "" {
+		return c.Package
+	}
+	return "contract" + c.Name
+}
+
+func loadManifest(path string) (*manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for i, c := range m.Contracts {
+		if c.Name == "" {
+			return nil, fmt.Errorf("manifest %s: contract %d has no name", path, i)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("manifest %s: contract %s is listed twice", path, c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return &m, nil
+}
+
+// artifact is the subset of a forge build artifact (out/<Source>/<Name>.json) the bindings are built from.
+type artifact struct {
+	ABI              json.RawMessage `json:"abi"`
+	Bytecode         bytecode        `json:"bytecode"`
+	DeployedBytecode bytecode        `json:"deployedBytecode"`
+}
+
+type bytecode struct {
+	Object string `json:"object"`
+}
+
+func loadArtifact(artifactsDir string, c contractSpec) (*artifact, error) {
+	path := filepath.Join(artifactsDir, c.source(), c.Name+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var a artifact
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("parsing artifact %s: %w", path, err)
+	}
+	if len(a.ABI) == 0 {
+		return nil, fmt.Errorf("artifact %s has no abi", path)
+	}
+	return &a, nil
+}
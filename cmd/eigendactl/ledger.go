@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/Layr-Labs/eigenda/contracts/ledger"
+)
+
+const ledgerUsage = `usage: eigendactl ledger <deposits|reservations|accounts|expiring|top> -rpc-url URL -payment-vault ADDRESS [flags]
+
+  deposits      every on-demand deposit as CSV
+  reservations  every reservation update as CSV
+  accounts      the deposit and reservation timeline of every account as JSON
+  expiring      reservations in effect at the last replayed block that end within -within, as CSV
+  top           the -n accounts with the largest on-demand deposits, as CSV
+
+The ledger is replayed from the PaymentVault events between -from-block and -to-block.`
+
+func runLedger(args []string) error {
+	if len(args) == 0 {
+		return errors.New(ledgerUsage)
+	}
+	mode := args[0]
+
+	flags := flag.NewFlagSet("ledger "+mode, flag.ContinueOnError)
+	rpcURL := flags.String("rpc-url", "", "ethereum RPC endpoint")
+	paymentVaultAddress := flags.String("payment-vault", "", "PaymentVault address")
+	fromBlock := flags.Uint64("from-block", 0, "first block to replay, usually the PaymentVault deployment block")
+	toBlock := flags.Uint64("to-block", 0, "last block to replay (default the head)")
+	blockRange := flags.Uint64("block-range", ledger.DefaultConfig().BlockRange, "most blocks filtered in one eth_getLogs request")
+	within := flags.Duration("within", 7*24*time.Hour, "expiring: how far ahead of the last replayed block to look")
+	n := flags.Int("n", 10, "top: number of accounts")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	switch mode {
+	case "deposits", "reservations", "accounts", "expiring", "top":
+	default:
+		return errors.New(ledgerUsage)
+	}
+	if *rpcURL == "" || !common.IsHexAddress(*paymentVaultAddress) {
+		return errors.New("-rpc-url and a valid -payment-vault address are required")
+	}
+
+	ctx := context.Background()
+	client, err := ethclient.Dial(*rpcURL)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", *rpcURL, err)
+	}
+	defer client.Close()
+
+	l, err := ledger.New(client, common.HexToAddress(*paymentVaultAddress), ledger.Config{BlockRange: *blockRange})
+	if err != nil {
+		return err
+	}
+	// the last replayed block is "now" for expiring, so a replay ending before the head sees the
+	// reservations as they stood then
+	var number *big.Int
+	if *toBlock != 0 {
+		number = new(big.Int).SetUint64(*toBlock)
+	}
+	last, err := client.HeaderByNumber(ctx, number)
+	if err != nil {
+		return err
+	}
+	end := last.Number.Uint64()
+	if err := l.Replay(ctx, *fromBlock, &end); err != nil {
+		return err
+	}
+
+	switch mode {
+	case "deposits":
+		return ledger.WriteDepositsCSV(os.Stdout, l.Deposits())
+	case "reservations":
+		return ledger.WriteReservationsCSV(os.Stdout, l.ReservationUpdates())
+	case "accounts":
+		return l.WriteJSON(os.Stdout)
+	case "expiring":
+		return ledger.WriteReservationsCSV(os.Stdout, l.Expiring(last.Time, last.Time+uint64(within.Seconds())))
+	default:
+		out := csv.NewWriter(os.Stdout)
+		if err := out.Write([]string{"account", "total_deposit_wei"}); err != nil {
+			return err
+		}
+		for _, account := range l.TopDepositors(*n) {
+			if err := out.Write([]string{account.Address.Hex(), account.TotalDeposit().String()}); err != nil {
+				return err
+			}
+		}
+		out.Flush()
+		return out.Error()
+	}
+}
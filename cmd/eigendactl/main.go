@@ -0,0 +1,61 @@
+// Command eigendactl is tooling for working with the EigenDA contracts.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var commands = []command{
+	{
+		name:        "security",
+		description: "check and solve the blob version security parameters",
+		run:         runSecurity,
+	},
+	{
+		name:        "ledger",
+		description: "export PaymentVault deposits and reservations",
+		run:         runLedger,
+	},
+	{
+		name:        "operators",
+		description: "snapshot and diff the registered operators",
+		run:         runOperators,
+	},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != os.Args[1] {
+			continue
+		}
+		if err := cmd.run(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: eigendactl <command> [arguments]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.description)
+	}
+}
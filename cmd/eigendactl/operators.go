@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/Layr-Labs/eigenda/contracts/operatorstate"
+)
+
+const operatorsUsage = `usage: eigendactl operators <snapshot|diff> -rpc-url URL -operator-state-retriever ADDRESS -registry-coordinator ADDRESS [flags]
+
+  snapshot  the operators of every quorum at -block as JSON
+  diff      the joins, leaves and stake changes between -from-block and -block as JSON`
+
+func runOperators(args []string) error {
+	if len(args) == 0 {
+		return errors.New(operatorsUsage)
+	}
+	mode := args[0]
+
+	flags := flag.NewFlagSet("operators "+mode, flag.ContinueOnError)
+	rpcURL := flags.String("rpc-url", "", "ethereum RPC endpoint")
+	retrieverAddress := flags.String("operator-state-retriever", "", "OperatorStateRetriever address")
+	coordinatorAddress := flags.String("registry-coordinator", "", "RegistryCoordinator address")
+	block := flags.Uint64("block", 0, "block to snapshot (default the head)")
+	fromBlock := flags.Uint64("from-block", 0, "diff: block to compare -block with")
+	quorums := flags.String("quorums", "", "comma separated quorum numbers (default every quorum)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	switch mode {
+	case "snapshot", "diff":
+	default:
+		return errors.New(operatorsUsage)
+	}
+	if *rpcURL == "" || !common.IsHexAddress(*retrieverAddress) || !common.IsHexAddress(*coordinatorAddress) {
+		return errors.New("-rpc-url and valid -operator-state-retriever and -registry-coordinator addresses are required")
+	}
+	quorumNumbers, err := parseQuorumNumbers(*quorums)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	client, err := ethclient.Dial(*rpcURL)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", *rpcURL, err)
+	}
+	defer client.Close()
+
+	if *block == 0 {
+		if *block, err = client.BlockNumber(ctx); err != nil {
+			return err
+		}
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	reader, err := operatorstate.NewReader(opts, client, common.HexToAddress(*retrieverAddress), common.HexToAddress(*coordinatorAddress))
+	if err != nil {
+		return err
+	}
+	snapshot, err := reader.Snapshot(opts, uint32(*block), quorumNumbers)
+	if err != nil {
+		return err
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	if mode == "snapshot" {
+		return out.Encode(snapshot)
+	}
+	if *fromBlock > *block {
+		return errors.New("-from-block must not be after -block")
+	}
+	older, err := reader.Snapshot(opts, uint32(*fromBlock), quorumNumbers)
+	if err != nil {
+		return err
+	}
+	return out.Encode(operatorstate.Diff(older, snapshot))
+}
+
+func parseQuorumNumbers(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var quorumNumbers []byte
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.ParseUint(strings.TrimSpace(field), 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("quorum number %q: %w", field, err)
+		}
+		quorumNumbers = append(quorumNumbers, byte(n))
+	}
+	return quorumNumbers, nil
+}
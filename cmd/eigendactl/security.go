@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	contractEigenDACertVerifier "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDACertVerifier"
+	"github.com/Layr-Labs/eigenda/contracts/reverts"
+	"github.com/Layr-Labs/eigenda/contracts/security"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+const securityUsage = `usage: eigendactl security <verify|max-adversary|min-chunks> [flags]
+
+  verify         check a VersionedBlobParams and SecurityThresholds pair
+  max-adversary  find the highest adversary threshold that passes for the blob params
+  min-chunks     find the fewest chunks that pass for the operator cap, coding rate and thresholds
+
+When -rpc-url and -cert-verifier are set, the result is also checked against
+EigenDACertVerifier.verifyDACertSecurityParams on the deployed contract.`
+
+func runSecurity(args []string) error {
+	if len(args) == 0 {
+		return errors.New(securityUsage)
+	}
+	mode := args[0]
+
+	flags := flag.NewFlagSet("security "+mode, flag.ContinueOnError)
+	maxNumOperators := flags.Uint64("max-num-operators", 0, "VersionedBlobParams.maxNumOperators")
+	numChunks := flags.Uint64("num-chunks", 0, "VersionedBlobParams.numChunks")
+	codingRate := flags.Uint64("coding-rate", 0, "VersionedBlobParams.codingRate")
+	confirmationThreshold := flags.Uint64("confirmation-threshold", 0, "SecurityThresholds.confirmationThreshold")
+	adversaryThreshold := flags.Uint64("adversary-threshold", 0, "SecurityThresholds.adversaryThreshold")
+	rpcURL := flags.String("rpc-url", "", "ethereum RPC endpoint used to cross check the result")
+	certVerifierAddress := flags.String("cert-verifier", "", "EigenDACertVerifier address used to cross check the result")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *maxNumOperators > math.MaxUint32 || *numChunks > math.MaxUint32 {
+		return errors.New("max-num-operators and num-chunks must fit in a uint32")
+	}
+	if *codingRate > math.MaxUint8 || *confirmationThreshold > math.MaxUint8 || *adversaryThreshold > math.MaxUint8 {
+		return errors.New("coding-rate and thresholds must fit in a uint8")
+	}
+	blobParams := structs.VersionedBlobParams{
+		MaxNumOperators: uint32(*maxNumOperators),
+		NumChunks:       uint32(*numChunks),
+		CodingRate:      uint8(*codingRate),
+	}
+	securityThresholds := structs.SecurityThresholds{
+		ConfirmationThreshold: uint8(*confirmationThreshold),
+		AdversaryThreshold:    uint8(*adversaryThreshold),
+	}
+
+	var result error
+	switch mode {
+	case "verify":
+		result = security.Verify(blobParams, securityThresholds)
+		if result == nil {
+			fmt.Println("security assumptions are met")
+		}
+	case "max-adversary":
+		threshold, err := security.MaxAdversaryThreshold(blobParams, securityThresholds.ConfirmationThreshold)
+		if err != nil {
+			return err
+		}
+		securityThresholds.AdversaryThreshold = threshold
+		fmt.Printf("max adversary threshold: %d\n", threshold)
+	case "min-chunks":
+		chunks, err := security.MinNumChunks(blobParams.MaxNumOperators, blobParams.CodingRate, securityThresholds)
+		if err != nil {
+			return err
+		}
+		blobParams.NumChunks = chunks
+		fmt.Printf("min num chunks: %d\n", chunks)
+	default:
+		return errors.New(securityUsage)
+	}
+
+	if *rpcURL != "" || *certVerifierAddress != "" {
+		if err := crossCheckSecurityParams(*rpcURL, *certVerifierAddress, blobParams, securityThresholds, result); err != nil {
+			return err
+		}
+		fmt.Println("result matches the deployed cert verifier")
+	}
+	return result
+}
+
+// crossCheckSecurityParams runs the check on a deployed EigenDACertVerifier and compares the
+// outcome, including the revert reason, with the local result.
+func crossCheckSecurityParams(
+	rpcURL string,
+	certVerifierAddress string,
+	blobParams structs.VersionedBlobParams,
+	securityThresholds structs.SecurityThresholds,
+	localResult error,
+) error {
+	if rpcURL == "" || !common.IsHexAddress(certVerifierAddress) {
+		return errors.New("cross checking requires both -rpc-url and a valid -cert-verifier address")
+	}
+
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", rpcURL, err)
+	}
+	defer client.Close()
+
+	certVerifier, err := contractEigenDACertVerifier.NewContractEigenDACertVerifierCaller(common.HexToAddress(certVerifierAddress), client)
+	if err != nil {
+		return err
+	}
+	onchainResult := certVerifier.VerifyDACertSecurityParams(&bind.CallOpts{Context: context.Background()}, blobParams, securityThresholds)
+
+	switch {
+	case localResult == nil && onchainResult == nil:
+		return nil
+	case localResult != nil && errors.Is(reverts.Decode(onchainResult), localResult):
+		return nil
+	default:
+		return fmt.Errorf("result mismatch: local %v, cert verifier %v", localResult, onchainResult)
+	}
+}
@@ -0,0 +1,188 @@
+// Package hashing computes the keccak digests of EigenDA structs exactly as
+// src/libraries/EigenDAHasher.sol does on chain, for both V1 and V2 types.
+//
+// Golden vectors produced by script/GenerateUnitTestHashes.s.sol live in testdata/golden_vectors.json;
+// test/unit/EigenDAHasherUnit.t.sol keeps them in sync with the Solidity hasher.
+package hashing
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+var (
+	g1PointComponents = []abi.ArgumentMarshaling{
+		{Name: "X", Type: "uint256"},
+		{Name: "Y", Type: "uint256"},
+	}
+	g2PointComponents = []abi.ArgumentMarshaling{
+		{Name: "X", Type: "uint256[2]"},
+		{Name: "Y", Type: "uint256[2]"},
+	}
+
+	uint16Type  = mustNewType("uint16", nil)
+	bytesType   = mustNewType("bytes", nil)
+	bytes32Type = mustNewType("bytes32", nil)
+
+	uint32ArrayType = mustNewType("uint32[]", nil)
+
+	quorumBlobParamComponents = []abi.ArgumentMarshaling{
+		{Name: "quorumNumber", Type: "uint8"},
+		{Name: "adversaryThresholdPercentage", Type: "uint8"},
+		{Name: "confirmationThresholdPercentage", Type: "uint8"},
+		{Name: "chunkLength", Type: "uint32"},
+	}
+
+	blobHeaderType = mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "commitment", Type: "tuple", Components: g1PointComponents},
+		{Name: "dataLength", Type: "uint32"},
+		{Name: "quorumBlobParams", Type: "tuple[]", Components: quorumBlobParamComponents},
+	})
+
+	batchHeaderType = mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "blobHeadersRoot", Type: "bytes32"},
+		{Name: "quorumNumbers", Type: "bytes"},
+		{Name: "signedStakeForQuorums", Type: "bytes"},
+		{Name: "referenceBlockNumber", Type: "uint32"},
+	})
+
+	reducedBatchHeaderType = mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "blobHeadersRoot", Type: "bytes32"},
+		{Name: "referenceBlockNumber", Type: "uint32"},
+	})
+
+	blobCommitmentType = mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "commitment", Type: "tuple", Components: g1PointComponents},
+		{Name: "lengthCommitment", Type: "tuple", Components: g2PointComponents},
+		{Name: "lengthProof", Type: "tuple", Components: g2PointComponents},
+		{Name: "length", Type: "uint32"},
+	})
+
+	batchHeaderV2Type = mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "batchRoot", Type: "bytes32"},
+		{Name: "referenceBlockNumber", Type: "uint32"},
+	})
+)
+
+func mustNewType(t string, components []abi.ArgumentMarshaling) abi.Type {
+	typ, err := abi.NewType(t, "", components)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// HashBatchHashedMetadata mirrors EigenDAHasher.hashBatchHashedMetadata(bytes32,bytes32,uint32).
+func HashBatchHashedMetadata(batchHeaderHash [32]byte, signatoryRecordHash [32]byte, blockNumber uint32) [32]byte {
+	return [32]byte(crypto.Keccak256Hash(batchHeaderHash[:], signatoryRecordHash[:], uint32Bytes(blockNumber)))
+}
+
+// HashBatchHashedMetadataFromConfirmationData mirrors EigenDAHasher.hashBatchHashedMetadata(bytes32,bytes,uint32).
+func HashBatchHashedMetadataFromConfirmationData(batchHeaderHash [32]byte, confirmationData []byte, blockNumber uint32) [32]byte {
+	return [32]byte(crypto.Keccak256Hash(batchHeaderHash[:], confirmationData, uint32Bytes(blockNumber)))
+}
+
+// HashBatchMetadata mirrors EigenDAHasher.hashBatchMetadata.
+func HashBatchMetadata(batchMetadata structs.BatchMetadata) ([32]byte, error) {
+	batchHeaderHash, err := HashBatchHeader(batchMetadata.BatchHeader)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return HashBatchHashedMetadata(batchHeaderHash, batchMetadata.SignatoryRecordHash, batchMetadata.ConfirmationBlockNumber), nil
+}
+
+// HashBatchHeader mirrors EigenDAHasher.hashBatchHeader and EigenDAHasher.hashBatchHeaderMemory.
+func HashBatchHeader(batchHeader structs.BatchHeader) ([32]byte, error) {
+	encoded, err := abi.Arguments{{Type: batchHeaderType}}.Pack(batchHeader)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return [32]byte(crypto.Keccak256Hash(encoded)), nil
+}
+
+// HashReducedBatchHeader mirrors EigenDAHasher.hashReducedBatchHeader.
+func HashReducedBatchHeader(reducedBatchHeader structs.ReducedBatchHeader) ([32]byte, error) {
+	encoded, err := abi.Arguments{{Type: reducedBatchHeaderType}}.Pack(reducedBatchHeader)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return [32]byte(crypto.Keccak256Hash(encoded)), nil
+}
+
+// HashBlobHeader mirrors EigenDAHasher.hashBlobHeader.
+func HashBlobHeader(blobHeader structs.BlobHeader) ([32]byte, error) {
+	encoded, err := abi.Arguments{{Type: blobHeaderType}}.Pack(blobHeader)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return [32]byte(crypto.Keccak256Hash(encoded)), nil
+}
+
+// ConvertBatchHeaderToReducedBatchHeader mirrors EigenDAHasher.convertBatchHeaderToReducedBatchHeader.
+func ConvertBatchHeaderToReducedBatchHeader(batchHeader structs.BatchHeader) structs.ReducedBatchHeader {
+	return structs.ReducedBatchHeader{
+		BlobHeadersRoot:      batchHeader.BlobHeadersRoot,
+		ReferenceBlockNumber: batchHeader.ReferenceBlockNumber,
+	}
+}
+
+// HashBatchHeaderToReducedBatchHeader mirrors EigenDAHasher.hashBatchHeaderToReducedBatchHeader.
+// This is the message operators sign for a V1 batch.
+func HashBatchHeaderToReducedBatchHeader(batchHeader structs.BatchHeader) ([32]byte, error) {
+	return HashReducedBatchHeader(ConvertBatchHeaderToReducedBatchHeader(batchHeader))
+}
+
+// HashBatchHeaderV2 mirrors EigenDAHasher.hashBatchHeaderV2.
+func HashBatchHeaderV2(batchHeader structs.BatchHeaderV2) ([32]byte, error) {
+	encoded, err := abi.Arguments{{Type: batchHeaderV2Type}}.Pack(batchHeader)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return [32]byte(crypto.Keccak256Hash(encoded)), nil
+}
+
+// HashBlobHeaderV2 mirrors EigenDAHasher.hashBlobHeaderV2.
+func HashBlobHeaderV2(blobHeader structs.BlobHeaderV2) ([32]byte, error) {
+	inner, err := abi.Arguments{
+		{Type: uint16Type},
+		{Type: bytesType},
+		{Type: blobCommitmentType},
+	}.Pack(blobHeader.Version, blobHeader.QuorumNumbers, blobHeader.Commitment)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	encoded, err := abi.Arguments{
+		{Type: bytes32Type},
+		{Type: bytes32Type},
+	}.Pack(crypto.Keccak256Hash(inner), blobHeader.PaymentHeaderHash)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return [32]byte(crypto.Keccak256Hash(encoded)), nil
+}
+
+// HashBlobCertificate mirrors EigenDAHasher.hashBlobCertificate.
+func HashBlobCertificate(blobCertificate structs.BlobCertificate) ([32]byte, error) {
+	blobHeaderHash, err := HashBlobHeaderV2(blobCertificate.BlobHeader)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	encoded, err := abi.Arguments{
+		{Type: bytes32Type},
+		{Type: bytesType},
+		{Type: uint32ArrayType},
+	}.Pack(blobHeaderHash, blobCertificate.Signature, blobCertificate.RelayKeys)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return [32]byte(crypto.Keccak256Hash(encoded)), nil
+}
+
+// uint32Bytes returns the packed encoding of v.
+func uint32Bytes(v uint32) []byte {
+	return []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+}
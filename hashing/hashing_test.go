@@ -0,0 +1,147 @@
+package hashing
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// goldenVectors is testdata/golden_vectors.json, written by script/GenerateUnitTestHashes.s.sol.
+type goldenVectors struct {
+	BlobHeader      hexutil.Bytes `json:"blobHeader"`
+	BatchMetadata   hexutil.Bytes `json:"batchMetadata"`
+	BlobCertificate hexutil.Bytes `json:"blobCertificate"`
+	BatchHeaderV2   hexutil.Bytes `json:"batchHeaderV2"`
+
+	HashBlobHeader         common.Hash `json:"hashBlobHeader"`
+	HashBatchHeader        common.Hash `json:"hashBatchHeader"`
+	HashReducedBatchHeader common.Hash `json:"hashReducedBatchHeader"`
+	HashBatchMetadata      common.Hash `json:"hashBatchMetadata"`
+	HashBlobHeaderV2       common.Hash `json:"hashBlobHeaderV2"`
+	HashBlobCertificate    common.Hash `json:"hashBlobCertificate"`
+	HashBatchHeaderV2      common.Hash `json:"hashBatchHeaderV2"`
+}
+
+var (
+	batchMetadataType = mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "batchHeader", Type: "tuple", Components: []abi.ArgumentMarshaling{
+			{Name: "blobHeadersRoot", Type: "bytes32"},
+			{Name: "quorumNumbers", Type: "bytes"},
+			{Name: "signedStakeForQuorums", Type: "bytes"},
+			{Name: "referenceBlockNumber", Type: "uint32"},
+		}},
+		{Name: "signatoryRecordHash", Type: "bytes32"},
+		{Name: "confirmationBlockNumber", Type: "uint32"},
+	})
+
+	blobCertificateType = mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "blobHeader", Type: "tuple", Components: []abi.ArgumentMarshaling{
+			{Name: "version", Type: "uint16"},
+			{Name: "quorumNumbers", Type: "bytes"},
+			{Name: "commitment", Type: "tuple", Components: []abi.ArgumentMarshaling{
+				{Name: "commitment", Type: "tuple", Components: g1PointComponents},
+				{Name: "lengthCommitment", Type: "tuple", Components: g2PointComponents},
+				{Name: "lengthProof", Type: "tuple", Components: g2PointComponents},
+				{Name: "length", Type: "uint32"},
+			}},
+			{Name: "paymentHeaderHash", Type: "bytes32"},
+		}},
+		{Name: "signature", Type: "bytes"},
+		{Name: "relayKeys", Type: "uint32[]"},
+	})
+)
+
+func loadGoldenVectors(t *testing.T) goldenVectors {
+	t.Helper()
+	data, err := os.ReadFile("testdata/golden_vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors goldenVectors
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+// decodeGolden decodes the abi.encode output of a golden struct into T and checks that encoding
+// it again gives back the same bytes, so the Go types lay out the struct as Solidity does.
+func decodeGolden[T any](t *testing.T, typ abi.Type, encoded []byte) T {
+	t.Helper()
+	args := abi.Arguments{{Type: typ}}
+	values, err := args.Unpack(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, ok := abi.ConvertType(values[0], new(T)).(*T)
+	if !ok {
+		t.Fatalf("cannot convert %T", values[0])
+	}
+	reencoded, err := args.Pack(*decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reencoded, encoded) {
+		t.Fatalf("re-encoding differs from the golden encoding\n got %x\nwant %x", reencoded, encoded)
+	}
+	return *decoded
+}
+
+func checkHash(t *testing.T, name string, got [32]byte, err error, want common.Hash) {
+	t.Helper()
+	if err != nil {
+		t.Errorf("%s: %v", name, err)
+		return
+	}
+	if common.Hash(got) != want {
+		t.Errorf("%s = %s, want %s", name, common.Hash(got), want)
+	}
+}
+
+func TestGoldenVectorsV1(t *testing.T) {
+	vectors := loadGoldenVectors(t)
+
+	blobHeader := decodeGolden[structs.BlobHeader](t, blobHeaderType, vectors.BlobHeader)
+	batchMetadata := decodeGolden[structs.BatchMetadata](t, batchMetadataType, vectors.BatchMetadata)
+
+	hash, err := HashBlobHeader(blobHeader)
+	checkHash(t, "HashBlobHeader", hash, err, vectors.HashBlobHeader)
+	hash, err = HashBatchHeader(batchMetadata.BatchHeader)
+	checkHash(t, "HashBatchHeader", hash, err, vectors.HashBatchHeader)
+	hash, err = HashBatchHeaderToReducedBatchHeader(batchMetadata.BatchHeader)
+	checkHash(t, "HashBatchHeaderToReducedBatchHeader", hash, err, vectors.HashReducedBatchHeader)
+	hash, err = HashReducedBatchHeader(ConvertBatchHeaderToReducedBatchHeader(batchMetadata.BatchHeader))
+	checkHash(t, "HashReducedBatchHeader", hash, err, vectors.HashReducedBatchHeader)
+	hash, err = HashBatchMetadata(batchMetadata)
+	checkHash(t, "HashBatchMetadata", hash, err, vectors.HashBatchMetadata)
+
+	batchHeaderHash, err := HashBatchHeader(batchMetadata.BatchHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash = HashBatchHashedMetadata(batchHeaderHash, batchMetadata.SignatoryRecordHash, batchMetadata.ConfirmationBlockNumber)
+	checkHash(t, "HashBatchHashedMetadata", hash, nil, vectors.HashBatchMetadata)
+	hash = HashBatchHashedMetadataFromConfirmationData(batchHeaderHash, batchMetadata.SignatoryRecordHash[:], batchMetadata.ConfirmationBlockNumber)
+	checkHash(t, "HashBatchHashedMetadataFromConfirmationData", hash, nil, vectors.HashBatchMetadata)
+}
+
+func TestGoldenVectorsV2(t *testing.T) {
+	vectors := loadGoldenVectors(t)
+
+	blobCertificate := decodeGolden[structs.BlobCertificate](t, blobCertificateType, vectors.BlobCertificate)
+	batchHeader := decodeGolden[structs.BatchHeaderV2](t, batchHeaderV2Type, vectors.BatchHeaderV2)
+
+	hash, err := HashBlobHeaderV2(blobCertificate.BlobHeader)
+	checkHash(t, "HashBlobHeaderV2", hash, err, vectors.HashBlobHeaderV2)
+	hash, err = HashBlobCertificate(blobCertificate)
+	checkHash(t, "HashBlobCertificate", hash, err, vectors.HashBlobCertificate)
+	hash, err = HashBatchHeaderV2(batchHeader)
+	checkHash(t, "HashBatchHeaderV2", hash, err, vectors.HashBatchHeaderV2)
+}
@@ -0,0 +1,243 @@
+// Package indexer follows the BatchConfirmed events of EigenDAServiceManager into a local store,
+// so V1 batch metadata can be looked up by batch id without scanning logs.
+//
+// The indexer polls rather than subscribes: every pass first checks that the last processed block
+// is still canonical, rolling the store back to the common ancestor if it is not, and then
+// filters the logs of the next range of blocks. Progress is persisted with the indexed batches,
+// so a restarted indexer resumes where it stopped.
+//
+// BatchMetadata rebuilds the full V1 batch metadata of an indexed batch from its confirmBatch
+// calldata, which is what verifyDACertV1 needs alongside the blob inclusion proof, and checks it
+// against the hash the ServiceManager stored for the batch.
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	contractEigenDAServiceManager "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+)
+
+// ChainReader is the subset of an ethclient the indexer reads the chain through.
+type ChainReader interface {
+	bind.ContractFilterer
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Config tunes the indexer. Zero values fall back to DefaultConfig.
+type Config struct {
+	// StartBlock is the first block scanned when the store is empty, usually the deployment block
+	// of the ServiceManager.
+	StartBlock uint64
+	// Confirmations is how many blocks the indexer stays behind the head.
+	Confirmations uint64
+	// BlockRange is the most blocks filtered in one eth_getLogs request.
+	BlockRange uint64
+	// MaxReorgDepth is how far back block hashes are kept to find the common ancestor after a
+	// reorg. A deeper reorg makes the indexer start over from StartBlock.
+	MaxReorgDepth uint64
+	// PollInterval is how long Run waits for new blocks once it has caught up.
+	PollInterval time.Duration
+	Logger       *slog.Logger
+}
+
+// DefaultConfig returns the defaults used for unset Config fields.
+func DefaultConfig() Config {
+	return Config{
+		BlockRange:    2000,
+		MaxReorgDepth: 128,
+		PollInterval:  12 * time.Second,
+		Logger:        slog.Default(),
+	}
+}
+
+// Indexer indexes the BatchConfirmed events of one EigenDAServiceManager into a Store.
+type Indexer struct {
+	client   ChainReader
+	filterer *contractEigenDAServiceManager.ContractEigenDAServiceManagerFilterer
+	store    *Store
+	config   Config
+}
+
+// New returns an indexer of the ServiceManager at serviceManager. It does not own store.
+func New(client ChainReader, serviceManager common.Address, store *Store, config Config) (*Indexer, error) {
+	filterer, err := contractEigenDAServiceManager.NewContractEigenDAServiceManagerFilterer(serviceManager, client)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := DefaultConfig()
+	if config.BlockRange == 0 {
+		config.BlockRange = defaults.BlockRange
+	}
+	if config.MaxReorgDepth == 0 {
+		config.MaxReorgDepth = defaults.MaxReorgDepth
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.Logger == nil {
+		config.Logger = defaults.Logger
+	}
+
+	return &Indexer{
+		client:   client,
+		filterer: filterer,
+		store:    store,
+		config:   config,
+	}, nil
+}
+
+// Run indexes until ctx is done, syncing every PollInterval. Failed passes are logged and retried.
+func (ix *Indexer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(ix.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := ix.Sync(ctx); err != nil && ctx.Err() == nil {
+			ix.config.Logger.Warn("BatchConfirmed indexing failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync indexes up to the confirmed head and returns.
+func (ix *Indexer) Sync(ctx context.Context) error {
+	for {
+		caughtUp, err := ix.step(ctx)
+		if err != nil || caughtUp {
+			return err
+		}
+	}
+}
+
+// step handles a pending reorg or indexes the next block range. It reports whether the store
+// has caught up with the confirmed head.
+func (ix *Indexer) step(ctx context.Context) (bool, error) {
+	cursor, ok, err := ix.store.Cursor()
+	if err != nil {
+		return false, err
+	}
+
+	from := ix.config.StartBlock
+	if ok {
+		canonical, err := ix.client.HeaderByNumber(ctx, new(big.Int).SetUint64(cursor.Number))
+		if err != nil {
+			return false, err
+		}
+		if canonical.Hash() != cursor.Hash {
+			return false, ix.rollback(ctx)
+		}
+		from = cursor.Number + 1
+	}
+
+	head, err := ix.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	if head.Number.Uint64() < ix.config.Confirmations {
+		return true, nil
+	}
+	confirmedHead := head.Number.Uint64() - ix.config.Confirmations
+	if from > confirmedHead {
+		return true, nil
+	}
+	to := min(confirmedHead, from+ix.config.BlockRange-1)
+
+	end, err := ix.client.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+	if err != nil {
+		return false, err
+	}
+	batches, err := ix.filter(ctx, from, to)
+	if err != nil {
+		return false, err
+	}
+	// Only commit the range if neither it nor the cursor it extends was reorged since the cursor
+	// was checked; the next step rolls back or retries.
+	recheck, err := ix.client.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+	if err != nil {
+		return false, err
+	}
+	if recheck.Hash() != end.Hash() {
+		return false, nil
+	}
+	if ok {
+		canonical, err := ix.client.HeaderByNumber(ctx, new(big.Int).SetUint64(cursor.Number))
+		if err != nil {
+			return false, err
+		}
+		if canonical.Hash() != cursor.Hash {
+			return false, nil
+		}
+	}
+
+	var pruneBelow uint64
+	if to > ix.config.MaxReorgDepth {
+		pruneBelow = to - ix.config.MaxReorgDepth
+	}
+	if err := ix.store.advance(batches, Cursor{Number: to, Hash: end.Hash()}, pruneBelow); err != nil {
+		return false, err
+	}
+	if len(batches) > 0 {
+		ix.config.Logger.Debug("Indexed confirmed batches", "from", from, "to", to, "count", len(batches))
+	}
+	return to == confirmedHead, nil
+}
+
+func (ix *Indexer) filter(ctx context.Context, from, to uint64) ([]*Batch, error) {
+	iter, err := ix.filterer.FilterBatchConfirmed(&bind.FilterOpts{Start: from, End: &to, Context: ctx}, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var batches []*Batch
+	for iter.Next() {
+		if iter.Event.Raw.Removed {
+			continue
+		}
+		batches = append(batches, BatchFromEvent(iter.Event))
+	}
+	return batches, iter.Error()
+}
+
+// rollback rewinds the store to the newest recorded block that is still canonical.
+func (ix *Indexer) rollback(ctx context.Context) error {
+	checkpoints, err := ix.store.checkpoints()
+	if err != nil {
+		return err
+	}
+	for _, checkpoint := range checkpoints {
+		canonical, err := ix.client.HeaderByNumber(ctx, new(big.Int).SetUint64(checkpoint.Number))
+		if errors.Is(err, ethereum.NotFound) {
+			// the chain is now shorter than this checkpoint
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if canonical.Hash() == checkpoint.Hash {
+			ix.config.Logger.Warn("Chain reorg, rolling back indexed batches", "ancestor", checkpoint.Number)
+			return ix.store.rollback(checkpoint)
+		}
+	}
+
+	ix.config.Logger.Warn("Chain reorg deeper than the recorded block hashes, reindexing",
+		"maxReorgDepth", ix.config.MaxReorgDepth, "startBlock", ix.config.StartBlock)
+	if err := ix.store.reset(); err != nil {
+		return fmt.Errorf("resetting store: %w", err)
+	}
+	return nil
+}
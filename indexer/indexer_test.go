@@ -0,0 +1,347 @@
+package indexer_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+
+	"github.com/Layr-Labs/eigenda/contracts/indexer"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+var testConfig = indexer.Config{
+	MaxReorgDepth: 16,
+	Logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+}
+
+func openStore(t *testing.T, path string) *indexer.Store {
+	t.Helper()
+	store, err := indexer.OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func newIndexer(t *testing.T, f *fixture.Fixture, store *indexer.Store, config indexer.Config) *indexer.Indexer {
+	t.Helper()
+	ix, err := indexer.New(f.Client, f.Addresses.ServiceManager, store, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ix
+}
+
+// confirmBatch confirms a batch referencing the latest block and returns its id and metadata.
+func confirmBatch(t *testing.T, f *fixture.Fixture, root byte) (uint32, structs.BatchMetadata) {
+	t.Helper()
+	referenceBlock, err := f.Client.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Backend.Commit()
+	batchID, batchMetadata, err := f.ConfirmBatch(structs.BatchHeader{
+		BlobHeadersRoot:       [32]byte{root},
+		QuorumNumbers:         []byte{0, 1},
+		SignedStakeForQuorums: []byte{100, 100},
+		ReferenceBlockNumber:  uint32(referenceBlock),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return batchID, batchMetadata
+}
+
+func blockHash(t *testing.T, f *fixture.Fixture, number uint64) common.Hash {
+	t.Helper()
+	header, err := f.Client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(number))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return header.Hash()
+}
+
+// checkBatch checks that the store holds batchID as confirmed on the canonical chain.
+func checkBatch(t *testing.T, f *fixture.Fixture, store *indexer.Store, batchID uint32, batchMetadata structs.BatchMetadata) {
+	t.Helper()
+	batch, err := store.Batch(batchID)
+	if err != nil {
+		t.Fatalf("batch %d: %v", batchID, err)
+	}
+	if batch.BlockNumber != uint64(batchMetadata.ConfirmationBlockNumber) {
+		t.Errorf("batch %d indexed at block %d, confirmed at %d", batchID, batch.BlockNumber, batchMetadata.ConfirmationBlockNumber)
+	}
+	if canonical := blockHash(t, f, batch.BlockNumber); batch.BlockHash != canonical {
+		t.Errorf("batch %d indexed in block %s, canonical block is %s", batchID, batch.BlockHash, canonical)
+	}
+
+	got, err := indexer.BatchMetadata(context.Background(), f.Client, f.Addresses.ServiceManager, batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, batchMetadata) {
+		t.Errorf("BatchMetadata = %+v, want %+v", got, batchMetadata)
+	}
+}
+
+func checkCursor(t *testing.T, f *fixture.Fixture, store *indexer.Store) indexer.Cursor {
+	t.Helper()
+	cursor, ok, err := store.Cursor()
+	if err != nil || !ok {
+		t.Fatalf("Cursor = %v, %v", ok, err)
+	}
+	head, err := f.Client.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor.Number != head || cursor.Hash != blockHash(t, f, head) {
+		t.Errorf("cursor = %+v, want head %d %s", cursor, head, blockHash(t, f, head))
+	}
+	return cursor
+}
+
+func TestSyncReorg(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	store := openStore(t, t.TempDir())
+	ix := newIndexer(t, f, store, testConfig)
+
+	keptID, keptMetadata := confirmBatch(t, f, 1)
+	if err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ancestor := checkCursor(t, f, store)
+
+	orphanedID, orphanedMetadata := confirmBatch(t, f, 2)
+	f.Backend.Commit()
+	if err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	checkBatch(t, f, store, orphanedID, orphanedMetadata)
+	orphanedHeight := checkCursor(t, f, store).Number
+
+	// fork out the block that confirmed the second batch with a longer chain of empty blocks
+	if err := f.Backend.Fork(ancestor.Hash); err != nil {
+		t.Fatal(err)
+	}
+	for head := ancestor.Number; head <= orphanedHeight; head++ {
+		f.Backend.Commit()
+	}
+	if batch, err := store.Batch(orphanedID); err != nil || batch.BlockHash == blockHash(t, f, batch.BlockNumber) {
+		t.Fatalf("batch %d was not orphaned: %+v, %v", orphanedID, batch, err)
+	}
+
+	if err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	checkCursor(t, f, store)
+	checkBatch(t, f, store, keptID, keptMetadata)
+
+	// the fork may include the orphaned confirmBatch transaction again, in a different block
+	if batch, err := store.Batch(orphanedID); err == nil {
+		if batch.BlockHash != blockHash(t, f, batch.BlockNumber) {
+			t.Errorf("batch %d still indexed in orphaned block %s", orphanedID, batch.BlockHash)
+		}
+	} else if !errors.Is(err, indexer.ErrNotFound) {
+		t.Fatal(err)
+	}
+	latest, err := store.LatestBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.BatchID < keptID {
+		t.Errorf("LatestBatch = %d, want at least %d", latest.BatchID, keptID)
+	}
+}
+
+// headHookClient runs onHead, once, before the first head lookup after it is set.
+type headHookClient struct {
+	simulated.Client
+	onHead func()
+}
+
+func (c *headHookClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil && c.onHead != nil {
+		onHead := c.onHead
+		c.onHead = nil
+		onHead()
+	}
+	return c.Client.HeaderByNumber(ctx, number)
+}
+
+// TestSyncReorgDuringStep reorgs the chain below the cursor after a step has checked the cursor
+// but before it fetches the next range, which must not be indexed on top of the orphaned cursor.
+func TestSyncReorgDuringStep(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	store := openStore(t, t.TempDir())
+	client := &headHookClient{Client: f.Client}
+	ix, err := indexer.New(client, f.Addresses.ServiceManager, store, testConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ancestor, err := f.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orphanedID, orphanedMetadata := confirmBatch(t, f, 1)
+	if err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	checkBatch(t, f, store, orphanedID, orphanedMetadata)
+	orphanedHeight := checkCursor(t, f, store).Number
+
+	client.onHead = func() {
+		if err := f.Backend.Fork(ancestor.Hash()); err != nil {
+			t.Error(err)
+		}
+		for head := ancestor.Number.Uint64(); head <= orphanedHeight+2; head++ {
+			f.Backend.Commit()
+		}
+	}
+	if err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if client.onHead != nil {
+		t.Fatal("the chain was not reorged during the sync")
+	}
+	checkCursor(t, f, store)
+	if batch, err := store.Batch(orphanedID); err == nil {
+		if batch.BlockHash != blockHash(t, f, batch.BlockNumber) {
+			t.Errorf("batch %d still indexed in orphaned block %s", orphanedID, batch.BlockHash)
+		}
+	} else if !errors.Is(err, indexer.ErrNotFound) {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncDeepReorg(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	store := openStore(t, t.TempDir())
+	config := testConfig
+	config.BlockRange = 4
+	config.MaxReorgDepth = 2
+	ix := newIndexer(t, f, store, config)
+
+	start, err := f.Client.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ancestor := blockHash(t, f, start)
+	batchID, _ := confirmBatch(t, f, 1)
+	for i := 0; i < 8; i++ {
+		f.Backend.Commit()
+	}
+	if err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	height := checkCursor(t, f, store).Number
+
+	// every recorded block hash is orphaned, so the indexer starts over
+	if err := f.Backend.Fork(ancestor); err != nil {
+		t.Fatal(err)
+	}
+	for head := start; head <= height; head++ {
+		f.Backend.Commit()
+	}
+	if err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	checkCursor(t, f, store)
+	if batch, err := store.Batch(batchID); err == nil && batch.BlockHash != blockHash(t, f, batch.BlockNumber) {
+		t.Errorf("batch %d still indexed in orphaned block %s", batchID, batch.BlockHash)
+	}
+}
+
+func TestSyncRestart(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "indexer")
+
+	store, err := indexer.OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstID, firstMetadata := confirmBatch(t, f, 1)
+	if err := newIndexer(t, f, store, testConfig).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	checkpoint := checkCursor(t, f, store)
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	secondID, secondMetadata := confirmBatch(t, f, 2)
+
+	store = openStore(t, path)
+	cursor, ok, err := store.Cursor()
+	if err != nil || !ok || cursor != checkpoint {
+		t.Fatalf("reopened cursor = %+v, %v, %v, want %+v", cursor, ok, err, checkpoint)
+	}
+	checkBatch(t, f, store, firstID, firstMetadata)
+
+	// a start block past the head shows that the restarted indexer resumes from the checkpoint
+	config := testConfig
+	config.StartBlock = uint64(secondMetadata.ConfirmationBlockNumber) + 1
+	if err := newIndexer(t, f, store, config).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	checkCursor(t, f, store)
+	checkBatch(t, f, store, firstID, firstMetadata)
+	checkBatch(t, f, store, secondID, secondMetadata)
+
+	latest, err := store.LatestBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.BatchID != secondID {
+		t.Errorf("LatestBatch = %d, want %d", latest.BatchID, secondID)
+	}
+}
+
+func TestBatchMetadataMismatch(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	store := openStore(t, t.TempDir())
+
+	firstID, _ := confirmBatch(t, f, 1)
+	secondID, secondMetadata := confirmBatch(t, f, 2)
+	f.Backend.Commit()
+	if err := newIndexer(t, f, store, testConfig).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	checkBatch(t, f, store, secondID, secondMetadata)
+	batch, err := store.Batch(secondID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*indexer.Batch)
+	}{
+		{name: "other batch id", modify: func(b *indexer.Batch) { b.BatchID = firstID }},
+		{name: "later block", modify: func(b *indexer.Batch) { b.BlockNumber++ }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			modified := *batch
+			tc.modify(&modified)
+			if _, err := indexer.BatchMetadata(ctx, f.Client, f.Addresses.ServiceManager, &modified); !errors.Is(err, indexer.ErrMetadataHashMismatch) {
+				t.Errorf("BatchMetadata = %v, want %v", err, indexer.ErrMetadataHashMismatch)
+			}
+		})
+	}
+}
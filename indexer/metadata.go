@@ -0,0 +1,114 @@
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	contractEigenDAServiceManager "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	"github.com/Layr-Labs/eigenda/contracts/hashing"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/verification"
+)
+
+// ErrNotConfirmBatch is returned when a batch's transaction does not call confirmBatch.
+var ErrNotConfirmBatch = errors.New("transaction is not a confirmBatch call")
+
+// ErrMetadataHashMismatch is returned when the reconstructed batch metadata does not hash to what
+// the ServiceManager stored for the batch.
+var ErrMetadataHashMismatch = errors.New("batch metadata does not match batchIdToBatchMetadataHash")
+
+// TransactionReader is the subset of an ethclient needed to read confirmBatch calldata and the
+// stored batch metadata hash.
+type TransactionReader interface {
+	bind.ContractCaller
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error)
+}
+
+// BatchMetadata reconstructs the BatchMetadata whose hash confirmBatch stored in
+// batchIdToBatchMetadataHash for batch.
+//
+// confirmBatch requires tx.origin == msg.sender, so the batch header and the non-signers are
+// always the calldata of the transaction that emitted BatchConfirmed. The signatoryRecordHash is
+// recomputed from the non-signer pubkeys and the confirmation block is the block of the event.
+// The result is checked against batchIdToBatchMetadataHash of the ServiceManager at serviceManager
+// as of the confirmation block, returning an error wrapping ErrMetadataHashMismatch if they differ.
+func BatchMetadata(ctx context.Context, client TransactionReader, serviceManager common.Address, batch *Batch) (structs.BatchMetadata, error) {
+	tx, _, err := client.TransactionByHash(ctx, batch.TxHash)
+	if err != nil {
+		return structs.BatchMetadata{}, fmt.Errorf("fetching transaction %s of batch %d: %w", batch.TxHash, batch.BatchID, err)
+	}
+	batchHeader, params, err := DecodeConfirmBatch(tx.Data())
+	if err != nil {
+		return structs.BatchMetadata{}, fmt.Errorf("transaction %s of batch %d: %w", batch.TxHash, batch.BatchID, err)
+	}
+
+	reducedBatchHeaderHash, err := hashing.HashBatchHeaderToReducedBatchHeader(batchHeader)
+	if err != nil {
+		return structs.BatchMetadata{}, err
+	}
+	if reducedBatchHeaderHash != batch.BatchHeaderHash {
+		return structs.BatchMetadata{}, fmt.Errorf("transaction %s confirms reduced batch header %x, not the %x of batch %d",
+			batch.TxHash, reducedBatchHeaderHash, batch.BatchHeaderHash, batch.BatchID)
+	}
+	if batch.BlockNumber > uint64(^uint32(0)) {
+		return structs.BatchMetadata{}, fmt.Errorf("confirmation block %d of batch %d overflows uint32", batch.BlockNumber, batch.BatchID)
+	}
+
+	batchMetadata := structs.BatchMetadata{
+		BatchHeader:             batchHeader,
+		SignatoryRecordHash:     verification.SignatoryRecordHash(batchHeader.ReferenceBlockNumber, params.NonSignerPubkeys),
+		ConfirmationBlockNumber: uint32(batch.BlockNumber),
+	}
+	batchMetadataHash, err := hashing.HashBatchMetadata(batchMetadata)
+	if err != nil {
+		return structs.BatchMetadata{}, err
+	}
+
+	caller, err := contractEigenDAServiceManager.NewContractEigenDAServiceManagerCaller(serviceManager, client)
+	if err != nil {
+		return structs.BatchMetadata{}, err
+	}
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(batch.BlockNumber)}
+	storedHash, err := caller.BatchIdToBatchMetadataHash(opts, batch.BatchID)
+	if err != nil {
+		return structs.BatchMetadata{}, fmt.Errorf("fetching metadata hash of batch %d: %w", batch.BatchID, err)
+	}
+	if storedHash != batchMetadataHash {
+		return structs.BatchMetadata{}, fmt.Errorf("%w: batch %d stores %x, reconstructed %x",
+			ErrMetadataHashMismatch, batch.BatchID, storedHash, batchMetadataHash)
+	}
+	return batchMetadata, nil
+}
+
+// DecodeConfirmBatch decodes the arguments of a confirmBatch call from its calldata.
+func DecodeConfirmBatch(data []byte) (structs.BatchHeader, structs.NonSignerStakesAndSignature, error) {
+	parsed, err := contractEigenDAServiceManager.ContractEigenDAServiceManagerMetaData.GetAbi()
+	if err != nil {
+		return structs.BatchHeader{}, structs.NonSignerStakesAndSignature{}, err
+	}
+	method := parsed.Methods["confirmBatch"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return structs.BatchHeader{}, structs.NonSignerStakesAndSignature{}, ErrNotConfirmBatch
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return structs.BatchHeader{}, structs.NonSignerStakesAndSignature{}, fmt.Errorf("decoding confirmBatch calldata: %w", err)
+	}
+
+	batchHeader, err := structs.Convert[structs.BatchHeader](args[0])
+	if err != nil {
+		return structs.BatchHeader{}, structs.NonSignerStakesAndSignature{}, err
+	}
+	params, err := structs.Convert[structs.NonSignerStakesAndSignature](args[1])
+	if err != nil {
+		return structs.BatchHeader{}, structs.NonSignerStakesAndSignature{}, err
+	}
+	return batchHeader, params, nil
+}
@@ -0,0 +1,230 @@
+package indexer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	contractEigenDAServiceManager "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+)
+
+// ErrNotFound is returned when the store has no batch with the requested id.
+var ErrNotFound = errors.New("batch not found")
+
+// Key prefixes of the store. Numbers are big-endian so that keys sort numerically.
+var (
+	batchPrefix      = []byte("b") // b<batchId uint32> -> Batch as JSON
+	checkpointPrefix = []byte("h") // h<blockNumber uint64> -> block hash
+	cursorKey        = []byte("c") // c -> Cursor
+)
+
+// Batch is a BatchConfirmed event of EigenDAServiceManager.
+type Batch struct {
+	BatchID uint32 `json:"batchId"`
+	// BatchHeaderHash is the hash carried by the event, which confirmBatch computes over the
+	// reduced batch header the operators signed.
+	BatchHeaderHash common.Hash `json:"batchHeaderHash"`
+	// BlockNumber is the confirmation block number recorded in the batch metadata.
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+	TxHash      common.Hash `json:"txHash"`
+	LogIndex    uint        `json:"logIndex"`
+}
+
+// BatchFromEvent returns the batch confirmed by event.
+func BatchFromEvent(event *contractEigenDAServiceManager.ContractEigenDAServiceManagerBatchConfirmed) *Batch {
+	return &Batch{
+		BatchID:         event.BatchId,
+		BatchHeaderHash: event.BatchHeaderHash,
+		BlockNumber:     event.Raw.BlockNumber,
+		BlockHash:       event.Raw.BlockHash,
+		TxHash:          event.Raw.TxHash,
+		LogIndex:        event.Raw.Index,
+	}
+}
+
+// Cursor is the last block the indexer has processed.
+type Cursor struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// Store persists indexed batches in a LevelDB database, together with the hashes of the
+// processed blocks needed to detect reorgs after a restart.
+type Store struct {
+	db *leveldb.DB
+}
+
+// OpenStore opens or creates the store at path.
+func OpenStore(path string) (*Store, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Batch returns the batch with the given id, or ErrNotFound.
+func (s *Store) Batch(batchID uint32) (*Batch, error) {
+	data, err := s.db.Get(batchKey(batchID), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var batch Batch
+	if err := json.Unmarshal(data, &batch); err != nil {
+		return nil, fmt.Errorf("decoding batch %d: %w", batchID, err)
+	}
+	return &batch, nil
+}
+
+// LatestBatch returns the batch with the highest id, or ErrNotFound if no batch is indexed.
+func (s *Store) LatestBatch() (*Batch, error) {
+	iter := s.db.NewIterator(util.BytesPrefix(batchPrefix), nil)
+	defer iter.Release()
+	if !iter.Last() {
+		if err := iter.Error(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotFound
+	}
+	var batch Batch
+	if err := json.Unmarshal(iter.Value(), &batch); err != nil {
+		return nil, fmt.Errorf("decoding batch: %w", err)
+	}
+	return &batch, nil
+}
+
+// Cursor returns the last processed block, if any.
+func (s *Store) Cursor() (Cursor, bool, error) {
+	data, err := s.db.Get(cursorKey, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return Cursor{}, false, nil
+	}
+	if err != nil {
+		return Cursor{}, false, err
+	}
+	if len(data) != 8+common.HashLength {
+		return Cursor{}, false, fmt.Errorf("corrupt cursor of length %d", len(data))
+	}
+	return Cursor{
+		Number: binary.BigEndian.Uint64(data),
+		Hash:   common.BytesToHash(data[8:]),
+	}, true, nil
+}
+
+// checkpoints returns the recorded block hashes from the highest block number down.
+func (s *Store) checkpoints() ([]Cursor, error) {
+	iter := s.db.NewIterator(util.BytesPrefix(checkpointPrefix), nil)
+	defer iter.Release()
+
+	var checkpoints []Cursor
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		checkpoints = append(checkpoints, Cursor{
+			Number: binary.BigEndian.Uint64(iter.Key()[len(checkpointPrefix):]),
+			Hash:   common.BytesToHash(iter.Value()),
+		})
+	}
+	return checkpoints, iter.Error()
+}
+
+// advance atomically records batches and the hashes of the blocks they were found in, and
+// moves the cursor. Checkpoints below pruneBelow are dropped.
+func (s *Store) advance(batches []*Batch, cursor Cursor, pruneBelow uint64) error {
+	update := new(leveldb.Batch)
+	for _, batch := range batches {
+		data, err := json.Marshal(batch)
+		if err != nil {
+			return err
+		}
+		update.Put(batchKey(batch.BatchID), data)
+		update.Put(checkpointKey(batch.BlockNumber), batch.BlockHash.Bytes())
+	}
+	update.Put(checkpointKey(cursor.Number), cursor.Hash.Bytes())
+	update.Put(cursorKey, cursorValue(cursor))
+
+	iter := s.db.NewIterator(&util.Range{Start: checkpointKey(0), Limit: checkpointKey(pruneBelow)}, nil)
+	for iter.Next() {
+		update.Delete(append([]byte(nil), iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	return s.db.Write(update, nil)
+}
+
+// rollback atomically removes every batch and checkpoint above cursor and moves the cursor
+// back to it.
+func (s *Store) rollback(cursor Cursor) error {
+	update := new(leveldb.Batch)
+
+	// batch ids grow with the block number, so the orphaned batches are the highest ids
+	batches := s.db.NewIterator(util.BytesPrefix(batchPrefix), nil)
+	for ok := batches.Last(); ok; ok = batches.Prev() {
+		var batch Batch
+		if err := json.Unmarshal(batches.Value(), &batch); err != nil {
+			batches.Release()
+			return fmt.Errorf("decoding batch: %w", err)
+		}
+		if batch.BlockNumber <= cursor.Number {
+			break
+		}
+		update.Delete(append([]byte(nil), batches.Key()...))
+	}
+	batches.Release()
+	if err := batches.Error(); err != nil {
+		return err
+	}
+
+	checkpoints := s.db.NewIterator(&util.Range{Start: checkpointKey(cursor.Number + 1), Limit: util.BytesPrefix(checkpointPrefix).Limit}, nil)
+	for checkpoints.Next() {
+		update.Delete(append([]byte(nil), checkpoints.Key()...))
+	}
+	checkpoints.Release()
+	if err := checkpoints.Error(); err != nil {
+		return err
+	}
+
+	update.Put(cursorKey, cursorValue(cursor))
+	return s.db.Write(update, nil)
+}
+
+// reset removes everything, for reindexing from the start block.
+func (s *Store) reset() error {
+	update := new(leveldb.Batch)
+	iter := s.db.NewIterator(nil, nil)
+	for iter.Next() {
+		update.Delete(append([]byte(nil), iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return s.db.Write(update, nil)
+}
+
+func batchKey(batchID uint32) []byte {
+	return binary.BigEndian.AppendUint32(append([]byte(nil), batchPrefix...), batchID)
+}
+
+func checkpointKey(number uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte(nil), checkpointPrefix...), number)
+}
+
+func cursorValue(cursor Cursor) []byte {
+	return append(binary.BigEndian.AppendUint64(nil, cursor.Number), cursor.Hash.Bytes()...)
+}
@@ -0,0 +1,66 @@
+package kzg
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+const (
+	// BytesPerSymbol is the size of a blob symbol, one field element.
+	BytesPerSymbol = 32
+	// BytesPerPayloadSymbol is the payload carried by a symbol once EncodePayload has zeroed its
+	// first byte.
+	BytesPerPayloadSymbol = BytesPerSymbol - 1
+)
+
+// ErrInvalidSymbol is returned for blob symbols that are not canonical field elements, which
+// EigenDA rejects.
+var ErrInvalidSymbol = errors.New("blob symbol is not a valid field element")
+
+// EncodePayload encodes payload as a blob the way EigenDA clients do: each 31 bytes of payload
+// follow a zero byte in a 32 byte symbol, so every symbol is below the field modulus. The last
+// symbol is zero padded.
+func EncodePayload(payload []byte) []byte {
+	numSymbols := (len(payload) + BytesPerPayloadSymbol - 1) / BytesPerPayloadSymbol
+	blob := make([]byte, numSymbols*BytesPerSymbol)
+	for i := 0; i < numSymbols; i++ {
+		copy(blob[i*BytesPerSymbol+1:(i+1)*BytesPerSymbol], payload[i*BytesPerPayloadSymbol:])
+	}
+	return blob
+}
+
+// DecodePayload reverses EncodePayload, returning length bytes of payload. The padding of the
+// last symbol cannot be told from payload, so the length must be known.
+func DecodePayload(blob []byte, length int) ([]byte, error) {
+	numSymbols := (len(blob) + BytesPerSymbol - 1) / BytesPerSymbol
+	if length > numSymbols*BytesPerPayloadSymbol {
+		return nil, fmt.Errorf("blob of %d bytes cannot hold %d bytes of payload", len(blob), length)
+	}
+	payload := make([]byte, 0, numSymbols*BytesPerPayloadSymbol)
+	for i := 0; i < numSymbols; i++ {
+		symbol := blob[i*BytesPerSymbol : min((i+1)*BytesPerSymbol, len(blob))]
+		if symbol[0] != 0 {
+			return nil, fmt.Errorf("symbol %d does not start with a zero byte", i)
+		}
+		payload = append(payload, symbol[1:]...)
+	}
+	return payload[:length], nil
+}
+
+// ToFieldElements splits blob into 32 byte big endian symbols, zero padding the last, and returns
+// them as the coefficients of the blob polynomial.
+func ToFieldElements(blob []byte) ([]fr.Element, error) {
+	numSymbols := (len(blob) + BytesPerSymbol - 1) / BytesPerSymbol
+	elements := make([]fr.Element, numSymbols)
+	var symbol [BytesPerSymbol]byte
+	for i := range elements {
+		symbol = [BytesPerSymbol]byte{}
+		copy(symbol[:], blob[i*BytesPerSymbol:])
+		if err := elements[i].SetBytesCanonical(symbol[:]); err != nil {
+			return nil, fmt.Errorf("%w: symbol %d", ErrInvalidSymbol, i)
+		}
+	}
+	return elements, nil
+}
@@ -0,0 +1,129 @@
+// Package kzg computes the KZG commitments EigenDA certifies blobs with and the opening proofs
+// MockRollup checks in challengeCommitment. Blobs are read as polynomials in coefficient form, one
+// coefficient per 32 byte symbol, and committed to with the G1 powers of an SRS. Opening proofs are
+// in G2, as EigenDARollupUtils.openCommitment expects. The length commitments and proofs of V2
+// BlobCommitments bound the degree of the blob polynomial with the top powers of the SRS.
+package kzg
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Layr-Labs/eigenda/contracts/bls"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// ErrSRSTooSmall is returned for polynomials with more coefficients than the SRS has powers for.
+var ErrSRSTooSmall = errors.New("polynomial degree exceeds the SRS")
+
+// Commit returns the commitment to the polynomial with coefficients coeffs, sum coeffs[i] G1[i],
+// as carried in BlobHeader.commitment and BlobCommitment.commitment.
+func Commit(srs *SRS, coeffs []fr.Element) (structs.G1Point, error) {
+	if len(coeffs) > len(srs.G1) {
+		return structs.G1Point{}, fmt.Errorf("%w: %d coefficients, %d G1 points", ErrSRSTooSmall, len(coeffs), len(srs.G1))
+	}
+	var commitment bn254.G1Affine
+	if len(coeffs) > 0 {
+		if _, err := commitment.MultiExp(srs.G1[:len(coeffs)], coeffs, ecc.MultiExpConfig{}); err != nil {
+			return structs.G1Point{}, err
+		}
+	}
+	return bls.G1Point(&commitment), nil
+}
+
+// CommitBlob returns the commitment to blob, read with ToFieldElements.
+func CommitBlob(srs *SRS, blob []byte) (structs.G1Point, error) {
+	coeffs, err := ToFieldElements(blob)
+	if err != nil {
+		return structs.G1Point{}, err
+	}
+	return Commit(srs, coeffs)
+}
+
+// Evaluate returns the value of the polynomial with coefficients coeffs at point.
+func Evaluate(coeffs []fr.Element, point *big.Int) *big.Int {
+	var z, value fr.Element
+	z.SetBigInt(point)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		value.Mul(&value, &z).Add(&value, &coeffs[i])
+	}
+	return value.BigInt(new(big.Int))
+}
+
+// OpenG2 returns the G2 proof that the polynomial with coefficients coeffs evaluates to evaluation
+// at point, the commitment to the quotient (p(x) - p(point)) / (x - point) in the G2 powers of srs.
+// It takes one G2 point fewer than the polynomial has coefficients.
+func OpenG2(srs *SRS, coeffs []fr.Element, point *big.Int) (proof structs.G2Point, evaluation *big.Int, err error) {
+	if len(coeffs) > len(srs.G2)+1 {
+		return structs.G2Point{}, nil, fmt.Errorf("%w: %d coefficients, %d G2 points", ErrSRSTooSmall, len(coeffs), len(srs.G2))
+	}
+
+	var z fr.Element
+	z.SetBigInt(point)
+
+	// synthetic division by (x - z): the quotient coefficients are the partial Horner sums, and
+	// the last sum is p(z)
+	var quotient []fr.Element
+	var value fr.Element
+	if len(coeffs) > 0 {
+		quotient = make([]fr.Element, len(coeffs)-1)
+		value = coeffs[len(coeffs)-1]
+		for i := len(coeffs) - 2; i >= 0; i-- {
+			quotient[i] = value
+			value.Mul(&value, &z).Add(&value, &coeffs[i])
+		}
+	}
+
+	var proofPoint bn254.G2Affine
+	if len(quotient) > 0 {
+		if _, err := proofPoint.MultiExp(srs.G2[:len(quotient)], quotient, ecc.MultiExpConfig{}); err != nil {
+			return structs.G2Point{}, nil, err
+		}
+	}
+	return bls.G2Point(&proofPoint), value.BigInt(new(big.Int)), nil
+}
+
+// VerifyOpening mirrors EigenDARollupUtils.openCommitment, checking
+// e(tau - point [1]_1, proof) == e(commitment - evaluation [1]_1, [1]_2). It returns false for
+// invalid points.
+func VerifyOpening(
+	point *big.Int,
+	evaluation *big.Int,
+	tau structs.G1Point,
+	commitment structs.G1Point,
+	proof structs.G2Point,
+) bool {
+	tauPoint, ok := bls.G1Affine(tau)
+	if !ok {
+		return false
+	}
+	commitmentPoint, ok := bls.G1Affine(commitment)
+	if !ok {
+		return false
+	}
+	proofPoint, ok := bls.G2Affine(proof)
+	if !ok {
+		return false
+	}
+
+	_, _, g1Gen, g2Gen := bn254.Generators()
+	var negG1Gen bn254.G1Affine
+	negG1Gen.Neg(&g1Gen)
+
+	var lhs, rhs bn254.G1Affine
+	lhs.ScalarMultiplication(&negG1Gen, point)
+	lhs.Add(&tauPoint, &lhs)
+	rhs.ScalarMultiplication(&negG1Gen, evaluation)
+	rhs.Add(&commitmentPoint, &rhs)
+
+	var negG2Gen bn254.G2Affine
+	negG2Gen.Neg(&g2Gen)
+
+	valid, err := bn254.PairingCheck([]bn254.G1Affine{lhs, rhs}, []bn254.G2Affine{proofPoint, negG2Gen})
+	return err == nil && valid
+}
@@ -0,0 +1,136 @@
+package kzg_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Layr-Labs/eigenda/contracts/kzg"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+var testSecret = big.NewInt(0x5ec2e7)
+
+// testCoeffs returns n deterministic coefficients spread over the whole field.
+func testCoeffs(n int) []fr.Element {
+	coeffs := make([]fr.Element, n)
+	for i := range coeffs {
+		coeffs[i].SetBytes(crypto.Keccak256(big.NewInt(int64(i)).Bytes()))
+	}
+	return coeffs
+}
+
+func TestOpenG2(t *testing.T) {
+	srs := kzg.NewInsecureSRS(testSecret, 32, 32)
+	tau, err := srs.Tau()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := kzg.NewInsecureSRS(big.NewInt(2), 32, 32)
+	otherTau, err := other.Tau()
+	if err != nil {
+		t.Fatal(err)
+	}
+	largePoint := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+
+	for _, n := range []int{0, 1, 2, 7, 32, 33} {
+		coeffs := testCoeffs(n)
+		commitment, err := kzg.Commit(srs, coeffs)
+		if n > 32 {
+			if !errors.Is(err, kzg.ErrSRSTooSmall) {
+				t.Errorf("%d coefficients: Commit = %v, want %v", n, err, kzg.ErrSRSTooSmall)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, point := range []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(5), largePoint} {
+			proof, evaluation, err := kzg.OpenG2(srs, coeffs, point)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := kzg.Evaluate(coeffs, point); evaluation.Cmp(want) != 0 {
+				t.Errorf("%d coefficients at %s: evaluation %s, Evaluate %s", n, point, evaluation, want)
+			}
+			if !kzg.VerifyOpening(point, evaluation, tau, commitment, proof) {
+				t.Errorf("%d coefficients at %s: opening does not verify", n, point)
+			}
+
+			wrongEvaluation := new(big.Int).Add(evaluation, big.NewInt(1))
+			if kzg.VerifyOpening(point, wrongEvaluation, tau, commitment, proof) {
+				t.Errorf("%d coefficients at %s: opening verifies with evaluation %s", n, point, wrongEvaluation)
+			}
+			// a constant polynomial opens to the same value everywhere with the zero proof, whatever the setup
+			if n <= 1 {
+				continue
+			}
+			if kzg.VerifyOpening(new(big.Int).Add(point, big.NewInt(1)), evaluation, tau, commitment, proof) {
+				t.Errorf("%d coefficients at %s: opening verifies at another point", n, point)
+			}
+			if kzg.VerifyOpening(point, evaluation, otherTau, commitment, proof) {
+				t.Errorf("%d coefficients at %s: opening verifies with another setup", n, point)
+			}
+		}
+	}
+
+	// an opening of one polynomial does not verify against another's commitment
+	coeffs := testCoeffs(4)
+	proof, evaluation, err := kzg.OpenG2(srs, coeffs, big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCommitment, err := kzg.Commit(srs, testCoeffs(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kzg.VerifyOpening(big.NewInt(3), evaluation, tau, otherCommitment, proof) {
+		t.Error("opening verifies against the commitment to another polynomial")
+	}
+
+	if _, _, err := kzg.OpenG2(srs, testCoeffs(34), big.NewInt(3)); !errors.Is(err, kzg.ErrSRSTooSmall) {
+		t.Errorf("OpenG2 of 34 coefficients with 32 G2 points = %v, want %v", err, kzg.ErrSRSTooSmall)
+	}
+}
+
+func TestVerifyOpeningInvalidPoints(t *testing.T) {
+	srs := kzg.NewInsecureSRS(testSecret, 8, 8)
+	tau, err := srs.Tau()
+	if err != nil {
+		t.Fatal(err)
+	}
+	coeffs := testCoeffs(8)
+	commitment, err := kzg.Commit(srs, coeffs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, evaluation, err := kzg.OpenG2(srs, coeffs, big.NewInt(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notOnCurve := structs.G1Point{X: big.NewInt(1), Y: big.NewInt(1)}
+	g2NotOnCurve := structs.G2Point{X: [2]*big.Int{big.NewInt(1), big.NewInt(1)}, Y: [2]*big.Int{big.NewInt(1), big.NewInt(1)}}
+	for _, tc := range []struct {
+		name       string
+		tau        structs.G1Point
+		commitment structs.G1Point
+		proof      structs.G2Point
+	}{
+		{name: "tau", tau: notOnCurve, commitment: commitment, proof: proof},
+		{name: "commitment", tau: tau, commitment: notOnCurve, proof: proof},
+		{name: "proof", tau: tau, commitment: commitment, proof: g2NotOnCurve},
+	} {
+		if kzg.VerifyOpening(big.NewInt(9), evaluation, tc.tau, tc.commitment, tc.proof) {
+			t.Errorf("opening verifies with an invalid %s", tc.name)
+		}
+	}
+
+	if _, err := kzg.NewInsecureSRS(testSecret, 1, 1).Tau(); !errors.Is(err, kzg.ErrSRSTooSmall) {
+		t.Errorf("Tau of a one point setup = %v, want %v", err, kzg.ErrSRSTooSmall)
+	}
+}
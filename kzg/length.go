@@ -0,0 +1,183 @@
+package kzg
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Layr-Labs/eigenda/contracts/bls"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// Errors returned when checking the length fields of a BlobCommitment.
+var (
+	ErrLengthNotPowerOfTwo      = errors.New("blob length is not a power of two")
+	ErrBlobLongerThanLength     = errors.New("blob has more symbols than its length")
+	ErrInvalidPoint             = errors.New("point is not on the curve")
+	ErrLengthProofInvalid       = errors.New("length proof is invalid")
+	ErrLengthCommitmentMismatch = errors.New("length commitment does not match the commitment")
+)
+
+// BlobLength returns the length EigenDA records for a blob of numSymbols symbols, the next power
+// of two.
+func BlobLength(numSymbols uint64) uint32 {
+	if numSymbols <= 1 {
+		return 1
+	}
+	return 1 << bits.Len64(numSymbols-1)
+}
+
+// CommitLength returns the length commitment of the polynomial with coefficients coeffs, its
+// commitment in G2, sum coeffs[i] G2[i].
+func CommitLength(srs *SRS, coeffs []fr.Element) (structs.G2Point, error) {
+	if len(coeffs) > len(srs.G2) {
+		return structs.G2Point{}, fmt.Errorf("%w: %d coefficients, %d G2 points", ErrSRSTooSmall, len(coeffs), len(srs.G2))
+	}
+	var lengthCommitment bn254.G2Affine
+	if len(coeffs) > 0 {
+		if _, err := lengthCommitment.MultiExp(srs.G2[:len(coeffs)], coeffs, ecc.MultiExpConfig{}); err != nil {
+			return structs.G2Point{}, err
+		}
+	}
+	return bls.G2Point(&lengthCommitment), nil
+}
+
+// ProveLength returns the proof that the polynomial with coefficients coeffs has degree below
+// length: its commitment shifted up by Order-length powers, s^(Order-length) p(s) [1]_2. Only
+// polynomials of degree below length fit under the top of the setup once shifted.
+func ProveLength(srs *SRS, coeffs []fr.Element, length uint32) (structs.G2Point, error) {
+	if err := checkLength(srs, length); err != nil {
+		return structs.G2Point{}, err
+	}
+	if uint64(len(coeffs)) > uint64(length) {
+		return structs.G2Point{}, fmt.Errorf("%w: %d symbols, length %d", ErrBlobLongerThanLength, len(coeffs), length)
+	}
+	shiftedPowers, ok := srs.g2Powers(srs.Order-uint64(length), uint64(len(coeffs)))
+	if !ok {
+		return structs.G2Point{}, fmt.Errorf("%w: no G2 powers from %d", ErrSRSTooSmall, srs.Order-uint64(length))
+	}
+	var lengthProof bn254.G2Affine
+	if len(coeffs) > 0 {
+		if _, err := lengthProof.MultiExp(shiftedPowers, coeffs, ecc.MultiExpConfig{}); err != nil {
+			return structs.G2Point{}, err
+		}
+	}
+	return bls.G2Point(&lengthProof), nil
+}
+
+// ComputeBlobCommitment returns the BlobCommitment of blob, read with ToFieldElements, as a
+// disperser fills it in: the commitment, the length commitment and a proof of BlobLength.
+func ComputeBlobCommitment(srs *SRS, blob []byte) (structs.BlobCommitment, error) {
+	coeffs, err := ToFieldElements(blob)
+	if err != nil {
+		return structs.BlobCommitment{}, err
+	}
+	commitment, err := Commit(srs, coeffs)
+	if err != nil {
+		return structs.BlobCommitment{}, err
+	}
+	lengthCommitment, err := CommitLength(srs, coeffs)
+	if err != nil {
+		return structs.BlobCommitment{}, err
+	}
+	length := BlobLength(uint64(len(coeffs)))
+	lengthProof, err := ProveLength(srs, coeffs, length)
+	if err != nil {
+		return structs.BlobCommitment{}, err
+	}
+	return structs.BlobCommitment{
+		Commitment:       commitment,
+		LengthCommitment: lengthCommitment,
+		LengthProof:      lengthProof,
+		Length:           length,
+	}, nil
+}
+
+// VerifyLengthProof checks that lengthProof proves the polynomial committed to by lengthCommitment
+// has degree below length, e(s^(Order-length) [1]_1, lengthCommitment) == e([1]_1, lengthProof).
+func VerifyLengthProof(srs *SRS, lengthCommitment, lengthProof structs.G2Point, length uint32) error {
+	if err := checkLength(srs, length); err != nil {
+		return err
+	}
+	challenge, ok := srs.g1Powers(srs.Order-uint64(length), 1)
+	if !ok {
+		return fmt.Errorf("%w: no G1 power %d", ErrSRSTooSmall, srs.Order-uint64(length))
+	}
+	lengthCommitmentPoint, ok := bls.G2Affine(lengthCommitment)
+	if !ok {
+		return fmt.Errorf("%w: length commitment", ErrInvalidPoint)
+	}
+	lengthProofPoint, ok := bls.G2Affine(lengthProof)
+	if !ok {
+		return fmt.Errorf("%w: length proof", ErrInvalidPoint)
+	}
+
+	_, _, g1Gen, _ := bn254.Generators()
+	var negG1Gen bn254.G1Affine
+	negG1Gen.Neg(&g1Gen)
+
+	valid, err := bn254.PairingCheck(
+		[]bn254.G1Affine{challenge[0], negG1Gen},
+		[]bn254.G2Affine{lengthCommitmentPoint, lengthProofPoint},
+	)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrLengthProofInvalid
+	}
+	return nil
+}
+
+// VerifyCommitmentEquivalence checks that commitment and lengthCommitment commit to the same
+// polynomial, e(commitment, [1]_2) == e([1]_1, lengthCommitment).
+func VerifyCommitmentEquivalence(commitment structs.G1Point, lengthCommitment structs.G2Point) error {
+	commitmentPoint, ok := bls.G1Affine(commitment)
+	if !ok {
+		return fmt.Errorf("%w: commitment", ErrInvalidPoint)
+	}
+	lengthCommitmentPoint, ok := bls.G2Affine(lengthCommitment)
+	if !ok {
+		return fmt.Errorf("%w: length commitment", ErrInvalidPoint)
+	}
+
+	_, _, g1Gen, g2Gen := bn254.Generators()
+	var negG1Gen bn254.G1Affine
+	negG1Gen.Neg(&g1Gen)
+
+	valid, err := bn254.PairingCheck(
+		[]bn254.G1Affine{commitmentPoint, negG1Gen},
+		[]bn254.G2Affine{g2Gen, lengthCommitmentPoint},
+	)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrLengthCommitmentMismatch
+	}
+	return nil
+}
+
+// VerifyBlobCommitment checks the length fields of blobCommitment: that the length proof holds for
+// the claimed length, and that the length commitment is to the same polynomial as the commitment.
+// None of this is checked on chain, so a cert can be rejected on it before verifying anything else.
+func VerifyBlobCommitment(srs *SRS, blobCommitment structs.BlobCommitment) error {
+	if err := VerifyLengthProof(srs, blobCommitment.LengthCommitment, blobCommitment.LengthProof, blobCommitment.Length); err != nil {
+		return err
+	}
+	return VerifyCommitmentEquivalence(blobCommitment.Commitment, blobCommitment.LengthCommitment)
+}
+
+func checkLength(srs *SRS, length uint32) error {
+	if length == 0 || length&(length-1) != 0 {
+		return fmt.Errorf("%w: %d", ErrLengthNotPowerOfTwo, length)
+	}
+	if uint64(length) > srs.Order {
+		return fmt.Errorf("%w: length %d, order %d", ErrSRSTooSmall, length, srs.Order)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package kzg_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda/contracts/kzg"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+func TestBlobLength(t *testing.T) {
+	for numSymbols, want := range map[uint64]uint32{
+		0: 1, 1: 1, 2: 2, 3: 4, 4: 4, 5: 8, 1000: 1024, 1024: 1024, 1025: 2048, 1 << 28: 1 << 28,
+	} {
+		if got := kzg.BlobLength(numSymbols); got != want {
+			t.Errorf("BlobLength(%d) = %d, want %d", numSymbols, got, want)
+		}
+	}
+}
+
+func TestLengthProof(t *testing.T) {
+	srs := kzg.NewInsecureSRS(testSecret, 64, 64)
+
+	for _, tc := range []struct {
+		n      int
+		length uint32
+	}{
+		{n: 0, length: 1},
+		{n: 1, length: 1},
+		{n: 1, length: 2},
+		{n: 3, length: 4},
+		{n: 4, length: 4},
+		{n: 5, length: 16},
+		{n: 64, length: 64},
+	} {
+		coeffs := testCoeffs(tc.n)
+		lengthCommitment, err := kzg.CommitLength(srs, coeffs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lengthProof, err := kzg.ProveLength(srs, coeffs, tc.length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := kzg.VerifyLengthProof(srs, lengthCommitment, lengthProof, tc.length); err != nil {
+			t.Errorf("%d coefficients, length %d: VerifyLengthProof = %v", tc.n, tc.length, err)
+		}
+
+		// the zero polynomial has every degree, so only the others are checked at other lengths
+		if tc.n == 0 {
+			continue
+		}
+		for _, length := range []uint32{tc.length / 2, tc.length * 2} {
+			if length == 0 || length > 64 {
+				continue
+			}
+			if err := kzg.VerifyLengthProof(srs, lengthCommitment, lengthProof, length); !errors.Is(err, kzg.ErrLengthProofInvalid) {
+				t.Errorf("%d coefficients, proof of length %d checked at %d: VerifyLengthProof = %v, want %v",
+					tc.n, tc.length, length, err, kzg.ErrLengthProofInvalid)
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		n       int
+		length  uint32
+		wantErr error
+	}{
+		{name: "longer than length", n: 5, length: 4, wantErr: kzg.ErrBlobLongerThanLength},
+		{name: "zero length", n: 0, length: 0, wantErr: kzg.ErrLengthNotPowerOfTwo},
+		{name: "length not a power of two", n: 3, length: 3, wantErr: kzg.ErrLengthNotPowerOfTwo},
+		{name: "length above the order", n: 3, length: 128, wantErr: kzg.ErrSRSTooSmall},
+	} {
+		if _, err := kzg.ProveLength(srs, testCoeffs(tc.n), tc.length); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: ProveLength = %v, want %v", tc.name, err, tc.wantErr)
+		}
+		if tc.wantErr == kzg.ErrBlobLongerThanLength {
+			continue
+		}
+		var zero structs.G2Point
+		if err := kzg.VerifyLengthProof(srs, zero, zero, tc.length); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: VerifyLengthProof = %v, want %v", tc.name, err, tc.wantErr)
+		}
+	}
+
+	lengthCommitment, err := kzg.CommitLength(srs, testCoeffs(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lengthProof, err := kzg.ProveLength(srs, testCoeffs(4), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notOnCurve := structs.G2Point{X: [2]*big.Int{big.NewInt(1), big.NewInt(1)}, Y: [2]*big.Int{big.NewInt(1), big.NewInt(1)}}
+	if err := kzg.VerifyLengthProof(srs, notOnCurve, lengthProof, 4); !errors.Is(err, kzg.ErrInvalidPoint) {
+		t.Errorf("invalid length commitment: VerifyLengthProof = %v, want %v", err, kzg.ErrInvalidPoint)
+	}
+	if err := kzg.VerifyLengthProof(srs, lengthCommitment, notOnCurve, 4); !errors.Is(err, kzg.ErrInvalidPoint) {
+		t.Errorf("invalid length proof: VerifyLengthProof = %v, want %v", err, kzg.ErrInvalidPoint)
+	}
+	if _, err := kzg.CommitLength(srs, testCoeffs(65)); !errors.Is(err, kzg.ErrSRSTooSmall) {
+		t.Errorf("CommitLength of 65 coefficients with 64 G2 points = %v, want %v", err, kzg.ErrSRSTooSmall)
+	}
+}
+
+// TestLengthProofTrailing checks that a setup holding only its first and last powers proves and
+// verifies lengths as the full setup does.
+func TestLengthProofTrailing(t *testing.T) {
+	full := kzg.NewInsecureSRS(testSecret, 64, 64)
+	trailing := &kzg.SRS{
+		G1:         full.G1[:16],
+		G2:         full.G2[:16],
+		Order:      64,
+		G1Trailing: full.G1[48:],
+		G2Trailing: full.G2[48:],
+	}
+
+	for _, length := range []uint32{1, 2, 4, 8, 16} {
+		coeffs := testCoeffs(int(length))
+		lengthCommitment, err := kzg.CommitLength(trailing, coeffs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lengthProof, err := kzg.ProveLength(trailing, coeffs, length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := kzg.ProveLength(full, coeffs, length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lengthProof.X[0].Cmp(want.X[0]) != 0 || lengthProof.X[1].Cmp(want.X[1]) != 0 ||
+			lengthProof.Y[0].Cmp(want.Y[0]) != 0 || lengthProof.Y[1].Cmp(want.Y[1]) != 0 {
+			t.Errorf("length %d: proof %v, full setup %v", length, lengthProof, want)
+		}
+		if err := kzg.VerifyLengthProof(trailing, lengthCommitment, lengthProof, length); err != nil {
+			t.Errorf("length %d: VerifyLengthProof = %v", length, err)
+		}
+	}
+
+	// length 32 needs the powers from 32, in neither part of the setup
+	if _, err := kzg.ProveLength(trailing, testCoeffs(4), 32); !errors.Is(err, kzg.ErrSRSTooSmall) {
+		t.Errorf("ProveLength of length 32 = %v, want %v", err, kzg.ErrSRSTooSmall)
+	}
+	var zero structs.G2Point
+	if err := kzg.VerifyLengthProof(trailing, zero, zero, 32); !errors.Is(err, kzg.ErrSRSTooSmall) {
+		t.Errorf("VerifyLengthProof of length 32 = %v, want %v", err, kzg.ErrSRSTooSmall)
+	}
+}
+
+func TestVerifyBlobCommitment(t *testing.T) {
+	srs := kzg.NewInsecureSRS(testSecret, 64, 64)
+
+	for _, size := range []int{0, 1, 31, 32, 100, 31 * 64} {
+		payload := bytes.Repeat([]byte{0xab}, size)
+		blobCommitment, err := kzg.ComputeBlobCommitment(srs, kzg.EncodePayload(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		numSymbols := uint64((size + kzg.BytesPerPayloadSymbol - 1) / kzg.BytesPerPayloadSymbol)
+		if want := kzg.BlobLength(numSymbols); blobCommitment.Length != want {
+			t.Errorf("%d byte payload: length %d, want %d", size, blobCommitment.Length, want)
+		}
+		if err := kzg.VerifyBlobCommitment(srs, blobCommitment); err != nil {
+			t.Errorf("%d byte payload: VerifyBlobCommitment = %v", size, err)
+		}
+	}
+
+	blobCommitment, err := kzg.ComputeBlobCommitment(srs, kzg.EncodePayload([]byte("blob")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := kzg.ComputeBlobCommitment(srs, kzg.EncodePayload([]byte("another blob")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	notOnCurve := structs.G1Point{X: big.NewInt(1), Y: big.NewInt(1)}
+
+	for _, tc := range []struct {
+		name    string
+		modify  func(c *structs.BlobCommitment)
+		wantErr error
+	}{
+		{
+			name:    "longer length",
+			modify:  func(c *structs.BlobCommitment) { c.Length = 2 },
+			wantErr: kzg.ErrLengthProofInvalid,
+		},
+		{
+			name:    "length proof of another blob",
+			modify:  func(c *structs.BlobCommitment) { c.LengthProof = other.LengthProof },
+			wantErr: kzg.ErrLengthProofInvalid,
+		},
+		{
+			name: "length commitment to another blob",
+			modify: func(c *structs.BlobCommitment) {
+				c.LengthCommitment, c.LengthProof = other.LengthCommitment, other.LengthProof
+			},
+			wantErr: kzg.ErrLengthCommitmentMismatch,
+		},
+		{
+			name:    "commitment not on the curve",
+			modify:  func(c *structs.BlobCommitment) { c.Commitment = notOnCurve },
+			wantErr: kzg.ErrInvalidPoint,
+		},
+		{
+			name:    "length not a power of two",
+			modify:  func(c *structs.BlobCommitment) { c.Length = 3 },
+			wantErr: kzg.ErrLengthNotPowerOfTwo,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := blobCommitment
+			tc.modify(&c)
+			if err := kzg.VerifyBlobCommitment(srs, c); !errors.Is(err, tc.wantErr) {
+				t.Errorf("VerifyBlobCommitment = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+
+	invalid := kzg.EncodePayload([]byte("blob"))
+	invalid[0] = 0xff
+	if _, err := kzg.ComputeBlobCommitment(srs, invalid); !errors.Is(err, kzg.ErrInvalidSymbol) {
+		t.Errorf("ComputeBlobCommitment of a non-canonical symbol = %v, want %v", err, kzg.ErrInvalidSymbol)
+	}
+}
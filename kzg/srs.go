@@ -0,0 +1,179 @@
+package kzg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Layr-Labs/eigenda/contracts/bls"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// SRS holds the powers of the trusted setup secret s, G1[i] = s^i [1]_1 and G2[i] = s^i [1]_2.
+// Commitments take as many G1 points as the polynomial has coefficients, and G2 opening proofs
+// one fewer G2 point.
+//
+// Length proofs shift polynomials up to the top of the setup, so they also need its highest
+// powers: G1Trailing[i] and G2Trailing[i] are the powers Order-len(G1Trailing)+i and
+// Order-len(G2Trailing)+i. Setups small enough to hold in full can leave the trailing points empty.
+type SRS struct {
+	G1 []bn254.G1Affine
+	G2 []bn254.G2Affine
+
+	// Order is the number of powers in the setup, 2^28 for EigenDA's.
+	Order      uint64
+	G1Trailing []bn254.G1Affine
+	G2Trailing []bn254.G2Affine
+}
+
+// LoadSRS reads the first numG1 points of the G1 SRS file at g1Path and the first numG2 points of
+// the G2 SRS file at g2Path, of a setup with order powers. The files hold compressed points back to
+// back, in the format of the g1.point and g2.point files of the EigenDA setup.
+func LoadSRS(g1Path string, numG1 uint64, g2Path string, numG2 uint64, order uint64) (*SRS, error) {
+	g1, err := readPointsFile(g1Path, 0, numG1, bn254.SizeOfG1AffineCompressed, ReadG1Points)
+	if err != nil {
+		return nil, err
+	}
+	g2, err := readPointsFile(g2Path, 0, numG2, bn254.SizeOfG2AffineCompressed, ReadG2Points)
+	if err != nil {
+		return nil, err
+	}
+	return &SRS{G1: g1, G2: g2, Order: order}, nil
+}
+
+// LoadTrailing reads the last n points of the full G1 and G2 SRS files at g1Path and g2Path into
+// G1Trailing and G2Trailing. EigenDA also publishes the trailing G2 points on their own as
+// g2.trailing.point, which ReadG2Points reads directly.
+func (s *SRS) LoadTrailing(g1Path, g2Path string, n uint64) error {
+	if n > s.Order {
+		return fmt.Errorf("%w: %d trailing points, order %d", ErrSRSTooSmall, n, s.Order)
+	}
+	g1, err := readPointsFile(g1Path, s.Order-n, n, bn254.SizeOfG1AffineCompressed, ReadG1Points)
+	if err != nil {
+		return err
+	}
+	g2, err := readPointsFile(g2Path, s.Order-n, n, bn254.SizeOfG2AffineCompressed, ReadG2Points)
+	if err != nil {
+		return err
+	}
+	s.G1Trailing, s.G2Trailing = g1, g2
+	return nil
+}
+
+func readPointsFile[T any](
+	path string,
+	start, n uint64,
+	pointSize int,
+	read func(io.Reader, uint64) ([]T, error),
+) ([]T, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if _, err := file.Seek(int64(start)*int64(pointSize), io.SeekStart); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	points, err := read(bufio.NewReader(file), n)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return points, nil
+}
+
+// ReadG1Points reads n compressed G1 points from r.
+func ReadG1Points(r io.Reader, n uint64) ([]bn254.G1Affine, error) {
+	points := make([]bn254.G1Affine, n)
+	buf := make([]byte, bn254.SizeOfG1AffineCompressed)
+	for i := range points {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("reading G1 point %d: %w", i, err)
+		}
+		if _, err := points[i].SetBytes(buf); err != nil {
+			return nil, fmt.Errorf("decoding G1 point %d: %w", i, err)
+		}
+	}
+	return points, nil
+}
+
+// ReadG2Points reads n compressed G2 points from r.
+func ReadG2Points(r io.Reader, n uint64) ([]bn254.G2Affine, error) {
+	points := make([]bn254.G2Affine, n)
+	buf := make([]byte, bn254.SizeOfG2AffineCompressed)
+	for i := range points {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("reading G2 point %d: %w", i, err)
+		}
+		if _, err := points[i].SetBytes(buf); err != nil {
+			return nil, fmt.Errorf("decoding G2 point %d: %w", i, err)
+		}
+	}
+	return points, nil
+}
+
+// NewInsecureSRS returns numG1 G1 and numG2 G2 powers of a known secret, in a setup whose order is
+// the larger of the two. Anyone knowing secret can open commitments to any value, so it is only fit
+// for tests, like the s = 2 setup MockRollup is tested with.
+func NewInsecureSRS(secret *big.Int, numG1, numG2 uint64) *SRS {
+	_, _, g1Gen, g2Gen := bn254.Generators()
+	var s, power fr.Element
+	s.SetBigInt(secret)
+	power.SetOne()
+
+	srs := &SRS{
+		G1:    make([]bn254.G1Affine, numG1),
+		G2:    make([]bn254.G2Affine, numG2),
+		Order: max(numG1, numG2),
+	}
+	for i := uint64(0); i < max(numG1, numG2); i++ {
+		exponent := power.BigInt(new(big.Int))
+		if i < numG1 {
+			srs.G1[i].ScalarMultiplication(&g1Gen, exponent)
+		}
+		if i < numG2 {
+			srs.G2[i].ScalarMultiplication(&g2Gen, exponent)
+		}
+		power.Mul(&power, &s)
+	}
+	return srs
+}
+
+// Tau returns [s]_1, the power of tau MockRollup is deployed with to check openings.
+func (s *SRS) Tau() (structs.G1Point, error) {
+	if len(s.G1) < 2 {
+		return structs.G1Point{}, fmt.Errorf("%w: tau needs 2 G1 points, have %d", ErrSRSTooSmall, len(s.G1))
+	}
+	return bls.G1Point(&s.G1[1]), nil
+}
+
+// g1Powers returns the n G1 powers from start, from G1 or G1Trailing, or false if neither holds
+// them all.
+func (s *SRS) g1Powers(start, n uint64) ([]bn254.G1Affine, bool) {
+	return powers(s.G1, s.G1Trailing, s.Order, start, n)
+}
+
+// g2Powers returns the n G2 powers from start, from G2 or G2Trailing, or false if neither holds
+// them all.
+func (s *SRS) g2Powers(start, n uint64) ([]bn254.G2Affine, bool) {
+	return powers(s.G2, s.G2Trailing, s.Order, start, n)
+}
+
+func powers[T any](leading, trailing []T, order, start, n uint64) ([]T, bool) {
+	end := start + n
+	if end <= uint64(len(leading)) {
+		return leading[start:end], true
+	}
+	if uint64(len(trailing)) > order {
+		return nil, false
+	}
+	trailingStart := order - uint64(len(trailing))
+	if start >= trailingStart && end <= order {
+		return trailing[start-trailingStart : end-trailingStart], true
+	}
+	return nil, false
+}
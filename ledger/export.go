@@ -0,0 +1,93 @@
+package ledger
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	depositsHeader = []string{
+		"block_number", "block_time", "tx_hash", "log_index",
+		"account", "amount_wei", "total_deposit_wei",
+	}
+	reservationsHeader = []string{
+		"block_number", "block_time", "tx_hash", "log_index",
+		"account", "symbols_per_second", "start_time", "end_time", "quorum_numbers", "quorum_splits",
+	}
+)
+
+// WriteDepositsCSV writes deposits as CSV with a header row. Times are RFC 3339 in UTC and
+// amounts are in wei.
+func WriteDepositsCSV(w io.Writer, deposits []*Deposit) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(depositsHeader); err != nil {
+		return err
+	}
+	for _, deposit := range deposits {
+		record := append(eventColumns(deposit.Event),
+			deposit.Account.Hex(),
+			deposit.Amount.String(),
+			deposit.TotalDeposit.String(),
+		)
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteReservationsCSV writes reservation updates as CSV with a header row. Times are RFC 3339
+// in UTC and quorum numbers and splits are hex.
+func WriteReservationsCSV(w io.Writer, updates []*ReservationUpdate) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(reservationsHeader); err != nil {
+		return err
+	}
+	for _, update := range updates {
+		record := append(eventColumns(update.Event),
+			update.Account.Hex(),
+			strconv.FormatUint(update.SymbolsPerSecond, 10),
+			formatTime(update.StartTimestamp),
+			formatTime(update.EndTimestamp),
+			hexutil.Encode(update.QuorumNumbers),
+			hexutil.Encode(update.QuorumSplits),
+		)
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteJSON writes the timelines of every account as an indented JSON array ordered by address.
+// Timestamps are unix seconds and amounts are in wei.
+func (l *Ledger) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l.Accounts())
+}
+
+func eventColumns(event Event) []string {
+	return []string{
+		strconv.FormatUint(event.BlockNumber, 10),
+		formatTime(event.Timestamp),
+		event.TxHash.Hex(),
+		strconv.FormatUint(uint64(event.LogIndex), 10),
+	}
+}
+
+// formatTime formats a unix timestamp, falling back to the number for times RFC 3339 cannot
+// represent, such as the far-future end of an open-ended reservation.
+func formatTime(timestamp uint64) string {
+	if timestamp > 253402300799 { // 9999-12-31T23:59:59Z
+		return strconv.FormatUint(timestamp, 10)
+	}
+	return time.Unix(int64(timestamp), 0).UTC().Format(time.RFC3339)
+}
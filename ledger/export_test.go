@@ -0,0 +1,73 @@
+package ledger_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Layr-Labs/eigenda/contracts/ledger"
+)
+
+func TestWriteDepositsCSV(t *testing.T) {
+	deposits := []*ledger.Deposit{
+		{
+			Account:      alice,
+			Amount:       big.NewInt(100),
+			TotalDeposit: big.NewInt(100),
+			Event:        ledger.Event{BlockNumber: 7, Timestamp: 1700000000, TxHash: common.Hash{1}, LogIndex: 2},
+		},
+		{
+			Account:      alice,
+			Amount:       new(big.Int).Lsh(big.NewInt(1), 79),
+			TotalDeposit: new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 79), big.NewInt(100)),
+			Event:        ledger.Event{BlockNumber: 8, Timestamp: 1700000012, TxHash: common.Hash{2}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := ledger.WriteDepositsCSV(&buf, deposits); err != nil {
+		t.Fatal(err)
+	}
+	want := "block_number,block_time,tx_hash,log_index,account,amount_wei,total_deposit_wei\n" +
+		"7,2023-11-14T22:13:20Z," + common.Hash{1}.Hex() + ",2," + alice.Hex() + ",100,100\n" +
+		"8,2023-11-14T22:13:32Z," + common.Hash{2}.Hex() + ",0," + alice.Hex() + ",604462909807314587353088,604462909807314587353188\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteDepositsCSV =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteReservationsCSV(t *testing.T) {
+	updates := []*ledger.ReservationUpdate{
+		{
+			Account:          bob,
+			SymbolsPerSecond: 100,
+			StartTimestamp:   1700000000,
+			EndTimestamp:     1700086400,
+			QuorumNumbers:    []byte{0, 1},
+			QuorumSplits:     []byte{60, 40},
+			Event:            ledger.Event{BlockNumber: 7, Timestamp: 1699999999, TxHash: common.Hash{1}, LogIndex: 3},
+		},
+		{
+			// an open-ended reservation is past what RFC 3339 can represent
+			Account:          bob,
+			SymbolsPerSecond: 1,
+			EndTimestamp:     ^uint64(0),
+			QuorumNumbers:    []byte{},
+			QuorumSplits:     []byte{},
+			Event:            ledger.Event{BlockNumber: 9, Timestamp: 1700000100, TxHash: common.Hash{3}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := ledger.WriteReservationsCSV(&buf, updates); err != nil {
+		t.Fatal(err)
+	}
+	want := "block_number,block_time,tx_hash,log_index,account,symbols_per_second,start_time,end_time,quorum_numbers,quorum_splits\n" +
+		"7,2023-11-14T22:13:19Z," + common.Hash{1}.Hex() + ",3," + bob.Hex() + ",100,2023-11-14T22:13:20Z,2023-11-15T22:13:20Z,0x0001,0x3c28\n" +
+		"9,2023-11-14T22:15:00Z," + common.Hash{3}.Hex() + ",0," + bob.Hex() + ",1,1970-01-01T00:00:00Z,18446744073709551615,0x,0x\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteReservationsCSV =\n%s\nwant\n%s", got, want)
+	}
+}
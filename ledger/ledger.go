@@ -0,0 +1,402 @@
+// Package ledger replays the payment events of a PaymentVault into per-account timelines of
+// on-demand deposits and reservations, and answers the questions billing asks of them.
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	contractPaymentVault "github.com/Layr-Labs/eigenda/contracts/bindings/PaymentVault"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// ChainReader is the subset of an ethclient the ledger reads the chain through. Headers are
+// read for the timestamps of the blocks events were emitted in.
+type ChainReader interface {
+	bind.ContractFilterer
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Event locates a PaymentVault event on chain.
+type Event struct {
+	BlockNumber uint64 `json:"blockNumber"`
+	// Timestamp is the timestamp of the block.
+	Timestamp uint64      `json:"timestamp"`
+	TxHash    common.Hash `json:"txHash"`
+	LogIndex  uint        `json:"logIndex"`
+}
+
+// Deposit is an OnDemandPaymentUpdated event.
+type Deposit struct {
+	Account common.Address `json:"account"`
+	// Amount is the wei deposited and TotalDeposit the account's on-demand deposit after it.
+	Amount       *big.Int `json:"amount"`
+	TotalDeposit *big.Int `json:"totalDeposit"`
+	Event
+}
+
+// ReservationUpdate is a ReservationUpdated event. The new reservation replaces any previous one.
+type ReservationUpdate struct {
+	Account          common.Address `json:"account"`
+	SymbolsPerSecond uint64         `json:"symbolsPerSecond"`
+	StartTimestamp   uint64         `json:"startTimestamp"`
+	EndTimestamp     uint64         `json:"endTimestamp"`
+	QuorumNumbers    hexutil.Bytes  `json:"quorumNumbers"`
+	QuorumSplits     hexutil.Bytes  `json:"quorumSplits"`
+	Event
+}
+
+// Reservation returns the reservation set by the update.
+func (u *ReservationUpdate) Reservation() structs.Reservation {
+	return structs.Reservation{
+		SymbolsPerSecond: u.SymbolsPerSecond,
+		StartTimestamp:   u.StartTimestamp,
+		EndTimestamp:     u.EndTimestamp,
+		QuorumNumbers:    u.QuorumNumbers,
+		QuorumSplits:     u.QuorumSplits,
+	}
+}
+
+// ReservationState is the state of an account's reservation at a timestamp.
+type ReservationState int
+
+const (
+	// ReservationNone means no reservation had been set by then.
+	ReservationNone ReservationState = iota
+	// ReservationPending means the reservation had been set but had not started.
+	ReservationPending
+	// ReservationActive means the timestamp falls in [startTimestamp, endTimestamp).
+	ReservationActive
+	// ReservationExpired means the reservation had ended.
+	ReservationExpired
+)
+
+func (s ReservationState) String() string {
+	switch s {
+	case ReservationNone:
+		return "none"
+	case ReservationPending:
+		return "pending"
+	case ReservationActive:
+		return "active"
+	case ReservationExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// Account is the payment timeline of one account, in chain order.
+type Account struct {
+	Address      common.Address       `json:"address"`
+	Deposits     []*Deposit           `json:"deposits"`
+	Reservations []*ReservationUpdate `json:"reservations"`
+}
+
+// TotalDeposit returns the account's on-demand deposit after its last deposit.
+func (a *Account) TotalDeposit() *big.Int {
+	return a.TotalDepositAt(^uint64(0))
+}
+
+// TotalDepositAt returns the account's on-demand deposit as of timestamp.
+func (a *Account) TotalDepositAt(timestamp uint64) *big.Int {
+	total := new(big.Int)
+	for _, deposit := range a.Deposits {
+		if deposit.Timestamp > timestamp {
+			break
+		}
+		total.Set(deposit.TotalDeposit)
+	}
+	return total
+}
+
+// ReservationAt returns the reservation in effect at timestamp, which is the last one set in a
+// block no later than timestamp, and its state then.
+func (a *Account) ReservationAt(timestamp uint64) (*ReservationUpdate, ReservationState) {
+	var current *ReservationUpdate
+	for _, update := range a.Reservations {
+		if update.Timestamp > timestamp {
+			break
+		}
+		current = update
+	}
+	switch {
+	case current == nil:
+		return nil, ReservationNone
+	case timestamp < current.StartTimestamp:
+		return current, ReservationPending
+	case timestamp < current.EndTimestamp:
+		return current, ReservationActive
+	default:
+		return current, ReservationExpired
+	}
+}
+
+// Config tunes how a Ledger replays events. Zero values fall back to DefaultConfig.
+type Config struct {
+	// BlockRange is the most blocks filtered in one eth_getLogs request when replaying.
+	BlockRange uint64
+}
+
+// DefaultConfig returns the defaults used for unset Config fields.
+func DefaultConfig() Config {
+	return Config{BlockRange: 10_000}
+}
+
+func (c Config) withDefaults() Config {
+	if c.BlockRange == 0 {
+		c.BlockRange = DefaultConfig().BlockRange
+	}
+	return c
+}
+
+// Ledger accumulates the payment events of one PaymentVault. It is not safe for concurrent use.
+type Ledger struct {
+	client   ChainReader
+	filterer *contractPaymentVault.ContractPaymentVaultFilterer
+	config   Config
+
+	// nextBlock is the block after the last one replayed.
+	nextBlock    uint64
+	accounts     map[common.Address]*Account
+	deposits     []*Deposit
+	reservations []*ReservationUpdate
+}
+
+// New returns an empty ledger of the PaymentVault at paymentVault.
+func New(client ChainReader, paymentVault common.Address, config Config) (*Ledger, error) {
+	filterer, err := contractPaymentVault.NewContractPaymentVaultFilterer(paymentVault, client)
+	if err != nil {
+		return nil, err
+	}
+	return &Ledger{
+		client:   client,
+		filterer: filterer,
+		config:   config.withDefaults(),
+		accounts: make(map[common.Address]*Account),
+	}, nil
+}
+
+// Replay adds the events emitted from block start through end, or through the head if end is
+// nil, filtering at most BlockRange blocks at a time. Successive calls must cover consecutive
+// block ranges.
+//
+// The events of a range of blocks are only added once all of them have been fetched, so a failed
+// Replay leaves the ledger holding every block before NextBlock and none after; replaying again
+// from NextBlock picks up where it stopped.
+func (l *Ledger) Replay(ctx context.Context, start uint64, end *uint64) error {
+	last := uint64(0)
+	if end != nil {
+		last = *end
+	} else {
+		head, err := l.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return err
+		}
+		last = head.Number.Uint64()
+	}
+
+	for from := start; from <= last; {
+		to := min(last, from+l.config.BlockRange-1)
+		deposits, updates, err := l.fetch(ctx, from, to)
+		if err != nil {
+			return fmt.Errorf("replaying blocks %d to %d: %w", from, to, err)
+		}
+		for _, deposit := range deposits {
+			account := l.account(deposit.Account)
+			account.Deposits = append(account.Deposits, deposit)
+			l.deposits = append(l.deposits, deposit)
+		}
+		for _, update := range updates {
+			account := l.account(update.Account)
+			account.Reservations = append(account.Reservations, update)
+			l.reservations = append(l.reservations, update)
+		}
+		l.nextBlock = to + 1
+		if to == last {
+			break
+		}
+		from = to + 1
+	}
+	return nil
+}
+
+// NextBlock returns the block after the last one replayed, where the next Replay continues.
+func (l *Ledger) NextBlock() uint64 {
+	return l.nextBlock
+}
+
+// fetch returns the deposits and reservation updates emitted from block from through to, each in
+// chain order, without adding them to the ledger.
+func (l *Ledger) fetch(ctx context.Context, from, to uint64) ([]*Deposit, []*ReservationUpdate, error) {
+	opts := &bind.FilterOpts{Start: from, End: &to, Context: ctx}
+	timestamps := make(map[uint64]uint64)
+
+	var deposits []*Deposit
+	depositIter, err := l.filterer.FilterOnDemandPaymentUpdated(opts, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer depositIter.Close()
+	for depositIter.Next() {
+		if depositIter.Event.Raw.Removed {
+			continue
+		}
+		deposit, err := l.deposit(ctx, timestamps, depositIter.Event)
+		if err != nil {
+			return nil, nil, err
+		}
+		deposits = append(deposits, deposit)
+	}
+	if err := depositIter.Error(); err != nil {
+		return nil, nil, err
+	}
+
+	var updates []*ReservationUpdate
+	updateIter, err := l.filterer.FilterReservationUpdated(opts, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer updateIter.Close()
+	for updateIter.Next() {
+		if updateIter.Event.Raw.Removed {
+			continue
+		}
+		update, err := l.reservationUpdate(ctx, timestamps, updateIter.Event)
+		if err != nil {
+			return nil, nil, err
+		}
+		updates = append(updates, update)
+	}
+	if err := updateIter.Error(); err != nil {
+		return nil, nil, err
+	}
+	return deposits, updates, nil
+}
+
+// Account returns the timeline of address, if it has any events.
+func (l *Ledger) Account(address common.Address) (*Account, bool) {
+	account, ok := l.accounts[address]
+	return account, ok
+}
+
+// Accounts returns the timelines of every account, ordered by address.
+func (l *Ledger) Accounts() []*Account {
+	accounts := make([]*Account, 0, len(l.accounts))
+	for _, account := range l.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Address[:], accounts[j].Address[:]) < 0
+	})
+	return accounts
+}
+
+// Deposits returns every deposit in chain order.
+func (l *Ledger) Deposits() []*Deposit {
+	return l.deposits
+}
+
+// ReservationUpdates returns every reservation update in chain order.
+func (l *Ledger) ReservationUpdates() []*ReservationUpdate {
+	return l.reservations
+}
+
+// Expiring returns the reservations in effect at from, active or yet to start, that end no
+// later than to, ordered by end timestamp.
+func (l *Ledger) Expiring(from, to uint64) []*ReservationUpdate {
+	var expiring []*ReservationUpdate
+	for _, account := range l.Accounts() {
+		reservation, state := account.ReservationAt(from)
+		if state != ReservationActive && state != ReservationPending {
+			continue
+		}
+		if reservation.EndTimestamp <= to {
+			expiring = append(expiring, reservation)
+		}
+	}
+	sort.SliceStable(expiring, func(i, j int) bool {
+		return expiring[i].EndTimestamp < expiring[j].EndTimestamp
+	})
+	return expiring
+}
+
+// TopDepositors returns the n accounts with the largest on-demand deposits, largest first.
+// Accounts that never deposited are left out.
+func (l *Ledger) TopDepositors(n int) []*Account {
+	var depositors []*Account
+	for _, account := range l.Accounts() {
+		if len(account.Deposits) > 0 {
+			depositors = append(depositors, account)
+		}
+	}
+	sort.SliceStable(depositors, func(i, j int) bool {
+		return depositors[i].TotalDeposit().Cmp(depositors[j].TotalDeposit()) > 0
+	})
+	return depositors[:min(n, len(depositors))]
+}
+
+func (l *Ledger) deposit(ctx context.Context, timestamps map[uint64]uint64, event *contractPaymentVault.ContractPaymentVaultOnDemandPaymentUpdated) (*Deposit, error) {
+	located, err := l.locate(ctx, timestamps, event.Raw)
+	if err != nil {
+		return nil, err
+	}
+	return &Deposit{
+		Account:      event.Account,
+		Amount:       event.OnDemandPayment,
+		TotalDeposit: event.TotalDeposit,
+		Event:        located,
+	}, nil
+}
+
+func (l *Ledger) reservationUpdate(ctx context.Context, timestamps map[uint64]uint64, event *contractPaymentVault.ContractPaymentVaultReservationUpdated) (*ReservationUpdate, error) {
+	located, err := l.locate(ctx, timestamps, event.Raw)
+	if err != nil {
+		return nil, err
+	}
+	return &ReservationUpdate{
+		Account:          event.Account,
+		SymbolsPerSecond: event.Reservation.SymbolsPerSecond,
+		StartTimestamp:   event.Reservation.StartTimestamp,
+		EndTimestamp:     event.Reservation.EndTimestamp,
+		QuorumNumbers:    event.Reservation.QuorumNumbers,
+		QuorumSplits:     event.Reservation.QuorumSplits,
+		Event:            located,
+	}, nil
+}
+
+func (l *Ledger) account(address common.Address) *Account {
+	account, ok := l.accounts[address]
+	if !ok {
+		account = &Account{Address: address}
+		l.accounts[address] = account
+	}
+	return account
+}
+
+// locate returns where log was emitted, caching block timestamps in timestamps.
+func (l *Ledger) locate(ctx context.Context, timestamps map[uint64]uint64, log types.Log) (Event, error) {
+	timestamp, ok := timestamps[log.BlockNumber]
+	if !ok {
+		header, err := l.client.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+		if err != nil {
+			return Event{}, err
+		}
+		timestamp = header.Time
+		timestamps[log.BlockNumber] = timestamp
+	}
+	return Event{
+		BlockNumber: log.BlockNumber,
+		Timestamp:   timestamp,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+	}, nil
+}
@@ -0,0 +1,304 @@
+package ledger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Layr-Labs/eigenda/contracts/ledger"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+const week = 7 * 24 * 60 * 60
+
+var (
+	alice = common.HexToAddress("0xa11ce")
+	bob   = common.HexToAddress("0xb0b")
+	carol = common.HexToAddress("0xca201")
+)
+
+type vault struct {
+	t *testing.T
+	f *fixture.Fixture
+}
+
+func (v vault) deposit(account common.Address, amount int64) *types.Transaction {
+	v.t.Helper()
+	opts := v.f.TransactOpts(v.f.Owner)
+	opts.Value = big.NewInt(amount)
+	tx, err := v.f.PaymentVault.DepositOnDemand(opts, account)
+	if err != nil {
+		v.t.Fatal(err)
+	}
+	return tx
+}
+
+func (v vault) reserve(account common.Address, start, end uint64) *types.Transaction {
+	v.t.Helper()
+	tx, err := v.f.PaymentVault.SetReservation(v.f.TransactOpts(v.f.Owner), account, structs.Reservation{
+		SymbolsPerSecond: 100,
+		StartTimestamp:   start,
+		EndTimestamp:     end,
+		QuorumNumbers:    []byte{0, 1},
+		QuorumSplits:     []byte{50, 50},
+	})
+	if err != nil {
+		v.t.Fatal(err)
+	}
+	return tx
+}
+
+// mine commits txs in one block and returns its header.
+func (v vault) mine(txs ...*types.Transaction) *types.Header {
+	v.t.Helper()
+	for _, tx := range txs {
+		if err := v.f.Mine(tx); err != nil {
+			v.t.Fatal(err)
+		}
+	}
+	receipt, err := v.f.Client.TransactionReceipt(context.Background(), txs[0].Hash())
+	if err != nil {
+		v.t.Fatal(err)
+	}
+	for _, tx := range txs[1:] {
+		other, err := v.f.Client.TransactionReceipt(context.Background(), tx.Hash())
+		if err != nil {
+			v.t.Fatal(err)
+		}
+		if other.BlockNumber.Cmp(receipt.BlockNumber) != 0 {
+			v.t.Fatalf("transactions mined in blocks %v and %v", receipt.BlockNumber, other.BlockNumber)
+		}
+	}
+	header, err := v.f.Client.HeaderByNumber(context.Background(), receipt.BlockNumber)
+	if err != nil {
+		v.t.Fatal(err)
+	}
+	return header
+}
+
+func TestReplay(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	v := vault{t: t, f: f}
+
+	now, err := f.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := now.Time + 1000
+
+	// the first block sets a reservation between two deposits
+	txs := []*types.Transaction{
+		v.deposit(alice, 100),
+		v.reserve(bob, start, start+1000),
+		v.deposit(carol, 10),
+	}
+	first := v.mine(txs...)
+	second := v.mine(v.deposit(alice, 50), v.deposit(carol, 290), v.reserve(alice, first.Time, first.Time+2*week))
+	// bob's reservation is replaced by one that is already active
+	third := v.mine(v.reserve(bob, 0, first.Time+1000))
+
+	l, err := ledger.New(f.Client, f.Addresses.PaymentVault, ledger.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// two consecutive ranges, split between the first and second block
+	end := first.Number.Uint64()
+	if err := l.Replay(ctx, 0, &end); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Replay(ctx, end+1, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// chain order across both event types
+	var order []common.Address
+	for _, deposit := range l.Deposits() {
+		order = append(order, deposit.Account)
+	}
+	if want := []common.Address{alice, carol, alice, carol}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Deposits accounts = %v, want %v", order, want)
+	}
+	order = nil
+	for _, update := range l.ReservationUpdates() {
+		order = append(order, update.Account)
+	}
+	if want := []common.Address{bob, alice, bob}; !reflect.DeepEqual(order, want) {
+		t.Errorf("ReservationUpdates accounts = %v, want %v", order, want)
+	}
+	if deposit, update := l.Deposits()[0], l.ReservationUpdates()[0]; deposit.LogIndex >= update.LogIndex ||
+		deposit.BlockNumber != first.Number.Uint64() || deposit.Timestamp != first.Time || deposit.TxHash != txs[0].Hash() {
+		t.Errorf("first deposit at %+v, first reservation update at %+v", deposit.Event, update.Event)
+	}
+
+	a, ok := l.Account(alice)
+	if !ok {
+		t.Fatal("no events of alice")
+	}
+	for _, tc := range []struct {
+		timestamp uint64
+		want      int64
+	}{
+		{timestamp: first.Time - 1, want: 0},
+		{timestamp: first.Time, want: 100},
+		{timestamp: second.Time, want: 150},
+	} {
+		if got := a.TotalDepositAt(tc.timestamp); got.Cmp(big.NewInt(tc.want)) != 0 {
+			t.Errorf("alice TotalDepositAt(%d) = %v, want %d", tc.timestamp, got, tc.want)
+		}
+	}
+	if got := a.TotalDeposit(); got.Cmp(big.NewInt(150)) != 0 {
+		t.Errorf("alice TotalDeposit = %v, want 150", got)
+	}
+	if _, ok := l.Account(common.HexToAddress("0xdead")); ok {
+		t.Error("an account without events has a timeline")
+	}
+
+	b, _ := l.Account(bob)
+	for _, tc := range []struct {
+		timestamp uint64
+		want      ledger.ReservationState
+		wantEnd   uint64
+	}{
+		{timestamp: first.Time - 1, want: ledger.ReservationNone},
+		{timestamp: first.Time, want: ledger.ReservationPending, wantEnd: start + 1000},
+		// the replacement applies from its block on
+		{timestamp: third.Time - 1, want: ledger.ReservationPending, wantEnd: start + 1000},
+		{timestamp: third.Time, want: ledger.ReservationActive, wantEnd: first.Time + 1000},
+		{timestamp: first.Time + 1000, want: ledger.ReservationExpired, wantEnd: first.Time + 1000},
+	} {
+		reservation, state := b.ReservationAt(tc.timestamp)
+		if state != tc.want {
+			t.Errorf("bob ReservationAt(%d) = %v, want %v", tc.timestamp, state, tc.want)
+			continue
+		}
+		if state != ledger.ReservationNone && reservation.EndTimestamp != tc.wantEnd {
+			t.Errorf("bob ReservationAt(%d) ends at %d, want %d", tc.timestamp, reservation.EndTimestamp, tc.wantEnd)
+		}
+	}
+
+	// at the third block, bob's reservation ends within a week and alice's only after it
+	expiring := l.Expiring(third.Time, third.Time+week)
+	if len(expiring) != 1 || expiring[0].Account != bob {
+		t.Errorf("Expiring within a week = %+v, want bob's reservation", expiring)
+	}
+	expiring = l.Expiring(third.Time, third.Time+3*week)
+	if len(expiring) != 2 || expiring[0].Account != bob || expiring[1].Account != alice {
+		t.Errorf("Expiring within three weeks = %+v, want bob's and alice's reservations", expiring)
+	}
+	if expiring := l.Expiring(first.Time+1000, first.Time+3*week); len(expiring) != 1 || expiring[0].Account != alice {
+		t.Errorf("Expiring after bob's reservation ended = %+v, want alice's reservation", expiring)
+	}
+	if got := expiring[0].Reservation(); got.SymbolsPerSecond != 100 || !bytes.Equal(got.QuorumSplits, []byte{50, 50}) {
+		t.Errorf("Reservation = %+v", got)
+	}
+
+	top := l.TopDepositors(1)
+	if len(top) != 1 || top[0].Address != carol {
+		t.Errorf("TopDepositors(1) = %+v, want carol", top)
+	}
+	top = l.TopDepositors(10)
+	if len(top) != 2 || top[0].Address != carol || top[1].Address != alice {
+		t.Errorf("TopDepositors(10) = %+v, want carol and alice", top)
+	}
+
+	var buf bytes.Buffer
+	if err := l.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []*ledger.Account
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, l.Accounts()) {
+		t.Errorf("WriteJSON round trip = %s", buf.Bytes())
+	}
+}
+
+// failingReader fails to read the header of block fail, as an RPC endpoint going away mid-replay
+// does.
+type failingReader struct {
+	ledger.ChainReader
+	fail *big.Int
+}
+
+func (r *failingReader) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if r.fail != nil && number != nil && number.Cmp(r.fail) == 0 {
+		return nil, errors.New("connection reset")
+	}
+	return r.ChainReader.HeaderByNumber(ctx, number)
+}
+
+func TestReplayPages(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	v := vault{t: t, f: f}
+
+	var blocks []*types.Header
+	for i, account := range []common.Address{alice, bob, carol, alice, bob} {
+		blocks = append(blocks, v.mine(v.deposit(account, int64(i+1)), v.reserve(account, 0, uint64(i+1)*week)))
+	}
+	end := blocks[len(blocks)-1].Number.Uint64()
+
+	replay := func(l *ledger.Ledger) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := l.WriteJSON(&buf); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	whole, err := ledger.New(f.Client, f.Addresses.PaymentVault, ledger.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := whole.Replay(ctx, 0, &end); err != nil {
+		t.Fatal(err)
+	}
+	want := replay(whole)
+
+	for _, blockRange := range []uint64{1, 2, 3} {
+		reader := &failingReader{ChainReader: f.Client, fail: blocks[2].Number}
+		l, err := ledger.New(reader, f.Addresses.PaymentVault, ledger.Config{BlockRange: blockRange})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Replay(ctx, 0, &end); err == nil {
+			t.Fatalf("block range %d: Replay with an unreadable header succeeded", blockRange)
+		}
+		// nothing from the page holding the unreadable block is applied
+		next := l.NextBlock()
+		if next > blocks[2].Number.Uint64() {
+			t.Errorf("block range %d: NextBlock = %d after failing at block %v", blockRange, next, blocks[2].Number)
+		}
+		for _, deposit := range l.Deposits() {
+			if deposit.BlockNumber >= next {
+				t.Errorf("block range %d: deposit of block %d applied, NextBlock %d", blockRange, deposit.BlockNumber, next)
+			}
+		}
+		for _, update := range l.ReservationUpdates() {
+			if update.BlockNumber >= next {
+				t.Errorf("block range %d: reservation update of block %d applied, NextBlock %d", blockRange, update.BlockNumber, next)
+			}
+		}
+
+		reader.fail = nil
+		if err := l.Replay(ctx, next, &end); err != nil {
+			t.Fatal(err)
+		}
+		if got := replay(l); got != want {
+			t.Errorf("block range %d: resumed replay = %s, want %s", blockRange, got, want)
+		}
+		if l.NextBlock() != end+1 {
+			t.Errorf("block range %d: NextBlock = %d, want %d", blockRange, l.NextBlock(), end+1)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Package merkle builds the keccak merkle trees committed to by EigenDA batches and checks
+// their inclusion proofs exactly as eigenlayer's Merkle.verifyInclusionKeccak does.
+package merkle
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrInvalidProofLength mirrors the revert in Merkle.processInclusionProofKeccak.
+var ErrInvalidProofLength = errors.New("Merkle.processInclusionProofKeccak: proof length should be a multiple of 32")
+
+// ProcessInclusionProofKeccak returns the root obtained by hashing leaf up the tree with the
+// sibling nodes in proof, where the bits of index select the side of each sibling.
+func ProcessInclusionProofKeccak(proof []byte, leaf [32]byte, index uint64) ([32]byte, error) {
+	if len(proof)%32 != 0 {
+		return [32]byte{}, ErrInvalidProofLength
+	}
+
+	computedHash := leaf
+	for i := 0; i < len(proof); i += 32 {
+		if index%2 == 0 {
+			computedHash = [32]byte(crypto.Keccak256Hash(computedHash[:], proof[i:i+32]))
+		} else {
+			computedHash = [32]byte(crypto.Keccak256Hash(proof[i:i+32], computedHash[:]))
+		}
+		index /= 2
+	}
+	return computedHash, nil
+}
+
+// VerifyInclusionKeccak mirrors Merkle.verifyInclusionKeccak.
+func VerifyInclusionKeccak(proof []byte, root [32]byte, leaf [32]byte, index uint64) (bool, error) {
+	computedRoot, err := ProcessInclusionProofKeccak(proof, leaf, index)
+	if err != nil {
+		return false, err
+	}
+	return computedRoot == root, nil
+}
+
+// LeafHash returns the leaf committed to for a blob hash, keccak256(abi.encodePacked(hash)).
+func LeafHash(hash [32]byte) [32]byte {
+	return [32]byte(crypto.Keccak256Hash(hash[:]))
+}
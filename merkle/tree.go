@@ -0,0 +1,108 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Layr-Labs/eigenda/contracts/hashing"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// ErrEmptyTree is returned when building a tree without any leaves.
+var ErrEmptyTree = errors.New("merkle tree must have at least one leaf")
+
+// Tree is a keccak merkle tree over blob hashes, laid out the way dispersers build batch roots:
+// each blob hash is hashed into a leaf and the leaf layer is padded with zero nodes up to a power of two.
+type Tree struct {
+	// layers[0] holds the padded leaves and the last layer holds the root.
+	layers [][][32]byte
+	size   int
+}
+
+// NewTree builds a tree committing to hashes in order.
+func NewTree(hashes [][32]byte) (*Tree, error) {
+	if len(hashes) == 0 {
+		return nil, ErrEmptyTree
+	}
+
+	width := 1
+	for width < len(hashes) {
+		width *= 2
+	}
+	leaves := make([][32]byte, width)
+	for i, hash := range hashes {
+		leaves[i] = LeafHash(hash)
+	}
+
+	layers := [][][32]byte{leaves}
+	for layer := leaves; len(layer) > 1; {
+		parents := make([][32]byte, len(layer)/2)
+		for i := range parents {
+			parents[i] = [32]byte(crypto.Keccak256Hash(layer[2*i][:], layer[2*i+1][:]))
+		}
+		layers = append(layers, parents)
+		layer = parents
+	}
+
+	return &Tree{layers: layers, size: len(hashes)}, nil
+}
+
+// NewTreeFromBlobCertificates builds the tree whose root is BatchHeaderV2.batchRoot.
+func NewTreeFromBlobCertificates(blobCertificates []structs.BlobCertificate) (*Tree, error) {
+	hashes := make([][32]byte, len(blobCertificates))
+	for i, blobCertificate := range blobCertificates {
+		hash, err := hashing.HashBlobCertificate(blobCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("hash blob certificate %d: %w", i, err)
+		}
+		hashes[i] = hash
+	}
+	return NewTree(hashes)
+}
+
+// NewTreeFromBlobHeaders builds the tree whose root is BatchHeader.blobHeadersRoot.
+func NewTreeFromBlobHeaders(blobHeaders []structs.BlobHeader) (*Tree, error) {
+	hashes := make([][32]byte, len(blobHeaders))
+	for i, blobHeader := range blobHeaders {
+		hash, err := hashing.HashBlobHeader(blobHeader)
+		if err != nil {
+			return nil, fmt.Errorf("hash blob header %d: %w", i, err)
+		}
+		hashes[i] = hash
+	}
+	return NewTree(hashes)
+}
+
+// Root returns the root of the tree.
+func (t *Tree) Root() [32]byte {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Size returns the number of blob hashes the tree commits to, excluding padding.
+func (t *Tree) Size() int {
+	return t.size
+}
+
+// Proof returns the inclusion proof for the blob at index, encoded as the concatenated sibling
+// nodes from the leaf up. This is the inclusionProof expected by Merkle.verifyInclusionKeccak.
+func (t *Tree) Proof(index uint32) ([]byte, error) {
+	if int(index) >= t.size {
+		return nil, fmt.Errorf("index %d out of range for tree of size %d", index, t.size)
+	}
+
+	proof := make([]byte, 0, 32*(len(t.layers)-1))
+	position := int(index)
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		sibling := layer[position^1]
+		proof = append(proof, sibling[:]...)
+		position /= 2
+	}
+	return proof, nil
+}
+
+// Verify checks that proof places hash at index under the root of the tree.
+func (t *Tree) Verify(proof []byte, hash [32]byte, index uint32) (bool, error) {
+	return VerifyInclusionKeccak(proof, t.Root(), LeafHash(hash), uint64(index))
+}
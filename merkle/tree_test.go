@@ -0,0 +1,175 @@
+package merkle_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Layr-Labs/eigenda/contracts/bls"
+	"github.com/Layr-Labs/eigenda/contracts/hashing"
+	"github.com/Layr-Labs/eigenda/contracts/merkle"
+	"github.com/Layr-Labs/eigenda/contracts/reverts"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+func blobHashes(n int) [][32]byte {
+	hashes := make([][32]byte, n)
+	for i := range hashes {
+		hashes[i] = [32]byte(crypto.Keccak256Hash(big.NewInt(int64(i)).Bytes()))
+	}
+	return hashes
+}
+
+func TestTreeProofs(t *testing.T) {
+	for _, size := range []int{1, 2, 3, 4, 5, 7, 8, 9, 16, 17} {
+		hashes := blobHashes(size)
+		tree, err := merkle.NewTree(hashes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tree.Size() != size {
+			t.Errorf("size %d: Size = %d", size, tree.Size())
+		}
+
+		for i, hash := range hashes {
+			index := uint32(i)
+			proof, err := tree.Proof(index)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ok, err := tree.Verify(proof, hash, index)
+			if err != nil || !ok {
+				t.Errorf("size %d: proof of blob %d does not verify: %v", size, i, err)
+			}
+			if size == 1 {
+				continue
+			}
+			if ok, _ := tree.Verify(proof, hash, index^1); ok {
+				t.Errorf("size %d: proof of blob %d verifies at index %d", size, i, index^1)
+			}
+			tampered := append([]byte(nil), proof...)
+			tampered[0] ^= 1
+			if ok, _ := tree.Verify(tampered, hash, index); ok {
+				t.Errorf("size %d: tampered proof of blob %d verifies", size, i)
+			}
+		}
+
+		if _, err := tree.Proof(uint32(size)); err == nil {
+			t.Errorf("size %d: proof of blob %d past the end", size, size)
+		}
+	}
+}
+
+func TestNewTreeEmpty(t *testing.T) {
+	if _, err := merkle.NewTree(nil); !errors.Is(err, merkle.ErrEmptyTree) {
+		t.Errorf("NewTree(nil) = %v, want %v", err, merkle.ErrEmptyTree)
+	}
+}
+
+func TestProcessInclusionProofKeccak(t *testing.T) {
+	hashes := blobHashes(2)
+	leaf0, leaf1 := merkle.LeafHash(hashes[0]), merkle.LeafHash(hashes[1])
+	want := [32]byte(crypto.Keccak256Hash(leaf0[:], leaf1[:]))
+
+	for _, tc := range []struct {
+		name  string
+		proof []byte
+		leaf  [32]byte
+		index uint64
+		want  [32]byte
+		err   error
+	}{
+		{name: "left leaf", proof: leaf1[:], leaf: leaf0, index: 0, want: want},
+		{name: "right leaf", proof: leaf0[:], leaf: leaf1, index: 1, want: want},
+		{name: "empty proof", proof: nil, leaf: leaf0, index: 0, want: leaf0},
+		{name: "short proof", proof: leaf1[:31], leaf: leaf0, err: merkle.ErrInvalidProofLength},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := merkle.ProcessInclusionProofKeccak(tc.proof, tc.leaf, tc.index)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("error = %v, want %v", err, tc.err)
+			}
+			if root != tc.want {
+				t.Errorf("root = %x, want %x", root, tc.want)
+			}
+		})
+	}
+}
+
+// TestProofsAgainstContract confirms a batch committing to a tree of blob headers and checks its
+// proofs with Merkle.verifyInclusionKeccak through EigenDACertVerifier.verifyDACertV1.
+func TestProofsAgainstContract(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	opts := &bind.CallOpts{Context: ctx}
+
+	_, _, g1, _ := bn254.Generators()
+	blobHeaders := make([]structs.BlobHeader, 5)
+	for i := range blobHeaders {
+		blobHeaders[i] = structs.BlobHeader{
+			Commitment: bls.G1Point(&g1),
+			DataLength: uint32(i + 1),
+			QuorumBlobParams: []structs.QuorumBlobParam{
+				{QuorumNumber: 0, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55, ChunkLength: 1},
+				{QuorumNumber: 1, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55, ChunkLength: 1},
+			},
+		}
+	}
+	tree, err := merkle.NewTreeFromBlobHeaders(blobHeaders)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	referenceBlock, err := f.Client.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Backend.Commit()
+	batchID, batchMetadata, err := f.ConfirmBatch(structs.BatchHeader{
+		BlobHeadersRoot:       tree.Root(),
+		QuorumNumbers:         []byte{0, 1},
+		SignedStakeForQuorums: []byte{100, 100},
+		ReferenceBlockNumber:  uint32(referenceBlock),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, blobHeader := range blobHeaders {
+		index := uint32(i)
+		proof, err := tree.Proof(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobHash, err := hashing.HashBlobHeader(blobHeader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, blobIndex := range []uint32{index, (index + 1) % uint32(len(blobHeaders))} {
+			included, err := tree.Verify(proof, blobHash, blobIndex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = reverts.Decode(f.CertVerifier.VerifyDACertV1(opts, blobHeader, structs.BlobVerificationProof{
+				BatchId:        batchID,
+				BlobIndex:      blobIndex,
+				BatchMetadata:  batchMetadata,
+				InclusionProof: proof,
+				QuorumIndices:  []byte{0, 1},
+			}))
+			switch {
+			case included && err != nil:
+				t.Errorf("blob %d at index %d: contract rejected a proof Go accepts: %v", i, blobIndex, err)
+			case !included && !errors.Is(err, reverts.ErrInclusionProofInvalidV1):
+				t.Errorf("blob %d at index %d: contract = %v, want %v", i, blobIndex, err, reverts.ErrInclusionProofInvalidV1)
+			}
+		}
+	}
+}
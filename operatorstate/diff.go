@@ -0,0 +1,133 @@
+package operatorstate
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChangeKind is the way an operator's membership of a quorum changed between two snapshots.
+type ChangeKind int
+
+const (
+	// Joined means the operator is in the quorum only in the newer snapshot.
+	Joined ChangeKind = iota
+	// Left means the operator is in the quorum only in the older snapshot.
+	Left
+	// StakeChanged means the operator is in the quorum in both snapshots with different stakes.
+	StakeChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Joined:
+		return "joined"
+	case Left:
+		return "left"
+	case StakeChanged:
+		return "stake-changed"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
+
+// MarshalText encodes the kind by name in JSON.
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Change is a change to the operator set of a quorum. OldStake is nil for joins and NewStake is
+// nil for leaves.
+type Change struct {
+	QuorumNumber uint8          `json:"quorumNumber"`
+	Kind         ChangeKind     `json:"kind"`
+	OperatorID   common.Hash    `json:"operatorId"`
+	Address      common.Address `json:"address"`
+	OldStake     *big.Int       `json:"oldStake,omitempty"`
+	NewStake     *big.Int       `json:"newStake,omitempty"`
+}
+
+// Diff returns the joins, leaves and stake changes from older to newer, ordered by quorum number,
+// kind and operator id. Quorums missing from either snapshot are compared with an empty quorum.
+func Diff(older, newer *Snapshot) []*Change {
+	var changes []*Change
+	for _, quorumNumber := range quorumNumbers(older, newer) {
+		before := operatorsByID(older, quorumNumber)
+		after := operatorsByID(newer, quorumNumber)
+
+		for id, operator := range after {
+			previous, ok := before[id]
+			switch {
+			case !ok:
+				changes = append(changes, &Change{
+					QuorumNumber: quorumNumber,
+					Kind:         Joined,
+					OperatorID:   id,
+					Address:      operator.Address,
+					NewStake:     operator.Stake,
+				})
+			case previous.Stake.Cmp(operator.Stake) != 0:
+				changes = append(changes, &Change{
+					QuorumNumber: quorumNumber,
+					Kind:         StakeChanged,
+					OperatorID:   id,
+					Address:      operator.Address,
+					OldStake:     previous.Stake,
+					NewStake:     operator.Stake,
+				})
+			}
+		}
+		for id, operator := range before {
+			if _, ok := after[id]; !ok {
+				changes = append(changes, &Change{
+					QuorumNumber: quorumNumber,
+					Kind:         Left,
+					OperatorID:   id,
+					Address:      operator.Address,
+					OldStake:     operator.Stake,
+				})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.QuorumNumber != b.QuorumNumber {
+			return a.QuorumNumber < b.QuorumNumber
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return bytes.Compare(a.OperatorID[:], b.OperatorID[:]) < 0
+	})
+	return changes
+}
+
+// quorumNumbers returns the quorum numbers in either snapshot in ascending order.
+func quorumNumbers(snapshots ...*Snapshot) []uint8 {
+	var seen [256]bool
+	var numbers []uint8
+	for _, snapshot := range snapshots {
+		for _, quorum := range snapshot.Quorums {
+			if !seen[quorum.QuorumNumber] {
+				seen[quorum.QuorumNumber] = true
+				numbers = append(numbers, quorum.QuorumNumber)
+			}
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	return numbers
+}
+
+func operatorsByID(snapshot *Snapshot, quorumNumber uint8) map[common.Hash]*Operator {
+	operators := make(map[common.Hash]*Operator)
+	if quorum, ok := snapshot.Quorum(quorumNumber); ok {
+		for _, operator := range quorum.Operators {
+			operators[operator.OperatorID] = operator
+		}
+	}
+	return operators
+}
@@ -0,0 +1,314 @@
+package operatorstate
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	contractBLSApkRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/BLSApkRegistry"
+	contractRegistryCoordinator "github.com/Layr-Labs/eigenda/contracts/bindings/RegistryCoordinator"
+	contractStakeRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/StakeRegistry"
+)
+
+// Config tunes how a HistoryCache follows the registries. Zero values fall back to DefaultConfig.
+type Config struct {
+	// BlockRange is the most blocks filtered in one eth_getLogs request when syncing.
+	BlockRange uint64
+}
+
+// DefaultConfig returns the defaults used for unset Config fields.
+func DefaultConfig() Config {
+	return Config{BlockRange: 10_000}
+}
+
+func (c Config) withDefaults() Config {
+	if c.BlockRange == 0 {
+		c.BlockRange = DefaultConfig().BlockRange
+	}
+	return c
+}
+
+type (
+	quorumBitmapUpdate = contractRegistryCoordinator.IRegistryCoordinatorQuorumBitmapUpdate
+	apkUpdate          = contractBLSApkRegistry.IBLSApkRegistryApkUpdate
+	stakeUpdate        = contractStakeRegistry.IStakeRegistryStakeUpdate
+)
+
+// history is a cached copy of one registry history array.
+type history[T any] struct {
+	updates []T
+	// stale is set when the registry emitted an event that may have changed the array since it
+	// was read.
+	stale bool
+}
+
+type stakeKey struct {
+	operatorID   [32]byte
+	quorumNumber uint8
+}
+
+// HistoryCache is a local copy of the registry histories OperatorStateRetriever.getCheckSignaturesIndices
+// searches: the quorum bitmap history of each operator in the RegistryCoordinator, the APK
+// history of each quorum in the BLSApkRegistry, and the operator and total stake histories in the
+// StakeRegistry. It is safe for concurrent use.
+//
+// Histories are read when first needed and then only re-read after the registries emit an event
+// that may have changed them, so indices for many certs cost no calls once the cache is warm.
+// Every read is made at the block the cache is synced to, which keeps all histories consistent
+// with each other. The cache does not follow reorgs of blocks it has synced.
+type HistoryCache struct {
+	registryCoordinator *contractRegistryCoordinator.ContractRegistryCoordinatorCaller
+	stakeRegistry       *contractStakeRegistry.ContractStakeRegistry
+	blsApkRegistry      *contractBLSApkRegistry.ContractBLSApkRegistry
+	backend             bind.ContractBackend
+	config              Config
+
+	mu sync.Mutex
+	// syncedBlock is the block up to which registry events have been applied.
+	syncedBlock   uint64
+	quorumBitmaps map[[32]byte]*history[quorumBitmapUpdate]
+	apks          map[uint8]*history[apkUpdate]
+	totalStakes   map[uint8]*history[stakeUpdate]
+	stakes        map[stakeKey]*history[stakeUpdate]
+}
+
+// NewHistoryCache returns an empty cache of the registries of the RegistryCoordinator at
+// registryCoordinator, synced to the head.
+func NewHistoryCache(
+	ctx context.Context,
+	backend bind.ContractBackend,
+	registryCoordinator common.Address,
+	config Config,
+) (*HistoryCache, error) {
+	coordinator, err := contractRegistryCoordinator.NewContractRegistryCoordinatorCaller(registryCoordinator, backend)
+	if err != nil {
+		return nil, err
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	stakeRegistryAddress, err := coordinator.StakeRegistry(opts)
+	if err != nil {
+		return nil, err
+	}
+	stakeRegistry, err := contractStakeRegistry.NewContractStakeRegistry(stakeRegistryAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	blsApkRegistryAddress, err := coordinator.BlsApkRegistry(opts)
+	if err != nil {
+		return nil, err
+	}
+	blsApkRegistry, err := contractBLSApkRegistry.NewContractBLSApkRegistry(blsApkRegistryAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	head, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &HistoryCache{
+		registryCoordinator: coordinator,
+		stakeRegistry:       stakeRegistry,
+		blsApkRegistry:      blsApkRegistry,
+		backend:             backend,
+		config:              config.withDefaults(),
+		syncedBlock:         head.Number.Uint64(),
+		quorumBitmaps:       make(map[[32]byte]*history[quorumBitmapUpdate]),
+		apks:                make(map[uint8]*history[apkUpdate]),
+		totalStakes:         make(map[uint8]*history[stakeUpdate]),
+		stakes:              make(map[stakeKey]*history[stakeUpdate]),
+	}, nil
+}
+
+// SyncedBlock returns the block the cached histories are current at.
+func (c *HistoryCache) SyncedBlock() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.syncedBlock
+}
+
+// Sync advances the cache to the head, marking the histories changed by the registry events
+// emitted since the last sync so they are re-read on next use.
+func (c *HistoryCache) Sync(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sync(ctx)
+}
+
+func (c *HistoryCache) sync(ctx context.Context) error {
+	head, err := c.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for c.syncedBlock < head.Number.Uint64() {
+		start := c.syncedBlock + 1
+		end := min(head.Number.Uint64(), c.syncedBlock+c.config.BlockRange)
+		if err := c.applyEvents(&bind.FilterOpts{Start: start, End: &end, Context: ctx}); err != nil {
+			return err
+		}
+		c.syncedBlock = end
+	}
+	return nil
+}
+
+// applyEvents marks the histories changed in the filtered blocks as stale. Registering and
+// deregistering change the operator's quorum bitmap and the quorum APKs, and every change to an
+// operator's stake also changes the quorum's total stake.
+func (c *HistoryCache) applyEvents(opts *bind.FilterOpts) error {
+	stakeUpdates, err := c.stakeRegistry.FilterOperatorStakeUpdate(opts, nil)
+	if err != nil {
+		return err
+	}
+	defer stakeUpdates.Close()
+	for stakeUpdates.Next() {
+		event := stakeUpdates.Event
+		markStale(c.stakes, stakeKey{event.OperatorId, event.QuorumNumber})
+		markStale(c.totalStakes, event.QuorumNumber)
+	}
+	if err := stakeUpdates.Error(); err != nil {
+		return err
+	}
+
+	added, err := c.blsApkRegistry.FilterOperatorAddedToQuorums(opts)
+	if err != nil {
+		return err
+	}
+	defer added.Close()
+	for added.Next() {
+		c.markMembershipStale(added.Event.OperatorId, added.Event.QuorumNumbers)
+	}
+	if err := added.Error(); err != nil {
+		return err
+	}
+
+	removed, err := c.blsApkRegistry.FilterOperatorRemovedFromQuorums(opts)
+	if err != nil {
+		return err
+	}
+	defer removed.Close()
+	for removed.Next() {
+		c.markMembershipStale(removed.Event.OperatorId, removed.Event.QuorumNumbers)
+	}
+	return removed.Error()
+}
+
+func (c *HistoryCache) markMembershipStale(operatorID [32]byte, quorumNumbers []byte) {
+	markStale(c.quorumBitmaps, operatorID)
+	for _, quorumNumber := range quorumNumbers {
+		markStale(c.apks, quorumNumber)
+	}
+}
+
+func markStale[K comparable, T any](histories map[K]*history[T], key K) {
+	if h, ok := histories[key]; ok {
+		h.stale = true
+	}
+}
+
+// callOpts pins reads to the synced block.
+func (c *HistoryCache) callOpts(ctx context.Context) *bind.CallOpts {
+	return &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(c.syncedBlock)}
+}
+
+func (c *HistoryCache) quorumBitmapHistory(ctx context.Context, operatorID [32]byte) ([]quorumBitmapUpdate, error) {
+	opts := c.callOpts(ctx)
+	return load(c.quorumBitmaps, operatorID,
+		func() (uint64, error) {
+			length, err := c.registryCoordinator.GetQuorumBitmapHistoryLength(opts, operatorID)
+			if err != nil {
+				return 0, err
+			}
+			return length.Uint64(), nil
+		},
+		func(index uint64) (quorumBitmapUpdate, error) {
+			return c.registryCoordinator.GetQuorumBitmapUpdateByIndex(opts, operatorID, new(big.Int).SetUint64(index))
+		},
+	)
+}
+
+func (c *HistoryCache) apkHistory(ctx context.Context, quorumNumber uint8) ([]apkUpdate, error) {
+	opts := c.callOpts(ctx)
+	return load(c.apks, quorumNumber,
+		func() (uint64, error) {
+			length, err := c.blsApkRegistry.GetApkHistoryLength(opts, quorumNumber)
+			return uint64(length), err
+		},
+		func(index uint64) (apkUpdate, error) {
+			return c.blsApkRegistry.GetApkUpdateAtIndex(opts, quorumNumber, new(big.Int).SetUint64(index))
+		},
+	)
+}
+
+func (c *HistoryCache) totalStakeHistory(ctx context.Context, quorumNumber uint8) ([]stakeUpdate, error) {
+	opts := c.callOpts(ctx)
+	return load(c.totalStakes, quorumNumber,
+		func() (uint64, error) {
+			length, err := c.stakeRegistry.GetTotalStakeHistoryLength(opts, quorumNumber)
+			if err != nil {
+				return 0, err
+			}
+			return length.Uint64(), nil
+		},
+		func(index uint64) (stakeUpdate, error) {
+			return c.stakeRegistry.GetTotalStakeUpdateAtIndex(opts, quorumNumber, new(big.Int).SetUint64(index))
+		},
+	)
+}
+
+func (c *HistoryCache) stakeHistory(ctx context.Context, operatorID [32]byte, quorumNumber uint8) ([]stakeUpdate, error) {
+	opts := c.callOpts(ctx)
+	return load(c.stakes, stakeKey{operatorID, quorumNumber},
+		func() (uint64, error) {
+			length, err := c.stakeRegistry.GetStakeHistoryLength(opts, operatorID, quorumNumber)
+			if err != nil {
+				return 0, err
+			}
+			return length.Uint64(), nil
+		},
+		func(index uint64) (stakeUpdate, error) {
+			return c.stakeRegistry.GetStakeUpdateAtIndex(opts, quorumNumber, operatorID, new(big.Int).SetUint64(index))
+		},
+	)
+}
+
+// load returns the cached history under key, reading it first if it is missing or stale.
+//
+// The registries only ever append to a history, except that an update in the same block as the
+// last entry overwrites it and a new entry sets the last one's nextUpdateBlockNumber. A stale
+// history is therefore refreshed by re-reading its last entry and appending the new ones. Empty
+// histories are not cached, since quorums and operators can gain one without an event.
+func load[K comparable, T any](
+	histories map[K]*history[T],
+	key K,
+	length func() (uint64, error),
+	at func(index uint64) (T, error),
+) ([]T, error) {
+	h, ok := histories[key]
+	if ok && !h.stale {
+		return h.updates, nil
+	}
+	if !ok {
+		h = &history[T]{}
+	}
+
+	n, err := length()
+	if err != nil {
+		return nil, err
+	}
+	updates := h.updates[:max(len(h.updates), 1)-1]
+	for index := uint64(len(updates)); index < n; index++ {
+		update, err := at(index)
+		if err != nil {
+			return nil, err
+		}
+		updates = append(updates, update)
+	}
+	if len(updates) == 0 {
+		return nil, nil
+	}
+	h.updates, h.stale = updates, false
+	histories[key] = h
+	return updates, nil
+}
@@ -0,0 +1,125 @@
+package operatorstate
+
+import (
+	"context"
+
+	"github.com/Layr-Labs/eigenda/contracts/reverts"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// Errors returned by CheckSignaturesIndices. They are the sentinels the reverts package decodes
+// the revert of the registry lookup that fails on chain for the same input to.
+var (
+	ErrNoQuorumBitmapUpdate      = reverts.ErrNoQuorumBitmapUpdate
+	ErrQuorumDoesNotExist        = reverts.ErrQuorumDoesNotExist
+	ErrNoTotalStakeHistory       = reverts.ErrNoTotalStakeHistory
+	ErrOperatorNotRegistered     = reverts.ErrOperatorNotRegistered
+	ErrNoStakeUpdate             = reverts.ErrNoStakeUpdate
+	ErrBlockBeforeFirstApkUpdate = reverts.ErrBlockBeforeFirstApkUpdate
+)
+
+// CheckSignaturesIndices mirrors OperatorStateRetriever.getCheckSignaturesIndices, searching the
+// cached histories instead of the registries. The cache is synced first if referenceBlockNumber
+// is past the synced block.
+func (c *HistoryCache) CheckSignaturesIndices(
+	ctx context.Context,
+	referenceBlockNumber uint32,
+	quorumNumbers []byte,
+	nonSignerOperatorIds [][32]byte,
+) (structs.CheckSignaturesIndices, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if uint64(referenceBlockNumber) > c.syncedBlock {
+		if err := c.sync(ctx); err != nil {
+			return structs.CheckSignaturesIndices{}, err
+		}
+	}
+
+	var indices structs.CheckSignaturesIndices
+
+	// RegistryCoordinator.getQuorumBitmapIndicesAtBlockNumber
+	indices.NonSignerQuorumBitmapIndices = make([]uint32, len(nonSignerOperatorIds))
+	nonSignerQuorumBitmaps := make([]quorumBitmapUpdate, len(nonSignerOperatorIds))
+	for i, operatorID := range nonSignerOperatorIds {
+		updates, err := c.quorumBitmapHistory(ctx, operatorID)
+		if err != nil {
+			return structs.CheckSignaturesIndices{}, err
+		}
+		index, ok := indexAtBlockNumber(updates, referenceBlockNumber, func(u quorumBitmapUpdate) uint32 { return u.UpdateBlockNumber })
+		if !ok {
+			return structs.CheckSignaturesIndices{}, ErrNoQuorumBitmapUpdate
+		}
+		indices.NonSignerQuorumBitmapIndices[i] = index
+		nonSignerQuorumBitmaps[i] = updates[index]
+	}
+
+	// StakeRegistry.getTotalStakeIndicesAtBlockNumber
+	indices.TotalStakeIndices = make([]uint32, len(quorumNumbers))
+	for i, quorumNumber := range quorumNumbers {
+		updates, err := c.totalStakeHistory(ctx, quorumNumber)
+		if err != nil {
+			return structs.CheckSignaturesIndices{}, err
+		}
+		// a quorum exists once its total stake history is initialized
+		if len(updates) == 0 {
+			return structs.CheckSignaturesIndices{}, ErrQuorumDoesNotExist
+		}
+		index, ok := indexAtBlockNumber(updates, referenceBlockNumber, func(u stakeUpdate) uint32 { return u.UpdateBlockNumber })
+		if !ok {
+			return structs.CheckSignaturesIndices{}, ErrNoTotalStakeHistory
+		}
+		indices.TotalStakeIndices[i] = index
+	}
+
+	indices.NonSignerStakeIndices = make([][]uint32, len(quorumNumbers))
+	for i, quorumNumber := range quorumNumbers {
+		indices.NonSignerStakeIndices[i] = []uint32{}
+		for j, operatorID := range nonSignerOperatorIds {
+			bitmap := nonSignerQuorumBitmaps[j].QuorumBitmap
+			if bitmap.Sign() == 0 {
+				return structs.CheckSignaturesIndices{}, ErrOperatorNotRegistered
+			}
+			if bitmap.Bit(int(quorumNumber)) == 0 {
+				continue
+			}
+
+			// StakeRegistry.getStakeUpdateIndexAtBlockNumber
+			updates, err := c.stakeHistory(ctx, operatorID, quorumNumber)
+			if err != nil {
+				return structs.CheckSignaturesIndices{}, err
+			}
+			index, ok := indexAtBlockNumber(updates, referenceBlockNumber, func(u stakeUpdate) uint32 { return u.UpdateBlockNumber })
+			if !ok {
+				return structs.CheckSignaturesIndices{}, ErrNoStakeUpdate
+			}
+			indices.NonSignerStakeIndices[i] = append(indices.NonSignerStakeIndices[i], index)
+		}
+	}
+
+	// BLSApkRegistry.getApkIndicesAtBlockNumber
+	indices.QuorumApkIndices = make([]uint32, len(quorumNumbers))
+	for i, quorumNumber := range quorumNumbers {
+		updates, err := c.apkHistory(ctx, quorumNumber)
+		if err != nil {
+			return structs.CheckSignaturesIndices{}, err
+		}
+		index, ok := indexAtBlockNumber(updates, referenceBlockNumber, func(u apkUpdate) uint32 { return u.UpdateBlockNumber })
+		if !ok {
+			return structs.CheckSignaturesIndices{}, ErrBlockBeforeFirstApkUpdate
+		}
+		indices.QuorumApkIndices[i] = index
+	}
+
+	return indices, nil
+}
+
+// indexAtBlockNumber returns the index of the last update made at or before blockNumber, which
+// every registry finds by walking its history backwards.
+func indexAtBlockNumber[T any](updates []T, blockNumber uint32, updateBlockNumber func(T) uint32) (uint32, bool) {
+	for i := len(updates); i > 0; i-- {
+		if updateBlockNumber(updates[i-1]) <= blockNumber {
+			return uint32(i - 1), true
+		}
+	}
+	return 0, false
+}
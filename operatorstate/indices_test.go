@@ -0,0 +1,128 @@
+package operatorstate_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/Layr-Labs/eigenda/contracts/operatorstate"
+	"github.com/Layr-Labs/eigenda/contracts/reverts"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+// indexErrors are the errors CheckSignaturesIndices and getCheckSignaturesIndices fail with.
+var indexErrors = []error{
+	operatorstate.ErrNoQuorumBitmapUpdate,
+	operatorstate.ErrQuorumDoesNotExist,
+	operatorstate.ErrNoTotalStakeHistory,
+	operatorstate.ErrOperatorNotRegistered,
+	operatorstate.ErrNoStakeUpdate,
+	operatorstate.ErrBlockBeforeFirstApkUpdate,
+}
+
+// TestCheckSignaturesIndices compares HistoryCache.CheckSignaturesIndices with
+// OperatorStateRetriever.getCheckSignaturesIndices at every block of the fixture, while operators
+// leave quorums and change stake.
+func TestCheckSignaturesIndices(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	operators := f.Operators
+
+	cache, err := operatorstate.NewHistoryCache(ctx, f.Client, f.Addresses.RegistryCoordinator, operatorstate.Config{BlockRange: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// warm the cache so that the changes below must be picked up from the registry events
+	if _, err := cache.CheckSignaturesIndices(ctx, headNumber(t, f), []byte{0, 1},
+		[][32]byte{operators[0].OperatorID, operators[1].OperatorID, operators[2].OperatorID}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.DeregisterOperator(operators[0], []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetOperatorShares(operators[1], 0, ether(3)); err != nil {
+		t.Fatal(err)
+	}
+	f.Backend.Commit()
+	if err := f.SetOperatorShares(operators[1], 0, ether(2)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.DeregisterOperator(operators[2], []byte{0, 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// every revert the fixture can reach is compared, not just successful lookups; the APK history
+	// starts in the same block as the total stake history, which is searched first
+	seen := make(map[error]bool)
+	defer func() {
+		for _, sentinel := range []error{
+			operatorstate.ErrNoQuorumBitmapUpdate,
+			operatorstate.ErrQuorumDoesNotExist,
+			operatorstate.ErrNoTotalStakeHistory,
+			operatorstate.ErrOperatorNotRegistered,
+		} {
+			if !seen[sentinel] {
+				t.Errorf("no lookup reverted with %v", sentinel)
+			}
+		}
+	}()
+
+	ids := func(operators ...*fixture.Operator) [][32]byte {
+		ids := make([][32]byte, len(operators))
+		for i, operator := range operators {
+			ids[i] = operator.OperatorID
+		}
+		return ids
+	}
+	for _, tc := range []struct {
+		name          string
+		quorumNumbers []byte
+		nonSigners    [][32]byte
+	}{
+		{name: "all signed", quorumNumbers: []byte{0, 1}, nonSigners: [][32]byte{}},
+		{name: "left quorum 1", quorumNumbers: []byte{0, 1}, nonSigners: ids(operators[0])},
+		{name: "stake changes", quorumNumbers: []byte{1, 0}, nonSigners: ids(operators[1], operators[3])},
+		{name: "deregistered", quorumNumbers: []byte{0, 1}, nonSigners: ids(operators[3], operators[2], operators[0])},
+		{name: "single quorum", quorumNumbers: []byte{1}, nonSigners: ids(operators[0], operators[1])},
+		{name: "unknown operator", quorumNumbers: []byte{0}, nonSigners: [][32]byte{{1}}},
+		{name: "unknown quorum", quorumNumbers: []byte{0, 5}, nonSigners: ids(operators[1])},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for block := uint32(0); block <= headNumber(t, f); block++ {
+				got, err := cache.CheckSignaturesIndices(ctx, block, tc.quorumNumbers, tc.nonSigners)
+				want, wantErr := f.OperatorStateRetriever.GetCheckSignaturesIndices(&bind.CallOpts{Context: ctx},
+					f.Addresses.RegistryCoordinator, block, tc.quorumNumbers, tc.nonSigners)
+				if wantErr != nil {
+					wantErr = reverts.Decode(wantErr)
+					seen[checkSameError(t, block, err, wantErr)] = true
+					continue
+				}
+				if err != nil {
+					t.Fatalf("block %d: CheckSignaturesIndices = %v, contract succeeded", block, err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("block %d: CheckSignaturesIndices = %+v, contract %+v", block, got, want)
+				}
+			}
+		})
+	}
+}
+
+// checkSameError checks that err is the error the contract reverted with, and returns it.
+func checkSameError(t *testing.T, block uint32, err, contractErr error) error {
+	t.Helper()
+	for _, sentinel := range indexErrors {
+		if errors.Is(contractErr, sentinel) {
+			if !errors.Is(err, sentinel) {
+				t.Fatalf("block %d: CheckSignaturesIndices = %v, contract reverted with %v", block, err, contractErr)
+			}
+			return sentinel
+		}
+	}
+	t.Fatalf("block %d: contract reverted with an unexpected error: %v", block, contractErr)
+	return nil
+}
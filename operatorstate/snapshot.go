@@ -0,0 +1,181 @@
+// Package operatorstate takes typed snapshots of the registered operator set through
+// OperatorStateRetriever, and diffs snapshots taken at different blocks. It also caches the
+// registry histories needed to compute the checkSignatures indices of certs off chain.
+package operatorstate
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	contractBLSApkRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/BLSApkRegistry"
+	contractOperatorStateRetriever "github.com/Layr-Labs/eigenda/contracts/bindings/OperatorStateRetriever"
+	contractRegistryCoordinator "github.com/Layr-Labs/eigenda/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// Operator is an operator registered in a quorum.
+type Operator struct {
+	OperatorID common.Hash     `json:"operatorId"`
+	Address    common.Address  `json:"address"`
+	Socket     string          `json:"socket"`
+	PubkeyG1   structs.G1Point `json:"pubkeyG1"`
+	Stake      *big.Int        `json:"stake"`
+	// StakeShare is the percentage of the quorum's total stake the operator holds.
+	StakeShare float64 `json:"stakeShare"`
+}
+
+// Quorum is the operator set of a quorum, in the order of the IndexRegistry.
+type Quorum struct {
+	QuorumNumber uint8       `json:"quorumNumber"`
+	TotalStake   *big.Int    `json:"totalStake"`
+	Operators    []*Operator `json:"operators"`
+}
+
+// Snapshot is the operator state of a set of quorums at a block.
+type Snapshot struct {
+	BlockNumber uint32    `json:"blockNumber"`
+	Quorums     []*Quorum `json:"quorums"`
+}
+
+// Quorum returns the quorum with the given number, if the snapshot has it.
+func (s *Snapshot) Quorum(quorumNumber uint8) (*Quorum, bool) {
+	for _, quorum := range s.Quorums {
+		if quorum.QuorumNumber == quorumNumber {
+			return quorum, true
+		}
+	}
+	return nil, false
+}
+
+// Reader takes snapshots of the operators registered with a RegistryCoordinator. It is safe for
+// concurrent use.
+type Reader struct {
+	registryCoordinatorAddress common.Address
+	registryCoordinator        *contractRegistryCoordinator.ContractRegistryCoordinatorCaller
+	operatorStateRetriever     *contractOperatorStateRetriever.ContractOperatorStateRetrieverCaller
+	blsApkRegistry             *contractBLSApkRegistry.ContractBLSApkRegistryCaller
+
+	// pubkeys caches the G1 pubkeys of operators, which cannot change once registered.
+	pubkeys sync.Map // common.Address -> structs.G1Point
+}
+
+// NewReader returns a Reader of the operators of the RegistryCoordinator at registryCoordinator,
+// looking up its BLSApkRegistry.
+func NewReader(
+	opts *bind.CallOpts,
+	backend bind.ContractCaller,
+	operatorStateRetriever common.Address,
+	registryCoordinator common.Address,
+) (*Reader, error) {
+	coordinator, err := contractRegistryCoordinator.NewContractRegistryCoordinatorCaller(registryCoordinator, backend)
+	if err != nil {
+		return nil, err
+	}
+	retriever, err := contractOperatorStateRetriever.NewContractOperatorStateRetrieverCaller(operatorStateRetriever, backend)
+	if err != nil {
+		return nil, err
+	}
+	blsApkRegistryAddress, err := coordinator.BlsApkRegistry(opts)
+	if err != nil {
+		return nil, err
+	}
+	blsApkRegistry, err := contractBLSApkRegistry.NewContractBLSApkRegistryCaller(blsApkRegistryAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		registryCoordinatorAddress: registryCoordinator,
+		registryCoordinator:        coordinator,
+		operatorStateRetriever:     retriever,
+		blsApkRegistry:             blsApkRegistry,
+	}, nil
+}
+
+// Snapshot returns the operator state of quorumNumbers at blockNumber, or of every quorum if
+// quorumNumbers is empty.
+//
+// Stakes come from the registry histories at blockNumber. Sockets and pubkeys are read with opts,
+// since the registries keep no history of them. Addresses are the ones the retriever resolves
+// with getBatchOperatorFromId, so they need no separate lookup.
+func (r *Reader) Snapshot(opts *bind.CallOpts, blockNumber uint32, quorumNumbers []byte) (*Snapshot, error) {
+	if len(quorumNumbers) == 0 {
+		quorumCount, err := r.registryCoordinator.QuorumCount(opts)
+		if err != nil {
+			return nil, err
+		}
+		for quorumNumber := uint8(0); quorumNumber < quorumCount; quorumNumber++ {
+			quorumNumbers = append(quorumNumbers, quorumNumber)
+		}
+	}
+
+	state, err := r.operatorStateRetriever.GetOperatorStateWithSocket(opts, r.registryCoordinatorAddress, quorumNumbers, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(state.Operators) != len(quorumNumbers) || len(state.Sockets) != len(quorumNumbers) {
+		return nil, fmt.Errorf("operator state has %d quorums and %d socket lists, expected %d",
+			len(state.Operators), len(state.Sockets), len(quorumNumbers))
+	}
+
+	snapshot := &Snapshot{BlockNumber: blockNumber}
+	for i, quorumNumber := range quorumNumbers {
+		operators, sockets := state.Operators[i], state.Sockets[i]
+		if len(sockets) != len(operators) {
+			return nil, fmt.Errorf("quorum %d has %d operators and %d sockets", quorumNumber, len(operators), len(sockets))
+		}
+
+		quorum := &Quorum{
+			QuorumNumber: quorumNumber,
+			TotalStake:   new(big.Int),
+			Operators:    make([]*Operator, len(operators)),
+		}
+		for j, operator := range operators {
+			pubkey, err := r.pubkey(opts, operator.Operator)
+			if err != nil {
+				return nil, err
+			}
+			quorum.Operators[j] = &Operator{
+				OperatorID: operator.OperatorId,
+				Address:    operator.Operator,
+				Socket:     sockets[j],
+				PubkeyG1:   pubkey,
+				Stake:      operator.Stake,
+			}
+			quorum.TotalStake.Add(quorum.TotalStake, operator.Stake)
+		}
+		for _, operator := range quorum.Operators {
+			operator.StakeShare = stakeShare(operator.Stake, quorum.TotalStake)
+		}
+		snapshot.Quorums = append(snapshot.Quorums, quorum)
+	}
+	return snapshot, nil
+}
+
+func (r *Reader) pubkey(opts *bind.CallOpts, operator common.Address) (structs.G1Point, error) {
+	if pubkey, ok := r.pubkeys.Load(operator); ok {
+		return pubkey.(structs.G1Point), nil
+	}
+	pubkey, _, err := r.blsApkRegistry.GetRegisteredPubkey(opts, operator)
+	if err != nil {
+		return structs.G1Point{}, fmt.Errorf("pubkey of operator %s: %w", operator, err)
+	}
+	point := structs.MustConvert[structs.G1Point](pubkey)
+	r.pubkeys.Store(operator, point)
+	return point, nil
+}
+
+// stakeShare returns stake as a percentage of total.
+func stakeShare(stake, total *big.Int) float64 {
+	if total.Sign() == 0 {
+		return 0
+	}
+	share, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(new(big.Int).Mul(stake, big.NewInt(100))),
+		new(big.Float).SetInt(total),
+	).Float64()
+	return share
+}
@@ -0,0 +1,244 @@
+package operatorstate_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/Layr-Labs/eigenda/contracts/operatorstate"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(params.Ether))
+}
+
+func newReader(t *testing.T, f *fixture.Fixture) *operatorstate.Reader {
+	t.Helper()
+	reader, err := operatorstate.NewReader(&bind.CallOpts{Context: context.Background()}, f.Client,
+		f.Addresses.OperatorStateRetriever, f.Addresses.RegistryCoordinator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+func headNumber(t *testing.T, f *fixture.Fixture) uint32 {
+	t.Helper()
+	head, err := f.Client.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uint32(head)
+}
+
+// checkOperator checks that operator is the fixture operator want with the given stake and share.
+func checkOperator(t *testing.T, quorumNumber uint8, operator *operatorstate.Operator, want *fixture.Operator, stake *big.Int, share float64) {
+	t.Helper()
+	if operator.OperatorID != want.OperatorID || operator.Address != want.Address || operator.Socket != want.Socket {
+		t.Errorf("quorum %d: operator %x at %s with socket %q, want %x at %s with socket %q", quorumNumber,
+			operator.OperatorID, operator.Address, operator.Socket, want.OperatorID, want.Address, want.Socket)
+	}
+	if operator.PubkeyG1.X.Cmp(want.PubkeyG1.X) != 0 || operator.PubkeyG1.Y.Cmp(want.PubkeyG1.Y) != 0 {
+		t.Errorf("quorum %d: operator %s has pubkey %v, want %v", quorumNumber, operator.Address, operator.PubkeyG1, want.PubkeyG1)
+	}
+	if operator.Stake.Cmp(stake) != 0 {
+		t.Errorf("quorum %d: operator %s has stake %s, want %s", quorumNumber, operator.Address, operator.Stake, stake)
+	}
+	if math.Abs(operator.StakeShare-share) > 1e-9 {
+		t.Errorf("quorum %d: operator %s has stake share %v, want %v", quorumNumber, operator.Address, operator.StakeShare, share)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	reader := newReader(t, f)
+	opts := &bind.CallOpts{Context: context.Background()}
+
+	before := headNumber(t, f)
+	snapshot, err := reader.Snapshot(opts, before, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.BlockNumber != before || len(snapshot.Quorums) != 2 {
+		t.Fatalf("snapshot at block %d with %d quorums, want block %d with 2", snapshot.BlockNumber, len(snapshot.Quorums), before)
+	}
+	for i, quorum := range snapshot.Quorums {
+		if quorum.QuorumNumber != uint8(i) || quorum.TotalStake.Cmp(ether(4)) != 0 || len(quorum.Operators) != len(f.Operators) {
+			t.Fatalf("quorum %d: number %d, total stake %s, %d operators", i, quorum.QuorumNumber, quorum.TotalStake, len(quorum.Operators))
+		}
+		// operators are in the order they registered in
+		for j, operator := range quorum.Operators {
+			checkOperator(t, quorum.QuorumNumber, operator, f.Operators[j], ether(1), 25)
+		}
+	}
+	if quorum, ok := snapshot.Quorum(1); !ok || quorum != snapshot.Quorums[1] {
+		t.Errorf("Quorum(1) = %v, %v", quorum, ok)
+	}
+	if _, ok := snapshot.Quorum(2); ok {
+		t.Error("Quorum(2) found in a snapshot of two quorums")
+	}
+
+	// operator 0 leaves quorum 1, operator 1 triples its stake in quorum 0 and operator 2 moves
+	if err := f.DeregisterOperator(f.Operators[0], []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetOperatorShares(f.Operators[1], 0, ether(3)); err != nil {
+		t.Fatal(err)
+	}
+	const socket = "moved.eigenda.test:32005;32006"
+	tx, err := f.RegistryCoordinator.UpdateSocket(f.TransactOpts(&f.Operators[2].Account), socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Mine(tx); err != nil {
+		t.Fatal(err)
+	}
+	after := headNumber(t, f)
+
+	newer, err := reader.Snapshot(opts, after, []byte{1, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(newer.Quorums) != 2 || newer.Quorums[0].QuorumNumber != 1 || newer.Quorums[1].QuorumNumber != 0 {
+		t.Fatalf("snapshot of quorums 1, 0 has quorums %v", newer.Quorums)
+	}
+	quorum0, _ := newer.Quorum(0)
+	if quorum0.TotalStake.Cmp(ether(6)) != 0 {
+		t.Errorf("quorum 0 total stake = %s, want %s", quorum0.TotalStake, ether(6))
+	}
+	moved := *f.Operators[2]
+	moved.Socket = socket
+	checkOperator(t, 0, quorum0.Operators[0], f.Operators[0], ether(1), 100.0/6)
+	checkOperator(t, 0, quorum0.Operators[1], f.Operators[1], ether(3), 50)
+	checkOperator(t, 0, quorum0.Operators[2], &moved, ether(1), 100.0/6)
+	quorum1, _ := newer.Quorum(1)
+	if quorum1.TotalStake.Cmp(ether(3)) != 0 || len(quorum1.Operators) != 3 {
+		t.Errorf("quorum 1 has total stake %s and %d operators, want %s and 3", quorum1.TotalStake, len(quorum1.Operators), ether(3))
+	}
+
+	want := []*operatorstate.Change{
+		{QuorumNumber: 0, Kind: operatorstate.StakeChanged, OperatorID: f.Operators[1].OperatorID, Address: f.Operators[1].Address, OldStake: ether(1), NewStake: ether(3)},
+		{QuorumNumber: 1, Kind: operatorstate.Left, OperatorID: f.Operators[0].OperatorID, Address: f.Operators[0].Address, OldStake: ether(1)},
+	}
+	checkChanges(t, operatorstate.Diff(snapshot, newer), want)
+
+	// stakes come from the histories, but sockets are always the current ones
+	again, err := reader.Snapshot(opts, before, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkChanges(t, operatorstate.Diff(snapshot, again), nil)
+	checkOperator(t, 0, again.Quorums[0].Operators[2], &moved, ether(1), 25)
+
+	if _, err := reader.Snapshot(opts, after, []byte{2}); err == nil {
+		t.Error("snapshot of a quorum that does not exist succeeded")
+	}
+}
+
+func checkChanges(t *testing.T, got, want []*operatorstate.Change) {
+	t.Helper()
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("changes = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	operator := func(id byte, stake int64) *operatorstate.Operator {
+		return &operatorstate.Operator{OperatorID: common.Hash{id}, Address: common.Address{id}, Stake: big.NewInt(stake)}
+	}
+	snapshot := func(quorums ...*operatorstate.Quorum) *operatorstate.Snapshot {
+		return &operatorstate.Snapshot{Quorums: quorums}
+	}
+	quorum := func(number uint8, operators ...*operatorstate.Operator) *operatorstate.Quorum {
+		return &operatorstate.Quorum{QuorumNumber: number, Operators: operators}
+	}
+	change := func(quorumNumber uint8, kind operatorstate.ChangeKind, id byte, oldStake, newStake int64) *operatorstate.Change {
+		c := &operatorstate.Change{QuorumNumber: quorumNumber, Kind: kind, OperatorID: common.Hash{id}, Address: common.Address{id}}
+		if kind != operatorstate.Joined {
+			c.OldStake = big.NewInt(oldStake)
+		}
+		if kind != operatorstate.Left {
+			c.NewStake = big.NewInt(newStake)
+		}
+		return c
+	}
+
+	for _, tc := range []struct {
+		name         string
+		older, newer *operatorstate.Snapshot
+		want         []*operatorstate.Change
+	}{
+		{
+			name:  "no changes",
+			older: snapshot(quorum(0, operator(1, 10), operator(2, 20))),
+			newer: snapshot(quorum(0, operator(2, 20), operator(1, 10))),
+		},
+		{
+			name:  "empty snapshots",
+			older: snapshot(),
+			newer: snapshot(),
+		},
+		{
+			name:  "ordered by quorum, kind and operator id",
+			older: snapshot(quorum(1, operator(3, 30), operator(1, 10)), quorum(0, operator(4, 40), operator(2, 20))),
+			newer: snapshot(quorum(0, operator(4, 41), operator(5, 50), operator(1, 10)), quorum(1, operator(3, 31), operator(2, 20))),
+			want: []*operatorstate.Change{
+				change(0, operatorstate.Joined, 1, 0, 10),
+				change(0, operatorstate.Joined, 5, 0, 50),
+				change(0, operatorstate.Left, 2, 20, 0),
+				change(0, operatorstate.StakeChanged, 4, 40, 41),
+				change(1, operatorstate.Joined, 2, 0, 20),
+				change(1, operatorstate.Left, 1, 10, 0),
+				change(1, operatorstate.StakeChanged, 3, 30, 31),
+			},
+		},
+		{
+			name:  "quorum only in the newer snapshot",
+			older: snapshot(),
+			newer: snapshot(quorum(3, operator(2, 20), operator(1, 10))),
+			want:  []*operatorstate.Change{change(3, operatorstate.Joined, 1, 0, 10), change(3, operatorstate.Joined, 2, 0, 20)},
+		},
+		{
+			name:  "quorum only in the older snapshot",
+			older: snapshot(quorum(3, operator(1, 10))),
+			newer: snapshot(quorum(0)),
+			want:  []*operatorstate.Change{change(3, operatorstate.Left, 1, 10, 0)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			checkChanges(t, operatorstate.Diff(tc.older, tc.newer), tc.want)
+		})
+	}
+}
+
+func TestChangeKindText(t *testing.T) {
+	for kind, want := range map[operatorstate.ChangeKind]string{
+		operatorstate.Joined:        "joined",
+		operatorstate.Left:          "left",
+		operatorstate.StakeChanged:  "stake-changed",
+		operatorstate.ChangeKind(7): "ChangeKind(7)",
+	} {
+		got, err := json.Marshal(kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != `"`+want+`"` {
+			t.Errorf("%d marshals to %s, want %q", int(kind), got, want)
+		}
+	}
+}
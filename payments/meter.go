@@ -0,0 +1,183 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	contractPaymentVault "github.com/Layr-Labs/eigenda/contracts/bindings/PaymentVault"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// Errors returned when metering usage against a reservation.
+var (
+	ErrReservationInactive = errors.New("reservation is not active at the dispersal timestamp")
+	ErrQuorumNotReserved   = errors.New("dispersal quorum is not covered by the reservation")
+	ErrReservationExceeded = errors.New("dispersal exceeds the reservation bandwidth of the period")
+)
+
+// MeterParams are the PaymentVault parameters that meter reservation usage.
+type MeterParams struct {
+	// ReservationPeriodInterval is the length in seconds of the periods reservation budgets are
+	// granted for.
+	ReservationPeriodInterval uint64
+	// MinNumSymbols is the minimum chargeable size of a blob.
+	MinNumSymbols uint64
+}
+
+// MeterParamsFromContract loads the current metering parameters of a PaymentVault.
+func MeterParamsFromContract(
+	opts *bind.CallOpts,
+	caller *contractPaymentVault.ContractPaymentVaultCaller,
+) (MeterParams, error) {
+	reservationPeriodInterval, err := caller.ReservationPeriodInterval(opts)
+	if err != nil {
+		return MeterParams{}, err
+	}
+	minNumSymbols, err := caller.MinNumSymbols(opts)
+	if err != nil {
+		return MeterParams{}, err
+	}
+	return MeterParams{
+		ReservationPeriodInterval: reservationPeriodInterval,
+		MinNumSymbols:             minNumSymbols,
+	}, nil
+}
+
+// UsageStore persists the per-quorum usage of reservations in each reservation period.
+// Implementations must be safe for concurrent use.
+type UsageStore interface {
+	// AddUsage atomically charges usage to account in period, spilling into overflowPeriod as
+	// ChargeUsage allows. If any quorum cannot be charged, nothing is recorded and false is returned.
+	AddUsage(ctx context.Context, account common.Address, period, overflowPeriod uint64, usage, limits map[uint8]uint64) (bool, error)
+	// Usage returns the usage of account in period.
+	Usage(ctx context.Context, account common.Address, period uint64) (map[uint8]uint64, error)
+	// PruneBefore drops the usage of every period before period.
+	PruneBefore(ctx context.Context, period uint64) error
+}
+
+// overflowPeriods is how many periods later the overflow of a period is charged. Skipping the
+// next period leaves it to the dispersals of accounts whose clocks run ahead.
+const overflowPeriods = 2
+
+// ChargeUsage returns the usage of period and overflowPeriod after charging usage on top of
+// current and overflow, or false if some quorum cannot be charged. A quorum is charged in period
+// up to its limit. Only a quorum that has budget left in period, and whose usage alone fits in
+// one period, may charge the excess to overflowPeriod, and only if it has not used it yet.
+// UsageStore implementations share it so that every store reaches the same decisions.
+func ChargeUsage(current, overflow, usage, limits map[uint8]uint64) (map[uint8]uint64, map[uint8]uint64, bool) {
+	newCurrent := make(map[uint8]uint64, len(usage))
+	newOverflow := make(map[uint8]uint64)
+	for quorumNumber, symbols := range usage {
+		used, limit := current[quorumNumber], limits[quorumNumber]
+		switch {
+		case used <= limit && symbols <= limit-used:
+			newCurrent[quorumNumber] = used + symbols
+		case used >= limit || symbols > limit || overflow[quorumNumber] != 0:
+			return nil, nil, false
+		default:
+			// used < limit < used + symbols, and symbols - (limit - used) <= symbols <= limit
+			newCurrent[quorumNumber] = limit
+			newOverflow[quorumNumber] = symbols - (limit - used)
+		}
+	}
+	return newCurrent, newOverflow, true
+}
+
+// Meter enforces reservation bandwidth. Each dispersal is charged to the reservation period of
+// its timestamp and split across the reservation's quorums by quorumSplits; a quorum may not use
+// more than its share of symbolsPerSecond * reservationPeriodInterval in a period.
+//
+// Periods are fixed buckets aligned to multiples of reservationPeriodInterval, not a sliding
+// window: every period starts with a full budget, so an account can use up to two budgets in an
+// interval straddling a period boundary. A dispersal that does not fit in what is left of its
+// period is still accepted if the period is not used up and the dispersal fits in one budget;
+// the excess is charged two periods later, which must be unused by then.
+//
+// Clients and dispersers metering the same account with the same parameters reach the same
+// decisions, whichever UsageStore they use.
+type Meter struct {
+	params MeterParams
+	store  UsageStore
+}
+
+// NewMeter returns a Meter recording usage in store.
+func NewMeter(params MeterParams, store UsageStore) *Meter {
+	return &Meter{params: params, store: store}
+}
+
+// Record charges a dispersal of numSymbols to quorumNumbers at timestamp against the reservation
+// of account. The charge is rounded up to MinNumSymbols like on-demand payments.
+func (m *Meter) Record(
+	ctx context.Context,
+	account common.Address,
+	reservation structs.Reservation,
+	timestamp uint64,
+	numSymbols uint64,
+	quorumNumbers []byte,
+) error {
+	if err := m.check(reservation, timestamp, quorumNumbers); err != nil {
+		return err
+	}
+	usage := SplitUsage(reservation, PriceParams{MinNumSymbols: m.params.MinNumSymbols}.SymbolsCharged(numSymbols))
+	period := ReservationPeriod(timestamp, m.params.ReservationPeriodInterval)
+	if period > math.MaxUint64-overflowPeriods {
+		return ErrReservationExceeded
+	}
+	ok, err := m.store.AddUsage(ctx, account, period, period+overflowPeriods, usage, QuorumBudgets(reservation, m.params.ReservationPeriodInterval))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrReservationExceeded
+	}
+	return nil
+}
+
+// Remaining returns how many symbols each quorum of the reservation of account may still use
+// in the reservation period of timestamp.
+func (m *Meter) Remaining(
+	ctx context.Context,
+	account common.Address,
+	reservation structs.Reservation,
+	timestamp uint64,
+) (map[uint8]uint64, error) {
+	if err := ValidateReservation(reservation); err != nil {
+		return nil, err
+	}
+	period := ReservationPeriod(timestamp, m.params.ReservationPeriodInterval)
+	usage, err := m.store.Usage(ctx, account, period)
+	if err != nil {
+		return nil, err
+	}
+	remaining := QuorumBudgets(reservation, m.params.ReservationPeriodInterval)
+	for quorumNumber, budget := range remaining {
+		remaining[quorumNumber] = budget - min(budget, usage[quorumNumber])
+	}
+	return remaining, nil
+}
+
+// Prune drops the usage of the periods before the one of timestamp, which can no longer be charged.
+func (m *Meter) Prune(ctx context.Context, timestamp uint64) error {
+	return m.store.PruneBefore(ctx, ReservationPeriod(timestamp, m.params.ReservationPeriodInterval))
+}
+
+func (m *Meter) check(reservation structs.Reservation, timestamp uint64, quorumNumbers []byte) error {
+	if err := ValidateReservation(reservation); err != nil {
+		return err
+	}
+	if !IsActive(reservation, timestamp) {
+		return ErrReservationInactive
+	}
+	for _, quorumNumber := range quorumNumbers {
+		if !slices.Contains(reservation.QuorumNumbers, quorumNumber) {
+			return fmt.Errorf("%w: quorum %d", ErrQuorumNotReserved, quorumNumber)
+		}
+	}
+	return nil
+}
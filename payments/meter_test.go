@@ -0,0 +1,257 @@
+package payments_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Layr-Labs/eigenda/contracts/payments"
+	"github.com/Layr-Labs/eigenda/contracts/reverts"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+// testReservation grants 10 symbols per second over [100, 1000), split 60/40 across quorums 0 and 1.
+var testReservation = structs.Reservation{
+	SymbolsPerSecond: 10,
+	StartTimestamp:   100,
+	EndTimestamp:     1000,
+	QuorumNumbers:    []byte{0, 1},
+	QuorumSplits:     []byte{60, 40},
+}
+
+// testMeterParams give the reservation a budget of 1000 symbols per 100 second period, so 600
+// symbols for quorum 0 and 400 for quorum 1.
+var testMeterParams = payments.MeterParams{ReservationPeriodInterval: 100, MinNumSymbols: 10}
+
+func TestValidateReservation(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	account := common.HexToAddress("0x1234")
+
+	for _, tc := range []struct {
+		name         string
+		modify       func(*structs.Reservation)
+		want         error
+		wantContract error
+	}{
+		{name: "valid", modify: func(*structs.Reservation) {}},
+		{
+			name:   "length mismatch",
+			modify: func(r *structs.Reservation) { r.QuorumSplits = []byte{100} },
+			want:   payments.ErrQuorumSplitsLengthMismatch,
+		},
+		{
+			name:   "splits below 100",
+			modify: func(r *structs.Reservation) { r.QuorumSplits = []byte{60, 39} },
+			want:   payments.ErrQuorumSplitsSum,
+		},
+		{
+			name:   "splits wrap to 100",
+			modify: func(r *structs.Reservation) { r.QuorumSplits = []byte{200, 156} },
+			// a wrapping uint8 sum would be 100
+			want:         payments.ErrQuorumSplitsOverflow,
+			wantContract: reverts.ErrPanicArithmetic,
+		},
+		{
+			name:   "empty window",
+			modify: func(r *structs.Reservation) { r.EndTimestamp = r.StartTimestamp },
+			want:   payments.ErrReservationWindowInvalid,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reservation := testReservation
+			tc.modify(&reservation)
+			if err := payments.ValidateReservation(reservation); !errors.Is(err, tc.want) {
+				t.Errorf("ValidateReservation = %v, want %v", err, tc.want)
+			}
+
+			wantContract := tc.wantContract
+			if wantContract == nil {
+				wantContract = tc.want
+			}
+			_, err := f.PaymentVault.SetReservation(f.TransactOpts(f.Owner), account, reservation)
+			if err := reverts.Decode(err); !errors.Is(err, wantContract) {
+				t.Errorf("setReservation = %v, want %v", err, wantContract)
+			}
+		})
+	}
+}
+
+func TestQuorumBudgets(t *testing.T) {
+	budgets := payments.QuorumBudgets(testReservation, testMeterParams.ReservationPeriodInterval)
+	if want := map[uint8]uint64{0: 600, 1: 400}; !reflect.DeepEqual(budgets, want) {
+		t.Errorf("QuorumBudgets = %v, want %v", budgets, want)
+	}
+
+	huge := testReservation
+	huge.SymbolsPerSecond = math.MaxUint64
+	if budget := payments.PeriodBudget(huge, 2); budget != math.MaxUint64 {
+		t.Errorf("PeriodBudget = %d, want it to saturate", budget)
+	}
+	budgets = payments.QuorumBudgets(huge, 2)
+	if want := map[uint8]uint64{0: math.MaxUint64 / 100 * 60, 1: math.MaxUint64 / 100 * 40}; budgets[0] < want[0] || budgets[1] < want[1] {
+		t.Errorf("QuorumBudgets = %v, want at least %v", budgets, want)
+	}
+
+	// usage is rounded up per quorum, budgets down
+	if usage := payments.SplitUsage(testReservation, 11); !reflect.DeepEqual(usage, map[uint8]uint64{0: 7, 1: 5}) {
+		t.Errorf("SplitUsage = %v", usage)
+	}
+}
+
+func TestReservationPeriod(t *testing.T) {
+	for _, tc := range []struct {
+		timestamp, interval, want uint64
+	}{
+		{timestamp: 0, interval: 100, want: 0},
+		{timestamp: 99, interval: 100, want: 0},
+		{timestamp: 100, interval: 100, want: 1},
+		{timestamp: 12345, interval: 0, want: 0},
+	} {
+		if got := payments.ReservationPeriod(tc.timestamp, tc.interval); got != tc.want {
+			t.Errorf("ReservationPeriod(%d, %d) = %d, want %d", tc.timestamp, tc.interval, got, tc.want)
+		}
+	}
+}
+
+func TestChargeUsage(t *testing.T) {
+	limits := map[uint8]uint64{0: 100}
+	for _, tc := range []struct {
+		name                      string
+		current, overflow, usage  map[uint8]uint64
+		wantCurrent, wantOverflow map[uint8]uint64
+		wantOK                    bool
+	}{
+		{
+			name:        "fits",
+			usage:       map[uint8]uint64{0: 40},
+			current:     map[uint8]uint64{0: 60},
+			wantCurrent: map[uint8]uint64{0: 100}, wantOverflow: map[uint8]uint64{}, wantOK: true,
+		},
+		{
+			name:        "overflows",
+			usage:       map[uint8]uint64{0: 50},
+			current:     map[uint8]uint64{0: 60},
+			wantCurrent: map[uint8]uint64{0: 100}, wantOverflow: map[uint8]uint64{0: 10}, wantOK: true,
+		},
+		{
+			name:        "overflows a whole budget",
+			usage:       map[uint8]uint64{0: 100},
+			current:     map[uint8]uint64{0: 1},
+			wantCurrent: map[uint8]uint64{0: 100}, wantOverflow: map[uint8]uint64{0: 1}, wantOK: true,
+		},
+		{
+			name:    "period used up",
+			usage:   map[uint8]uint64{0: 1},
+			current: map[uint8]uint64{0: 100},
+		},
+		{
+			name:  "larger than a budget",
+			usage: map[uint8]uint64{0: 101},
+		},
+		{
+			name:     "overflow period in use",
+			usage:    map[uint8]uint64{0: 50},
+			current:  map[uint8]uint64{0: 60},
+			overflow: map[uint8]uint64{0: 1},
+		},
+		{
+			name:    "no budget",
+			usage:   map[uint8]uint64{1: 1},
+			current: map[uint8]uint64{0: 60},
+		},
+		{
+			name:    "no arithmetic overflow",
+			usage:   map[uint8]uint64{0: math.MaxUint64},
+			current: map[uint8]uint64{0: 60},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			current, overflow, ok := payments.ChargeUsage(tc.current, tc.overflow, tc.usage, limits)
+			if ok != tc.wantOK {
+				t.Fatalf("ChargeUsage ok = %v, want %v", ok, tc.wantOK)
+			}
+			if ok && (!reflect.DeepEqual(current, tc.wantCurrent) || !reflect.DeepEqual(overflow, tc.wantOverflow)) {
+				t.Errorf("ChargeUsage = %v, %v, want %v, %v", current, overflow, tc.wantCurrent, tc.wantOverflow)
+			}
+		})
+	}
+}
+
+// testMeter runs the same sequence of dispersals against a Meter backed by store.
+func testMeter(t *testing.T, store payments.UsageStore) {
+	ctx := context.Background()
+	meter := payments.NewMeter(testMeterParams, store)
+	account := common.HexToAddress("0x1234")
+	other := common.HexToAddress("0x5678")
+
+	remaining := func(timestamp uint64) map[uint8]uint64 {
+		t.Helper()
+		remaining, err := meter.Remaining(ctx, account, testReservation, timestamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return remaining
+	}
+
+	for _, step := range []struct {
+		name          string
+		account       common.Address
+		timestamp     uint64
+		numSymbols    uint64
+		quorumNumbers []byte
+		want          error
+		wantRemaining map[uint8]uint64
+	}{
+		// 1 symbol is charged as 10, 6 to quorum 0 and 4 to quorum 1
+		{name: "minimum charge", timestamp: 100, numSymbols: 1, quorumNumbers: []byte{0, 1}, wantRemaining: map[uint8]uint64{0: 594, 1: 396}},
+		{name: "before start", timestamp: 99, numSymbols: 10, want: payments.ErrReservationInactive},
+		{name: "at end", timestamp: 1000, numSymbols: 10, want: payments.ErrReservationInactive},
+		{name: "unreserved quorum", timestamp: 150, numSymbols: 10, quorumNumbers: []byte{0, 2}, want: payments.ErrQuorumNotReserved},
+		{name: "larger than a budget", timestamp: 150, numSymbols: 1010, want: payments.ErrReservationExceeded},
+		{name: "fill", timestamp: 199, numSymbols: 980, wantRemaining: map[uint8]uint64{0: 6, 1: 4}},
+		// 20 symbols need 12 and 8, of which 6 and 4 overflow into period 3
+		{name: "overflow", timestamp: 199, numSymbols: 20, wantRemaining: map[uint8]uint64{0: 0, 1: 0}},
+		{name: "period used up", timestamp: 150, numSymbols: 10, want: payments.ErrReservationExceeded},
+		{name: "other account", account: other, timestamp: 150, numSymbols: 10},
+		{name: "next period", timestamp: 250, numSymbols: 1000, wantRemaining: map[uint8]uint64{0: 0, 1: 0}},
+		{name: "overflow period", timestamp: 350, numSymbols: 10, wantRemaining: map[uint8]uint64{0: 588, 1: 392}},
+	} {
+		if step.account == (common.Address{}) {
+			step.account = account
+		}
+		err := meter.Record(ctx, step.account, testReservation, step.timestamp, step.numSymbols, step.quorumNumbers)
+		if !errors.Is(err, step.want) {
+			t.Fatalf("%s: Record = %v, want %v", step.name, err, step.want)
+		}
+		if step.wantRemaining != nil {
+			if got := remaining(step.timestamp); !reflect.DeepEqual(got, step.wantRemaining) {
+				t.Errorf("%s: Remaining = %v, want %v", step.name, got, step.wantRemaining)
+			}
+		}
+	}
+
+	if err := meter.Prune(ctx, 250); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := remaining(150), map[uint8]uint64{0: 600, 1: 400}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Remaining after Prune = %v, want %v", got, want)
+	}
+	if got, want := remaining(250), map[uint8]uint64{0: 0, 1: 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Remaining of the current period after Prune = %v, want %v", got, want)
+	}
+
+	invalid := testReservation
+	invalid.QuorumSplits = []byte{50, 40}
+	if err := meter.Record(ctx, account, invalid, 150, 10, nil); !errors.Is(err, payments.ErrQuorumSplitsSum) {
+		t.Errorf("Record with an invalid reservation = %v, want %v", err, payments.ErrQuorumSplitsSum)
+	}
+}
+
+func TestMeterMemory(t *testing.T) {
+	testMeter(t, payments.NewMemoryUsageStore())
+}
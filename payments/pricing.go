@@ -0,0 +1,161 @@
+// Package payments prices and meters dispersals against the parameters of PaymentVault, so
+// disperser clients and billing tools charge blobs the same way.
+package payments
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	contractPaymentVault "github.com/Layr-Labs/eigenda/contracts/bindings/PaymentVault"
+)
+
+// SymbolSize is the size in bytes of a symbol, the unit blobs are measured and priced in.
+const SymbolSize = 32
+
+// PriceParams are the PaymentVault parameters that price on-demand dispersals.
+type PriceParams struct {
+	// MinNumSymbols is the minimum chargeable size of a blob. Blobs are charged in multiples of it.
+	MinNumSymbols uint64
+	// PricePerSymbol is the price of a symbol in wei.
+	PricePerSymbol uint64
+}
+
+// PriceParamsFromContract loads the current price parameters of a PaymentVault.
+func PriceParamsFromContract(
+	opts *bind.CallOpts,
+	caller *contractPaymentVault.ContractPaymentVaultCaller,
+) (PriceParams, error) {
+	minNumSymbols, err := caller.MinNumSymbols(opts)
+	if err != nil {
+		return PriceParams{}, err
+	}
+	pricePerSymbol, err := caller.PricePerSymbol(opts)
+	if err != nil {
+		return PriceParams{}, err
+	}
+	return PriceParams{
+		MinNumSymbols:  minNumSymbols,
+		PricePerSymbol: pricePerSymbol,
+	}, nil
+}
+
+// NumSymbols returns the number of symbols a blob of dataLength bytes occupies.
+func NumSymbols(dataLength uint64) uint64 {
+	return dataLength/SymbolSize + min(dataLength%SymbolSize, 1)
+}
+
+// SymbolsCharged rounds numSymbols up to a multiple of MinNumSymbols, so that every blob is
+// charged for at least MinNumSymbols. A zero MinNumSymbols charges numSymbols as is. It saturates
+// at math.MaxUint64.
+func (p PriceParams) SymbolsCharged(numSymbols uint64) uint64 {
+	if p.MinNumSymbols == 0 {
+		return numSymbols
+	}
+	if numSymbols <= p.MinNumSymbols {
+		return p.MinNumSymbols
+	}
+	chunks := numSymbols / p.MinNumSymbols
+	if numSymbols%p.MinNumSymbols != 0 {
+		chunks++
+	}
+	hi, lo := bits.Mul64(chunks, p.MinNumSymbols)
+	if hi != 0 {
+		return math.MaxUint64
+	}
+	return lo
+}
+
+// Cost returns the price in wei of dispersing a blob of numSymbols symbols on demand.
+func (p PriceParams) Cost(numSymbols uint64) *big.Int {
+	symbols := new(big.Int).SetUint64(p.SymbolsCharged(numSymbols))
+	return symbols.Mul(symbols, new(big.Int).SetUint64(p.PricePerSymbol))
+}
+
+// Quote is what an account can still afford to disperse on demand at a blob size.
+type Quote struct {
+	Account common.Address
+	// NumSymbols is the quoted blob size and SymbolsCharged the size it is charged for.
+	NumSymbols     uint64
+	SymbolsCharged uint64
+	// Cost is the price of one blob in wei.
+	Cost *big.Int
+	// TotalDeposit is the account's on-demand deposit in the PaymentVault and CumulativePayment
+	// what it has spent of it so far.
+	TotalDeposit      *big.Int
+	CumulativePayment *big.Int
+	// Remaining is the unspent deposit, zero if the account has overspent.
+	Remaining *big.Int
+	// AffordableBlobs is how many more blobs of NumSymbols the remaining deposit pays for. It
+	// saturates at math.MaxUint64, which is also its value when blobs are free.
+	AffordableBlobs uint64
+}
+
+// Quote prices a blob of numSymbols for account, given its on-demand deposit and the cumulative
+// payment the client has tracked for it. A nil cumulativePayment counts as nothing spent.
+func (p PriceParams) Quote(
+	account common.Address,
+	numSymbols uint64,
+	totalDeposit *big.Int,
+	cumulativePayment *big.Int,
+) *Quote {
+	if cumulativePayment == nil {
+		cumulativePayment = new(big.Int)
+	}
+	quote := &Quote{
+		Account:           account,
+		NumSymbols:        numSymbols,
+		SymbolsCharged:    p.SymbolsCharged(numSymbols),
+		Cost:              p.Cost(numSymbols),
+		TotalDeposit:      new(big.Int).Set(totalDeposit),
+		CumulativePayment: new(big.Int).Set(cumulativePayment),
+		Remaining:         new(big.Int).Sub(totalDeposit, cumulativePayment),
+	}
+	if quote.Remaining.Sign() < 0 {
+		quote.Remaining.SetUint64(0)
+	}
+
+	if quote.Cost.Sign() == 0 {
+		quote.AffordableBlobs = math.MaxUint64
+		return quote
+	}
+	affordable := new(big.Int).Quo(quote.Remaining, quote.Cost)
+	if affordable.IsUint64() {
+		quote.AffordableBlobs = affordable.Uint64()
+	} else {
+		quote.AffordableBlobs = math.MaxUint64
+	}
+	return quote
+}
+
+// Quoter quotes on-demand dispersals against a deployed PaymentVault.
+type Quoter struct {
+	caller *contractPaymentVault.ContractPaymentVaultCaller
+}
+
+// NewQuoter returns a Quoter reading prices and deposits through caller.
+func NewQuoter(caller *contractPaymentVault.ContractPaymentVaultCaller) *Quoter {
+	return &Quoter{caller: caller}
+}
+
+// Quote prices a blob of numSymbols for account at the current price parameters and deposit.
+// Pin opts.BlockNumber to read both at the same block.
+func (q *Quoter) Quote(
+	opts *bind.CallOpts,
+	account common.Address,
+	numSymbols uint64,
+	cumulativePayment *big.Int,
+) (*Quote, error) {
+	params, err := PriceParamsFromContract(opts, q.caller)
+	if err != nil {
+		return nil, err
+	}
+	totalDeposit, err := q.caller.GetOnDemandTotalDeposit(opts, account)
+	if err != nil {
+		return nil, err
+	}
+	return params.Quote(account, numSymbols, totalDeposit, cumulativePayment), nil
+}
@@ -0,0 +1,135 @@
+package payments_test
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Layr-Labs/eigenda/contracts/payments"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+func TestNumSymbols(t *testing.T) {
+	for _, tc := range []struct {
+		dataLength uint64
+		want       uint64
+	}{
+		{dataLength: 0, want: 0},
+		{dataLength: 1, want: 1},
+		{dataLength: 31, want: 1},
+		{dataLength: 32, want: 1},
+		{dataLength: 33, want: 2},
+		{dataLength: 64, want: 2},
+		{dataLength: math.MaxUint64, want: math.MaxUint64/32 + 1},
+	} {
+		if got := payments.NumSymbols(tc.dataLength); got != tc.want {
+			t.Errorf("NumSymbols(%d) = %d, want %d", tc.dataLength, got, tc.want)
+		}
+	}
+}
+
+func TestSymbolsCharged(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		minNumSymbols uint64
+		numSymbols    uint64
+		want          uint64
+	}{
+		{name: "no minimum", minNumSymbols: 0, numSymbols: 5, want: 5},
+		{name: "no minimum, empty", minNumSymbols: 0, numSymbols: 0, want: 0},
+		{name: "empty", minNumSymbols: 4096, numSymbols: 0, want: 4096},
+		{name: "below minimum", minNumSymbols: 4096, numSymbols: 1, want: 4096},
+		{name: "at minimum", minNumSymbols: 4096, numSymbols: 4096, want: 4096},
+		{name: "just above minimum", minNumSymbols: 4096, numSymbols: 4097, want: 8192},
+		{name: "multiple", minNumSymbols: 4096, numSymbols: 3 * 4096, want: 3 * 4096},
+		{name: "just above multiple", minNumSymbols: 4096, numSymbols: 3*4096 + 1, want: 4 * 4096},
+		{name: "minimum of one", minNumSymbols: 1, numSymbols: 12345, want: 12345},
+		{name: "largest multiple", minNumSymbols: 3, numSymbols: math.MaxUint64 - 1, want: math.MaxUint64},
+		{name: "saturates", minNumSymbols: 2, numSymbols: math.MaxUint64, want: math.MaxUint64},
+		{name: "minimum of max", minNumSymbols: math.MaxUint64, numSymbols: 7, want: math.MaxUint64},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			params := payments.PriceParams{MinNumSymbols: tc.minNumSymbols}
+			if got := params.SymbolsCharged(tc.numSymbols); got != tc.want {
+				t.Errorf("SymbolsCharged(%d) = %d, want %d", tc.numSymbols, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	account := common.HexToAddress("0x1234")
+	params := payments.PriceParams{MinNumSymbols: 4, PricePerSymbol: 10}
+
+	for _, tc := range []struct {
+		name              string
+		params            payments.PriceParams
+		numSymbols        uint64
+		totalDeposit      int64
+		cumulativePayment *big.Int
+		wantCost          int64
+		wantRemaining     int64
+		wantAffordable    uint64
+	}{
+		{name: "nothing spent", params: params, numSymbols: 3, totalDeposit: 100, wantCost: 40, wantRemaining: 100, wantAffordable: 2},
+		{name: "partly spent", params: params, numSymbols: 5, totalDeposit: 1000, cumulativePayment: big.NewInt(150), wantCost: 80, wantRemaining: 850, wantAffordable: 10},
+		{name: "exactly affordable", params: params, numSymbols: 4, totalDeposit: 120, wantCost: 40, wantRemaining: 120, wantAffordable: 3},
+		{name: "overspent", params: params, numSymbols: 4, totalDeposit: 100, cumulativePayment: big.NewInt(101), wantCost: 40, wantRemaining: 0, wantAffordable: 0},
+		{name: "free", params: payments.PriceParams{MinNumSymbols: 4}, numSymbols: 4, totalDeposit: 0, wantCost: 0, wantRemaining: 0, wantAffordable: math.MaxUint64},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			quote := tc.params.Quote(account, tc.numSymbols, big.NewInt(tc.totalDeposit), tc.cumulativePayment)
+			if quote.Account != account || quote.NumSymbols != tc.numSymbols || quote.SymbolsCharged != tc.params.SymbolsCharged(tc.numSymbols) {
+				t.Errorf("quote = %+v", quote)
+			}
+			if quote.Cost.Cmp(big.NewInt(tc.wantCost)) != 0 {
+				t.Errorf("Cost = %v, want %d", quote.Cost, tc.wantCost)
+			}
+			if quote.Remaining.Cmp(big.NewInt(tc.wantRemaining)) != 0 {
+				t.Errorf("Remaining = %v, want %d", quote.Remaining, tc.wantRemaining)
+			}
+			if quote.AffordableBlobs != tc.wantAffordable {
+				t.Errorf("AffordableBlobs = %d, want %d", quote.AffordableBlobs, tc.wantAffordable)
+			}
+		})
+	}
+
+	// the cost of the largest charge does not fit in a uint64
+	huge := payments.PriceParams{MinNumSymbols: 1, PricePerSymbol: 2}.Cost(math.MaxUint64)
+	if want := new(big.Int).Lsh(new(big.Int).SetUint64(math.MaxUint64), 1); huge.Cmp(want) != 0 {
+		t.Errorf("Cost(MaxUint64) = %v, want %v", huge, want)
+	}
+}
+
+func TestQuoter(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	account := f.Operators[0].Address
+
+	opts := f.TransactOpts(f.Owner)
+	opts.Value = big.NewInt(1000)
+	tx, err := f.PaymentVault.DepositOnDemand(opts, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Mine(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	quote, err := payments.NewQuoter(&f.PaymentVault.ContractPaymentVaultCaller).Quote(
+		&bind.CallOpts{Context: context.Background()}, account, 10, big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 10 symbols at fixture.PricePerSymbol wei each, with a minimum of fixture.MinNumSymbols
+	wantCost := int64(10 * fixture.PricePerSymbol)
+	if quote.TotalDeposit.Cmp(big.NewInt(1000)) != 0 || quote.Cost.Cmp(big.NewInt(wantCost)) != 0 {
+		t.Errorf("quote = %+v, want deposit 1000 and cost %d", quote, wantCost)
+	}
+	if want := uint64(900 / wantCost); quote.AffordableBlobs != want {
+		t.Errorf("AffordableBlobs = %d, want %d", quote.AffordableBlobs, want)
+	}
+}
@@ -0,0 +1,201 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	contractPaymentVault "github.com/Layr-Labs/eigenda/contracts/bindings/PaymentVault"
+)
+
+// ErrGlobalRateExceeded is returned when an on-demand dispersal does not fit in the network-wide
+// capacity left in the current period.
+var ErrGlobalRateExceeded = errors.New("dispersal exceeds the global on-demand rate of the period")
+
+// Clock tells the rate limiter the time. Tests substitute a fake one.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock of the local system.
+type SystemClock struct{}
+
+// Now implements Clock.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// GlobalRateParams are the PaymentVault parameters that cap on-demand dispersals network-wide.
+type GlobalRateParams struct {
+	// GlobalSymbolsPerPeriod is the most symbols dispersed on demand per period.
+	GlobalSymbolsPerPeriod uint64
+	// GlobalRatePeriodInterval is the length of a period in seconds. Periods are aligned to
+	// multiples of it.
+	GlobalRatePeriodInterval uint64
+}
+
+// GlobalRateParamsFromContract loads the current global rate parameters of a PaymentVault.
+func GlobalRateParamsFromContract(
+	opts *bind.CallOpts,
+	caller *contractPaymentVault.ContractPaymentVaultCaller,
+) (GlobalRateParams, error) {
+	globalSymbolsPerPeriod, err := caller.GlobalSymbolsPerPeriod(opts)
+	if err != nil {
+		return GlobalRateParams{}, err
+	}
+	globalRatePeriodInterval, err := caller.GlobalRatePeriodInterval(opts)
+	if err != nil {
+		return GlobalRateParams{}, err
+	}
+	return GlobalRateParams{
+		GlobalSymbolsPerPeriod:   globalSymbolsPerPeriod,
+		GlobalRatePeriodInterval: globalRatePeriodInterval,
+	}, nil
+}
+
+// GlobalRateLimiter admits on-demand dispersals while they fit in GlobalSymbolsPerPeriod for the
+// current period. It is safe for concurrent use.
+type GlobalRateLimiter struct {
+	clock Clock
+
+	mu     sync.Mutex
+	params GlobalRateParams
+	period uint64
+	used   uint64
+}
+
+// NewGlobalRateLimiter returns a limiter enforcing params, with nothing used yet.
+func NewGlobalRateLimiter(params GlobalRateParams, clock Clock) *GlobalRateLimiter {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	l := &GlobalRateLimiter{clock: clock, params: params}
+	l.period = l.currentPeriod()
+	return l
+}
+
+// Admit charges numSymbols to the current period, or returns ErrGlobalRateExceeded and charges
+// nothing if they do not fit.
+func (l *GlobalRateLimiter) Admit(numSymbols uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance()
+	if numSymbols > l.params.GlobalSymbolsPerPeriod-min(l.used, l.params.GlobalSymbolsPerPeriod) {
+		return ErrGlobalRateExceeded
+	}
+	l.used += numSymbols
+	return nil
+}
+
+// Remaining returns the symbols still available in the current period.
+func (l *GlobalRateLimiter) Remaining() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance()
+	return l.params.GlobalSymbolsPerPeriod - min(l.used, l.params.GlobalSymbolsPerPeriod)
+}
+
+// Params returns the parameters currently enforced.
+func (l *GlobalRateLimiter) Params() GlobalRateParams {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.params
+}
+
+// SetParams changes the parameters enforced. Usage of the current period is kept unless the new
+// interval puts the clock in a different period.
+func (l *GlobalRateLimiter) SetParams(params GlobalRateParams) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.params = params
+	l.advance()
+}
+
+// Refresh reloads the parameters from the PaymentVault.
+func (l *GlobalRateLimiter) Refresh(opts *bind.CallOpts, caller *contractPaymentVault.ContractPaymentVaultCaller) error {
+	params, err := GlobalRateParamsFromContract(opts, caller)
+	if err != nil {
+		return err
+	}
+	l.SetParams(params)
+	return nil
+}
+
+// Watch keeps the parameters in sync with the PaymentVault until ctx is done or a subscription
+// fails. It loads them once after subscribing, then applies every GlobalSymbolsPerPeriodUpdated
+// and GlobalRatePeriodIntervalUpdated event. An update removed by a reorg reloads the parameters
+// rather than restoring the event's PreviousValue: when several updates are reorged out and the
+// new chain brings its own, the removed and added logs interleave, and only the contract state
+// at the head says which value is in force.
+func (l *GlobalRateLimiter) Watch(ctx context.Context, vault *contractPaymentVault.ContractPaymentVault) error {
+	symbolsUpdates := make(chan *contractPaymentVault.ContractPaymentVaultGlobalSymbolsPerPeriodUpdated)
+	symbolsSub, err := vault.WatchGlobalSymbolsPerPeriodUpdated(&bind.WatchOpts{Context: ctx}, symbolsUpdates)
+	if err != nil {
+		return err
+	}
+	defer symbolsSub.Unsubscribe()
+	intervalUpdates := make(chan *contractPaymentVault.ContractPaymentVaultGlobalRatePeriodIntervalUpdated)
+	intervalSub, err := vault.WatchGlobalRatePeriodIntervalUpdated(&bind.WatchOpts{Context: ctx}, intervalUpdates)
+	if err != nil {
+		return err
+	}
+	defer intervalSub.Unsubscribe()
+
+	// Subscribing first means no update is missed between the load and the subscriptions.
+	if err := l.Refresh(&bind.CallOpts{Context: ctx}, &vault.ContractPaymentVaultCaller); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event := <-symbolsUpdates:
+			if event.Raw.Removed {
+				if err := l.Refresh(&bind.CallOpts{Context: ctx}, &vault.ContractPaymentVaultCaller); err != nil {
+					return err
+				}
+				continue
+			}
+			l.mu.Lock()
+			l.params.GlobalSymbolsPerPeriod = event.NewValue
+			l.mu.Unlock()
+		case event := <-intervalUpdates:
+			if event.Raw.Removed {
+				if err := l.Refresh(&bind.CallOpts{Context: ctx}, &vault.ContractPaymentVaultCaller); err != nil {
+					return err
+				}
+				continue
+			}
+			l.mu.Lock()
+			l.params.GlobalRatePeriodInterval = event.NewValue
+			l.advance()
+			l.mu.Unlock()
+		case err := <-symbolsSub.Err():
+			return err
+		case err := <-intervalSub.Err():
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// advance resets the usage when the clock has moved to another period. l.mu must be held.
+func (l *GlobalRateLimiter) advance() {
+	if period := l.currentPeriod(); period != l.period {
+		l.period = period
+		l.used = 0
+	}
+}
+
+func (l *GlobalRateLimiter) currentPeriod() uint64 {
+	now := l.clock.Now().Unix()
+	if now < 0 {
+		return 0
+	}
+	return periodIndex(uint64(now), l.params.GlobalRatePeriodInterval)
+}
@@ -0,0 +1,178 @@
+package payments_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	contractPaymentVault "github.com/Layr-Labs/eigenda/contracts/bindings/PaymentVault"
+	"github.com/Layr-Labs/eigenda/contracts/payments"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+// fakeClock is a Clock that only moves when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(unix int64) *fakeClock {
+	return &fakeClock{now: time.Unix(unix, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestGlobalRateLimiter(t *testing.T) {
+	// periods of 10 seconds starting at multiples of 10, with 100 symbols each
+	clock := newFakeClock(1000)
+	limiter := payments.NewGlobalRateLimiter(payments.GlobalRateParams{GlobalSymbolsPerPeriod: 100, GlobalRatePeriodInterval: 10}, clock)
+
+	admit := func(numSymbols uint64, want error) {
+		t.Helper()
+		if err := limiter.Admit(numSymbols); !errors.Is(err, want) {
+			t.Fatalf("Admit(%d) = %v, want %v", numSymbols, err, want)
+		}
+	}
+	remaining := func(want uint64) {
+		t.Helper()
+		if got := limiter.Remaining(); got != want {
+			t.Fatalf("Remaining = %d, want %d", got, want)
+		}
+	}
+
+	// the whole period can be used in one burst, but no more
+	admit(101, payments.ErrGlobalRateExceeded)
+	remaining(100)
+	admit(100, nil)
+	remaining(0)
+	admit(1, payments.ErrGlobalRateExceeded)
+	admit(0, nil)
+
+	// nothing refills within the period
+	clock.Advance(9 * time.Second)
+	admit(1, payments.ErrGlobalRateExceeded)
+
+	// all of it refills at the boundary
+	clock.Advance(time.Second)
+	remaining(100)
+	admit(60, nil)
+	admit(41, payments.ErrGlobalRateExceeded)
+	admit(40, nil)
+
+	// skipping periods refills once
+	clock.Advance(35 * time.Second)
+	remaining(100)
+	admit(30, nil)
+
+	// a higher rate applies to the current period, keeping its usage
+	limiter.SetParams(payments.GlobalRateParams{GlobalSymbolsPerPeriod: 200, GlobalRatePeriodInterval: 10})
+	remaining(170)
+
+	// a rate below the usage leaves nothing until the next period
+	limiter.SetParams(payments.GlobalRateParams{GlobalSymbolsPerPeriod: 20, GlobalRatePeriodInterval: 10})
+	remaining(0)
+	admit(1, payments.ErrGlobalRateExceeded)
+
+	// an interval that moves the clock into another period starts it fresh; the clock is at 1045,
+	// which is in period 1045/10 = 104 and 1045/15 = 69
+	limiter.SetParams(payments.GlobalRateParams{GlobalSymbolsPerPeriod: 20, GlobalRatePeriodInterval: 15})
+	remaining(20)
+	admit(20, nil)
+	clock.Advance(4 * time.Second)
+	remaining(0)
+	clock.Advance(time.Second)
+	remaining(20)
+
+	if params := limiter.Params(); params.GlobalRatePeriodInterval != 15 || params.GlobalSymbolsPerPeriod != 20 {
+		t.Errorf("Params = %+v", params)
+	}
+
+	// a zero interval makes all of time one period
+	limiter.SetParams(payments.GlobalRateParams{GlobalSymbolsPerPeriod: 20})
+	admit(20, nil)
+	clock.Advance(time.Hour)
+	remaining(0)
+}
+
+func TestGlobalRateLimiterWatch(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	client := f.NewReorgClient()
+	vault, err := contractPaymentVault.NewContractPaymentVault(f.Addresses.PaymentVault, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter := payments.NewGlobalRateLimiter(payments.GlobalRateParams{}, newFakeClock(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- limiter.Watch(ctx, vault) }()
+	defer func() {
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Watch = %v", err)
+		}
+	}()
+
+	waitFor := func(want payments.GlobalRateParams) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for limiter.Params() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("Params = %+v, want %+v", limiter.Params(), want)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitFor(payments.GlobalRateParams{
+		GlobalSymbolsPerPeriod:   fixture.GlobalSymbolsPerPeriod,
+		GlobalRatePeriodInterval: fixture.GlobalRatePeriodInterval,
+	})
+
+	tx, err := f.PaymentVault.SetGlobalSymbolsPerPeriod(f.TransactOpts(f.Owner), 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Mine(tx); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(payments.GlobalRateParams{GlobalSymbolsPerPeriod: 50, GlobalRatePeriodInterval: fixture.GlobalRatePeriodInterval})
+
+	parent, err := f.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err = f.PaymentVault.SetGlobalRatePeriodInterval(f.TransactOpts(f.Owner), 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Mine(tx); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(payments.GlobalRateParams{GlobalSymbolsPerPeriod: 50, GlobalRatePeriodInterval: 60})
+	receipt, err := f.Client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reorg out the interval update; the removed event carries the value that was removed, so the
+	// limiter must read the contract again
+	if err := f.Backend.Fork(parent.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	f.Backend.Commit()
+	f.Backend.Commit()
+	client.SendRemoved(receipt.Logs...)
+	waitFor(payments.GlobalRateParams{GlobalSymbolsPerPeriod: 50, GlobalRatePeriodInterval: fixture.GlobalRatePeriodInterval})
+}
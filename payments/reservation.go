@@ -0,0 +1,105 @@
+package payments
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/Layr-Labs/eigenda/contracts/reverts"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// Errors returned by ValidateReservation. They are the sentinels the reverts package decodes the
+// reverts of PaymentVault.setReservation to.
+var (
+	ErrQuorumSplitsLengthMismatch = reverts.ErrQuorumSplitsLengthMismatch
+	ErrQuorumSplitsSum            = reverts.ErrQuorumSplitsSum
+	ErrReservationWindowInvalid   = reverts.ErrReservationWindowInvalid
+	// ErrQuorumSplitsOverflow matches reverts.ErrPanicArithmetic, the panic setReservation
+	// reverts with when quorumSplits sum past 255.
+	ErrQuorumSplitsOverflow = fmt.Errorf("sum of quorumSplits overflows uint8: %w", reverts.ErrPanicArithmetic)
+)
+
+// ValidateReservation mirrors the checks of PaymentVault.setReservation, which every stored
+// reservation has passed.
+func ValidateReservation(reservation structs.Reservation) error {
+	if len(reservation.QuorumNumbers) != len(reservation.QuorumSplits) {
+		return ErrQuorumSplitsLengthMismatch
+	}
+	// _checkQuorumSplit sums in a uint8 and reverts on overflow
+	var total uint8
+	for _, split := range reservation.QuorumSplits {
+		if split > math.MaxUint8-total {
+			return ErrQuorumSplitsOverflow
+		}
+		total += split
+	}
+	if total != 100 {
+		return ErrQuorumSplitsSum
+	}
+	if reservation.EndTimestamp <= reservation.StartTimestamp {
+		return ErrReservationWindowInvalid
+	}
+	return nil
+}
+
+// IsActive reports whether timestamp falls in the reservation window [startTimestamp, endTimestamp).
+func IsActive(reservation structs.Reservation, timestamp uint64) bool {
+	return reservation.StartTimestamp <= timestamp && timestamp < reservation.EndTimestamp
+}
+
+// ReservationPeriod returns the index of the reservation period timestamp falls in. Periods are
+// aligned to multiples of reservationPeriodInterval.
+func ReservationPeriod(timestamp, reservationPeriodInterval uint64) uint64 {
+	return periodIndex(timestamp, reservationPeriodInterval)
+}
+
+// PeriodBudget returns the symbols a reservation may disperse per reservation period. It
+// saturates at math.MaxUint64.
+func PeriodBudget(reservation structs.Reservation, reservationPeriodInterval uint64) uint64 {
+	hi, lo := bits.Mul64(reservation.SymbolsPerSecond, reservationPeriodInterval)
+	if hi != 0 {
+		return math.MaxUint64
+	}
+	return lo
+}
+
+// QuorumBudgets splits the per-period budget of a valid reservation across its quorums by
+// quorumSplits, rounding each share down.
+func QuorumBudgets(reservation structs.Reservation, reservationPeriodInterval uint64) map[uint8]uint64 {
+	budget := PeriodBudget(reservation, reservationPeriodInterval)
+	budgets := make(map[uint8]uint64, len(reservation.QuorumNumbers))
+	for i, quorumNumber := range reservation.QuorumNumbers {
+		budgets[quorumNumber] = percentOf(budget, reservation.QuorumSplits[i], false)
+	}
+	return budgets
+}
+
+// SplitUsage splits numSymbols of usage across the quorums of a valid reservation by
+// quorumSplits, rounding each share up so that usage is never undercounted.
+func SplitUsage(reservation structs.Reservation, numSymbols uint64) map[uint8]uint64 {
+	usage := make(map[uint8]uint64, len(reservation.QuorumNumbers))
+	for i, quorumNumber := range reservation.QuorumNumbers {
+		usage[quorumNumber] = percentOf(numSymbols, reservation.QuorumSplits[i], true)
+	}
+	return usage
+}
+
+// percentOf returns v * percent / 100 without overflowing, for percent <= 100.
+func percentOf(v uint64, percent uint8, roundUp bool) uint64 {
+	hi, lo := bits.Mul64(v, uint64(percent))
+	quo, rem := bits.Div64(hi, lo, 100)
+	if roundUp && rem != 0 {
+		quo++
+	}
+	return quo
+}
+
+// periodIndex returns the index of the interval-aligned period timestamp falls in. A zero
+// interval makes all of time one period.
+func periodIndex(timestamp, interval uint64) uint64 {
+	if interval == 0 {
+		return 0
+	}
+	return timestamp / interval
+}
@@ -0,0 +1,183 @@
+package payments
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+type usageKey struct {
+	account common.Address
+	period  uint64
+}
+
+// MemoryUsageStore is a UsageStore that keeps usage in memory.
+type MemoryUsageStore struct {
+	mu    sync.Mutex
+	usage map[usageKey]map[uint8]uint64
+}
+
+// NewMemoryUsageStore returns an empty MemoryUsageStore.
+func NewMemoryUsageStore() *MemoryUsageStore {
+	return &MemoryUsageStore{usage: make(map[usageKey]map[uint8]uint64)}
+}
+
+// AddUsage implements UsageStore.
+func (s *MemoryUsageStore) AddUsage(
+	_ context.Context,
+	account common.Address,
+	period, overflowPeriod uint64,
+	usage, limits map[uint8]uint64,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, overflowKey := usageKey{account, period}, usageKey{account, overflowPeriod}
+	current, overflow, ok := ChargeUsage(s.usage[key], s.usage[overflowKey], usage, limits)
+	if !ok {
+		return false, nil
+	}
+	s.merge(key, current)
+	s.merge(overflowKey, overflow)
+	return true, nil
+}
+
+func (s *MemoryUsageStore) merge(key usageKey, usage map[uint8]uint64) {
+	if len(usage) == 0 {
+		return
+	}
+	if s.usage[key] == nil {
+		s.usage[key] = make(map[uint8]uint64, len(usage))
+	}
+	for quorumNumber, symbols := range usage {
+		s.usage[key][quorumNumber] = symbols
+	}
+}
+
+// Usage implements UsageStore.
+func (s *MemoryUsageStore) Usage(_ context.Context, account common.Address, period uint64) (map[uint8]uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usage := make(map[uint8]uint64)
+	for quorumNumber, symbols := range s.usage[usageKey{account, period}] {
+		usage[quorumNumber] = symbols
+	}
+	return usage, nil
+}
+
+// PruneBefore implements UsageStore.
+func (s *MemoryUsageStore) PruneBefore(_ context.Context, period uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.usage {
+		if key.period < period {
+			delete(s.usage, key)
+		}
+	}
+	return nil
+}
+
+// LevelDBUsageStore is a UsageStore persisted in a LevelDB database, so that usage survives
+// restarts of the metering process.
+//
+// Usage is keyed by period first, so that pruning is a range scan.
+type LevelDBUsageStore struct {
+	// mu serializes the read-check-write of AddUsage.
+	mu sync.Mutex
+	db *leveldb.DB
+}
+
+// OpenLevelDBUsageStore opens or creates the store at path.
+func OpenLevelDBUsageStore(path string) (*LevelDBUsageStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LevelDBUsageStore{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *LevelDBUsageStore) Close() error {
+	return s.db.Close()
+}
+
+// AddUsage implements UsageStore.
+func (s *LevelDBUsageStore) AddUsage(
+	ctx context.Context,
+	account common.Address,
+	period, overflowPeriod uint64,
+	usage, limits map[uint8]uint64,
+) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.Usage(ctx, account, period)
+	if err != nil {
+		return false, err
+	}
+	overflow, err := s.Usage(ctx, account, overflowPeriod)
+	if err != nil {
+		return false, err
+	}
+	current, overflow, ok := ChargeUsage(current, overflow, usage, limits)
+	if !ok {
+		return false, nil
+	}
+	update := new(leveldb.Batch)
+	for quorumNumber, symbols := range current {
+		update.Put(usageStoreKey(period, account, quorumNumber), binary.BigEndian.AppendUint64(nil, symbols))
+	}
+	for quorumNumber, symbols := range overflow {
+		update.Put(usageStoreKey(overflowPeriod, account, quorumNumber), binary.BigEndian.AppendUint64(nil, symbols))
+	}
+	return true, s.db.Write(update, nil)
+}
+
+// Usage implements UsageStore.
+func (s *LevelDBUsageStore) Usage(_ context.Context, account common.Address, period uint64) (map[uint8]uint64, error) {
+	prefix := usageStoreKey(period, account, 0)
+	iter := s.db.NewIterator(util.BytesPrefix(prefix[:len(prefix)-1]), nil)
+	defer iter.Release()
+
+	usage := make(map[uint8]uint64)
+	for iter.Next() {
+		key, value := iter.Key(), iter.Value()
+		if len(value) != 8 {
+			return nil, errors.New("corrupt usage value")
+		}
+		usage[key[len(key)-1]] = binary.BigEndian.Uint64(value)
+	}
+	return usage, iter.Error()
+}
+
+// PruneBefore implements UsageStore.
+func (s *LevelDBUsageStore) PruneBefore(_ context.Context, period uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update := new(leveldb.Batch)
+	iter := s.db.NewIterator(&util.Range{Limit: binary.BigEndian.AppendUint64(nil, period)}, nil)
+	for iter.Next() {
+		update.Delete(append([]byte(nil), iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return s.db.Write(update, nil)
+}
+
+// usageStoreKey is <period uint64><account><quorumNumber>, with the period big-endian so that
+// keys sort by period.
+func usageStoreKey(period uint64, account common.Address, quorumNumber uint8) []byte {
+	key := binary.BigEndian.AppendUint64(make([]byte, 0, 8+common.AddressLength+1), period)
+	key = append(key, account.Bytes()...)
+	return append(key, quorumNumber)
+}
@@ -0,0 +1,126 @@
+package payments_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Layr-Labs/eigenda/contracts/payments"
+)
+
+func openLevelDBUsageStore(t *testing.T, path string) *payments.LevelDBUsageStore {
+	t.Helper()
+	store, err := payments.OpenLevelDBUsageStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestMeterLevelDB(t *testing.T) {
+	testMeter(t, openLevelDBUsageStore(t, t.TempDir()))
+}
+
+func TestLevelDBUsageStoreReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "usage")
+	account := common.HexToAddress("0x1234")
+	limits := map[uint8]uint64{0: 100, 1: 100}
+
+	store, err := payments.OpenLevelDBUsageStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := store.AddUsage(ctx, account, 1, 3, map[uint8]uint64{0: 90, 1: 10}, limits); err != nil || !ok {
+		t.Fatalf("AddUsage = %v, %v", ok, err)
+	}
+	if ok, err := store.AddUsage(ctx, account, 1, 3, map[uint8]uint64{0: 20}, limits); err != nil || !ok {
+		t.Fatalf("AddUsage = %v, %v", ok, err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store = openLevelDBUsageStore(t, path)
+	for period, want := range map[uint64]map[uint8]uint64{
+		1: {0: 100, 1: 10},
+		2: {},
+		3: {0: 10},
+	} {
+		usage, err := store.Usage(ctx, account, period)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(usage, want) {
+			t.Errorf("period %d: Usage = %v, want %v", period, usage, want)
+		}
+	}
+
+	// the reopened store still enforces the limits and overflows on top of the stored usage
+	if ok, err := store.AddUsage(ctx, account, 1, 3, map[uint8]uint64{0: 1}, limits); err != nil || ok {
+		t.Errorf("AddUsage into a used up period = %v, %v", ok, err)
+	}
+	if ok, err := store.AddUsage(ctx, account, 1, 3, map[uint8]uint64{1: 95}, limits); err != nil || !ok {
+		t.Errorf("AddUsage = %v, %v", ok, err)
+	}
+	usage, err := store.Usage(ctx, account, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[uint8]uint64{0: 10, 1: 5}; !reflect.DeepEqual(usage, want) {
+		t.Errorf("overflow period: Usage = %v, want %v", usage, want)
+	}
+}
+
+// TestUsageStoreConcurrent checks that concurrent charges never exceed a limit.
+func TestUsageStoreConcurrent(t *testing.T) {
+	for name, store := range map[string]payments.UsageStore{
+		"memory":  payments.NewMemoryUsageStore(),
+		"leveldb": openLevelDBUsageStore(t, t.TempDir()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			account := common.HexToAddress("0x1234")
+			limits := map[uint8]uint64{0: 100}
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			accepted := 0
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					ok, err := store.AddUsage(ctx, account, 5, 7, map[uint8]uint64{0: 7}, limits)
+					if err != nil {
+						t.Error(err)
+					}
+					if ok {
+						mu.Lock()
+						accepted++
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			// 14 charges fill the period, the 15th overflows 5 symbols and the rest are rejected
+			if accepted != 15 {
+				t.Errorf("accepted %d charges, want 15", accepted)
+			}
+			for period, want := range map[uint64]uint64{5: 100, 7: 5} {
+				usage, err := store.Usage(ctx, account, period)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if usage[0] != want {
+					t.Errorf("period %d: usage = %d, want %d", period, usage[0], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+
+	contractEigenDADisperserRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDADisperserRegistry"
+)
+
+// Errors returned when checking a disperser signature.
+var (
+	ErrDisperserNotFound     = errors.New("disperser key is not registered")
+	ErrInvalidSignature      = errors.New("invalid disperser signature")
+	ErrUnauthorizedDisperser = errors.New("signature is not from the disperser registered for the key")
+)
+
+// DisperserRegistryClient is a local copy of an EigenDADisperserRegistry, used to accept only
+// requests signed by registered dispersers. It is safe for concurrent use.
+//
+// The registry cannot be enumerated on chain, so the copy is built by replaying DisperserAdded
+// from the block the registry was deployed in. A disperser set to the zero address counts as
+// removed.
+type DisperserRegistryClient struct {
+	registry *contractEigenDADisperserRegistry.ContractEigenDADisperserRegistry
+	backend  bind.ContractBackend
+	config   Config
+
+	// syncMu serializes replays.
+	syncMu sync.Mutex
+	// nextBlock is the first block not replayed yet.
+	nextBlock uint64
+
+	mu         sync.RWMutex
+	dispersers map[uint32]common.Address
+}
+
+// NewDisperserRegistryClient returns a client of the EigenDADisperserRegistry at address,
+// synced by replaying its events from startBlock. Run keeps it current.
+func NewDisperserRegistryClient(
+	ctx context.Context,
+	address common.Address,
+	backend bind.ContractBackend,
+	startBlock uint64,
+	config Config,
+) (*DisperserRegistryClient, error) {
+	registry, err := contractEigenDADisperserRegistry.NewContractEigenDADisperserRegistry(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	c := &DisperserRegistryClient{
+		registry:   registry,
+		backend:    backend,
+		config:     config.withDefaults(),
+		nextBlock:  startBlock,
+		dispersers: make(map[uint32]common.Address),
+	}
+	if err := c.Sync(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Address mirrors EigenDADisperserRegistry.disperserKeyToAddress for registered keys.
+func (c *DisperserRegistryClient) Address(key uint32) (common.Address, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	address, ok := c.dispersers[key]
+	if !ok {
+		return common.Address{}, fmt.Errorf("%w: %d", ErrDisperserNotFound, key)
+	}
+	return address, nil
+}
+
+// Dispersers returns a copy of the registered disperser addresses by key.
+func (c *DisperserRegistryClient) Dispersers() map[uint32]common.Address {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	dispersers := make(map[uint32]common.Address, len(c.dispersers))
+	for key, address := range c.dispersers {
+		dispersers[key] = address
+	}
+	return dispersers
+}
+
+// VerifySignature checks that signature is an ECDSA signature of digest by the disperser
+// registered under key. The signature is in the 65 byte [R || S || V] format, with V either 0/1
+// or 27/28.
+func (c *DisperserRegistryClient) VerifySignature(key uint32, digest [32]byte, signature []byte) error {
+	address, err := c.Address(key)
+	if err != nil {
+		return err
+	}
+	if len(signature) != crypto.SignatureLength {
+		return fmt.Errorf("%w: length %d", ErrInvalidSignature, len(signature))
+	}
+	sig := append([]byte(nil), signature...)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pubkey, err := crypto.SigToPub(digest[:], sig)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if signer := crypto.PubkeyToAddress(*pubkey); signer != address {
+		return fmt.Errorf("%w: key %d is %s, signed by %s", ErrUnauthorizedDisperser, key, address, signer)
+	}
+	return nil
+}
+
+// Sync replays the DisperserAdded events emitted since the last sync.
+func (c *DisperserRegistryClient) Sync(ctx context.Context) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+
+	head, err := c.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for c.nextBlock <= head.Number.Uint64() {
+		end := min(head.Number.Uint64(), c.nextBlock+c.config.BlockRange-1)
+		iter, err := c.registry.FilterDisperserAdded(&bind.FilterOpts{Start: c.nextBlock, End: &end, Context: ctx}, nil, nil)
+		if err != nil {
+			return err
+		}
+		for iter.Next() {
+			if err := c.apply(ctx, iter.Event); err != nil {
+				iter.Close()
+				return err
+			}
+		}
+		iter.Close()
+		if err := iter.Error(); err != nil {
+			return err
+		}
+		c.nextBlock = end + 1
+	}
+	return nil
+}
+
+// Run keeps the copy current until ctx is done. It applies DisperserAdded events as they are
+// emitted. While the backend cannot subscribe, or after the subscription fails, it syncs every
+// PollInterval and retries the subscription each time.
+func (c *DisperserRegistryClient) Run(ctx context.Context) error {
+	polling := false
+	for {
+		events := make(chan *contractEigenDADisperserRegistry.ContractEigenDADisperserRegistryDisperserAdded)
+		sub, err := c.registry.WatchDisperserAdded(&bind.WatchOpts{Context: ctx}, events, nil, nil)
+		switch {
+		case err == nil:
+			if polling {
+				c.config.Logger.Info("Subscribed to DisperserAdded, stopped polling the disperser registry")
+			}
+			err = c.watch(ctx, sub, events)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.config.Logger.Warn("DisperserAdded subscription failed, polling the disperser registry", "err", err)
+		case !polling:
+			c.config.Logger.Info("Cannot subscribe to DisperserAdded, polling the disperser registry", "err", err)
+		}
+		polling = true
+
+		if err := c.Sync(ctx); err != nil && ctx.Err() == nil {
+			c.config.Logger.Warn("Syncing dispersers failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.config.PollInterval):
+		}
+	}
+}
+
+// watch applies the events of sub until it fails or ctx is done.
+func (c *DisperserRegistryClient) watch(
+	ctx context.Context,
+	sub event.Subscription,
+	events <-chan *contractEigenDADisperserRegistry.ContractEigenDADisperserRegistryDisperserAdded,
+) error {
+	defer sub.Unsubscribe()
+
+	// Catch up with the dispersers set before the subscription started.
+	if err := c.Sync(ctx); err != nil {
+		c.config.Logger.Warn("Syncing dispersers failed", "err", err)
+	}
+	for {
+		select {
+		case event := <-events:
+			if err := c.apply(ctx, event); err != nil {
+				c.config.Logger.Warn("Applying DisperserAdded failed", "key", event.Key, "err", err)
+			}
+		case err := <-sub.Err():
+			if err == nil {
+				err = errors.New("subscription closed")
+			}
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// apply records a DisperserAdded event. An event removed by a reorg says nothing about the
+// current address, so the key is read back from the registry instead.
+func (c *DisperserRegistryClient) apply(
+	ctx context.Context,
+	event *contractEigenDADisperserRegistry.ContractEigenDADisperserRegistryDisperserAdded,
+) error {
+	address := event.Disperser
+	if event.Raw.Removed {
+		var err error
+		address, err = c.registry.DisperserKeyToAddress(&bind.CallOpts{Context: ctx}, event.Key)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if address == (common.Address{}) {
+		delete(c.dispersers, event.Key)
+	} else {
+		c.dispersers[event.Key] = address
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package registry_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	contractEigenDADisperserRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDADisperserRegistry"
+	"github.com/Layr-Labs/eigenda/contracts/registry"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+// setDisperser sets the disperser of key and returns the receipt of the transaction.
+func setDisperser(t *testing.T, f *fixture.Fixture, key uint32, address common.Address) *types.Receipt {
+	t.Helper()
+	tx, err := f.DisperserRegistry.SetDisperserInfo(f.TransactOpts(f.Owner), key,
+		contractEigenDADisperserRegistry.DisperserInfo{DisperserAddress: address})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Mine(tx); err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := f.Client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return receipt
+}
+
+func newDisperserKey(t *testing.T) (*ecdsa.PrivateKey, common.Address) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestDisperserRegistryClientSync(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	start, err := f.Client.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, first := newDisperserKey(t)
+	_, second := newDisperserKey(t)
+	_, replaced := newDisperserKey(t)
+	setDisperser(t, f, 0, first)
+	setDisperser(t, f, 7, replaced)
+	for i := 0; i < 5; i++ {
+		f.Backend.Commit()
+	}
+	setDisperser(t, f, 7, second)
+	setDisperser(t, f, 3, replaced)
+	setDisperser(t, f, 3, common.Address{})
+
+	// a small block range replays the events over several requests
+	config := testConfig
+	config.BlockRange = 2
+	client, err := registry.NewDisperserRegistryClient(ctx, f.Addresses.DisperserRegistry, f.Client, start, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[uint32]common.Address{0: first, 7: second}
+	if got := client.Dispersers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Dispersers = %v, want %v", got, want)
+	}
+	if _, err := client.Address(3); !errors.Is(err, registry.ErrDisperserNotFound) {
+		t.Errorf("Address(3) = %v, want %v", err, registry.ErrDisperserNotFound)
+	}
+
+	// a sync only replays the new events
+	setDisperser(t, f, 0, common.Address{})
+	setDisperser(t, f, 1, first)
+	if err := client.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want = map[uint32]common.Address{1: first, 7: second}
+	if got := client.Dispersers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Dispersers after sync = %v, want %v", got, want)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	ctx := context.Background()
+	start, err := f.Client.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disperserKey, disperser := newDisperserKey(t)
+	otherKey, _ := newDisperserKey(t)
+	setDisperser(t, f, 0, disperser)
+	client, err := registry.NewDisperserRegistryClient(ctx, f.Addresses.DisperserRegistry, f.Client, start, testConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := [32]byte(crypto.Keccak256Hash([]byte("blob request")))
+	sign := func(key *ecdsa.PrivateKey) []byte {
+		signature, err := crypto.Sign(digest[:], key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signature
+	}
+	legacyV := sign(disperserKey)
+	legacyV[crypto.RecoveryIDOffset] += 27
+
+	for _, tc := range []struct {
+		name      string
+		key       uint32
+		signature []byte
+		want      error
+	}{
+		{name: "valid", key: 0, signature: sign(disperserKey)},
+		{name: "27/28 recovery id", key: 0, signature: legacyV},
+		{name: "other signer", key: 0, signature: sign(otherKey), want: registry.ErrUnauthorizedDisperser},
+		{name: "unregistered key", key: 1, signature: sign(disperserKey), want: registry.ErrDisperserNotFound},
+		{name: "short signature", key: 0, signature: sign(disperserKey)[:64], want: registry.ErrInvalidSignature},
+		{name: "invalid recovery id", key: 0, signature: append(sign(disperserKey)[:64], 5), want: registry.ErrInvalidSignature},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := client.VerifySignature(tc.key, digest, tc.signature); !errors.Is(err, tc.want) {
+				t.Errorf("VerifySignature = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestDisperserRegistryClientRun(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	start, err := f.Client.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &flakyClient{ReorgClient: f.NewReorgClient(), failures: 2}
+	client, err := registry.NewDisperserRegistryClient(context.Background(), f.Addresses.DisperserRegistry, backend, start, testConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- client.Run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Run = %v", err)
+		}
+	}()
+
+	want := make(map[uint32]common.Address)
+	dispersersAre := func(want map[uint32]common.Address) func() bool {
+		return func() bool { return reflect.DeepEqual(client.Dispersers(), want) }
+	}
+
+	// dispersers are polled while subscribing fails, and the subscription is retried after each poll
+	_, want[0] = newDisperserKey(t)
+	setDisperser(t, f, 0, want[0])
+	waitUntil(t, "disperser 0", dispersersAre(want))
+	waitUntil(t, "a third subscription", func() bool { return backend.Subscribes() >= 3 })
+
+	_, want[1] = newDisperserKey(t)
+	setDisperser(t, f, 1, want[1])
+	waitUntil(t, "disperser 1", dispersersAre(want))
+
+	// a failed subscription falls back to polling and is then renewed
+	backend.Fail()
+	setDisperser(t, f, 0, common.Address{})
+	delete(want, 0)
+	waitUntil(t, "disperser 0 to be removed", dispersersAre(want))
+	waitUntil(t, "a fourth subscription", func() bool { return backend.Subscribes() >= 4 })
+
+	// a disperser set in a block that is reorged out is read back when its event is removed
+	parent, err := f.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, orphaned := newDisperserKey(t)
+	receipt := setDisperser(t, f, 1, orphaned)
+	waitUntil(t, "the orphaned disperser", func() bool { address, _ := client.Address(1); return address == orphaned })
+	if err := f.Backend.Fork(parent.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	f.Backend.Commit()
+	f.Backend.Commit()
+	backend.SendRemoved(receipt.Logs...)
+	waitUntil(t, "disperser 1 to be restored", dispersersAre(want))
+}
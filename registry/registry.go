@@ -0,0 +1,42 @@
+// Package registry keeps local copies of the EigenDA relay and disperser registries, loaded once
+// and then kept current from the events the registries emit when entries are set. Where the RPC
+// endpoint cannot push events, the copies are refreshed by polling instead.
+package registry
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Config tunes how a registry copy is kept current. Zero values fall back to DefaultConfig.
+type Config struct {
+	// PollInterval is how often the registry is polled for new entries, and the subscription
+	// retried, when events cannot be subscribed to.
+	PollInterval time.Duration
+	// BlockRange is the most blocks filtered in one eth_getLogs request when replaying events.
+	BlockRange uint64
+	Logger     *slog.Logger
+}
+
+// DefaultConfig returns the defaults used for unset Config fields.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Minute,
+		BlockRange:   10_000,
+		Logger:       slog.Default(),
+	}
+}
+
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.PollInterval == 0 {
+		c.PollInterval = defaults.PollInterval
+	}
+	if c.BlockRange == 0 {
+		c.BlockRange = defaults.BlockRange
+	}
+	if c.Logger == nil {
+		c.Logger = defaults.Logger
+	}
+	return c
+}
@@ -0,0 +1,234 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+
+	contractEigenDARelayRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDARelayRegistry"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// ErrRelayNotFound is returned for relay keys the registry has not assigned.
+var ErrRelayNotFound = errors.New("relay key is not registered")
+
+// RelayRegistryCache is a local copy of an EigenDARelayRegistry. It is safe for concurrent use.
+type RelayRegistryCache struct {
+	registry *contractEigenDARelayRegistry.ContractEigenDARelayRegistry
+	config   Config
+
+	mu     sync.RWMutex
+	relays map[uint32]structs.RelayInfo
+	// nextKey is the nextRelayKey of the last load; every lower key is in relays.
+	nextKey uint32
+}
+
+// NewRelayRegistryCache returns a cache of the EigenDARelayRegistry at address holding every
+// relay registered so far. Run keeps it current.
+func NewRelayRegistryCache(
+	ctx context.Context,
+	address common.Address,
+	backend bind.ContractBackend,
+	config Config,
+) (*RelayRegistryCache, error) {
+	registry, err := contractEigenDARelayRegistry.NewContractEigenDARelayRegistry(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	c := &RelayRegistryCache{
+		registry: registry,
+		config:   config.withDefaults(),
+		relays:   make(map[uint32]structs.RelayInfo),
+	}
+	if err := c.Load(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Relay returns the relay registered under key.
+func (c *RelayRegistryCache) Relay(key uint32) (structs.RelayInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	relay, ok := c.relays[key]
+	if !ok {
+		return structs.RelayInfo{}, fmt.Errorf("%w: %d", ErrRelayNotFound, key)
+	}
+	return relay, nil
+}
+
+// URL mirrors EigenDARelayRegistry.relayKeyToUrl for registered keys.
+func (c *RelayRegistryCache) URL(key uint32) (string, error) {
+	relay, err := c.Relay(key)
+	return relay.RelayURL, err
+}
+
+// Address mirrors EigenDARelayRegistry.relayKeyToAddress for registered keys.
+func (c *RelayRegistryCache) Address(key uint32) (common.Address, error) {
+	relay, err := c.Relay(key)
+	return relay.RelayAddress, err
+}
+
+// URLs resolves the relay keys of a blob certificate to URLs, in the same order.
+func (c *RelayRegistryCache) URLs(keys []uint32) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	urls := make([]string, len(keys))
+	for i, key := range keys {
+		relay, ok := c.relays[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrRelayNotFound, key)
+		}
+		urls[i] = relay.RelayURL
+	}
+	return urls, nil
+}
+
+// Relays returns a copy of every cached relay by key.
+func (c *RelayRegistryCache) Relays() map[uint32]structs.RelayInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	relays := make(map[uint32]structs.RelayInfo, len(c.relays))
+	for key, relay := range c.relays {
+		relays[key] = relay
+	}
+	return relays
+}
+
+// RelayAddresses returns the address of every cached relay by key, as
+// verification.RegistryState.RelayAddresses expects them.
+func (c *RelayRegistryCache) RelayAddresses() map[uint32]common.Address {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	addresses := make(map[uint32]common.Address, len(c.relays))
+	for key, relay := range c.relays {
+		addresses[key] = relay.RelayAddress
+	}
+	return addresses
+}
+
+// Load fetches the relays registered since the last load.
+func (c *RelayRegistryCache) Load(ctx context.Context) error {
+	opts := &bind.CallOpts{Context: ctx}
+	nextKey, err := c.registry.NextRelayKey(opts)
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	from := c.nextKey
+	c.mu.RUnlock()
+
+	loaded := make(map[uint32]structs.RelayInfo)
+	for key := from; key < nextKey; key++ {
+		info, err := c.registry.RelayKeyToInfo(opts, key)
+		if err != nil {
+			return fmt.Errorf("loading relay %d: %w", key, err)
+		}
+		loaded[key] = structs.RelayInfo{RelayAddress: info.RelayAddress, RelayURL: info.RelayURL}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, relay := range loaded {
+		c.relays[key] = relay
+	}
+	c.nextKey = max(c.nextKey, nextKey)
+	return nil
+}
+
+// Run keeps the cache current until ctx is done. It applies RelayAdded events as they are
+// emitted. While the backend cannot subscribe, or after the subscription fails, it loads the
+// registry every PollInterval and retries the subscription each time.
+func (c *RelayRegistryCache) Run(ctx context.Context) error {
+	polling := false
+	for {
+		events := make(chan *contractEigenDARelayRegistry.ContractEigenDARelayRegistryRelayAdded)
+		sub, err := c.registry.WatchRelayAdded(&bind.WatchOpts{Context: ctx}, events, nil, nil)
+		switch {
+		case err == nil:
+			if polling {
+				c.config.Logger.Info("Subscribed to RelayAdded, stopped polling the relay registry")
+			}
+			err = c.watch(ctx, sub, events)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.config.Logger.Warn("RelayAdded subscription failed, polling the relay registry", "err", err)
+		case !polling:
+			c.config.Logger.Info("Cannot subscribe to RelayAdded, polling the relay registry", "err", err)
+		}
+		polling = true
+
+		if err := c.Load(ctx); err != nil && ctx.Err() == nil {
+			c.config.Logger.Warn("Loading relays failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.config.PollInterval):
+		}
+	}
+}
+
+// watch applies the events of sub until it fails or ctx is done.
+func (c *RelayRegistryCache) watch(
+	ctx context.Context,
+	sub event.Subscription,
+	events <-chan *contractEigenDARelayRegistry.ContractEigenDARelayRegistryRelayAdded,
+) error {
+	defer sub.Unsubscribe()
+
+	// Catch up with the relays added before the subscription started.
+	if err := c.Load(ctx); err != nil {
+		c.config.Logger.Warn("Loading relays failed", "err", err)
+	}
+	for {
+		select {
+		case event := <-events:
+			if err := c.apply(ctx, event); err != nil {
+				c.config.Logger.Warn("Applying RelayAdded failed", "key", event.Key, "err", err)
+			}
+		case err := <-sub.Err():
+			if err == nil {
+				err = errors.New("subscription closed")
+			}
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// apply records a RelayAdded event. An event removed by a reorg says nothing about the current
+// relay, so the key is read back from the registry instead, and dropped if it is no longer set.
+func (c *RelayRegistryCache) apply(
+	ctx context.Context,
+	event *contractEigenDARelayRegistry.ContractEigenDARelayRegistryRelayAdded,
+) error {
+	relay := structs.RelayInfo{RelayAddress: event.Relay, RelayURL: event.RelayURL}
+	if event.Raw.Removed {
+		info, err := c.registry.RelayKeyToInfo(&bind.CallOpts{Context: ctx}, event.Key)
+		if err != nil {
+			return err
+		}
+		relay = structs.RelayInfo{RelayAddress: info.RelayAddress, RelayURL: info.RelayURL}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if relay.RelayAddress == (common.Address{}) {
+		delete(c.relays, event.Key)
+		// the key will be assigned again, so the next load must fetch it
+		c.nextKey = min(c.nextKey, event.Key)
+	} else {
+		c.relays[event.Key] = relay
+	}
+	return nil
+}
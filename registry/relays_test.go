@@ -0,0 +1,181 @@
+package registry_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Layr-Labs/eigenda/contracts/registry"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+var testConfig = registry.Config{
+	PollInterval: 20 * time.Millisecond,
+	Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+}
+
+// flakyClient is a client whose first log subscriptions fail, and whose live subscriptions can be
+// failed on demand.
+type flakyClient struct {
+	*fixture.ReorgClient
+
+	mu         sync.Mutex
+	failures   int
+	subscribes int
+	subs       []ethereum.Subscription
+}
+
+func (c *flakyClient) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subscribes++
+	if c.failures > 0 {
+		c.failures--
+		return nil, errors.New("subscriptions are not supported")
+	}
+	sub, err := c.ReorgClient.SubscribeFilterLogs(ctx, query, ch)
+	if err != nil {
+		return nil, err
+	}
+	c.subs = append(c.subs, sub)
+	return sub, nil
+}
+
+// Subscribes returns how many subscriptions have been attempted.
+func (c *flakyClient) Subscribes() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.subscribes
+}
+
+// Fail ends every live subscription as a dropped connection would.
+func (c *flakyClient) Fail() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, sub := range c.subs {
+		sub.Unsubscribe()
+	}
+	c.subs = nil
+}
+
+// waitUntil polls cond until it holds, failing the test after a few seconds.
+func waitUntil(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func testRelay(key uint32) structs.RelayInfo {
+	return structs.RelayInfo{
+		RelayAddress: common.Address{byte(key + 1)},
+		RelayURL:     "relay" + string(rune('0'+key)) + ".eigenda.test:32007",
+	}
+}
+
+// addRelay registers the next relay and returns the receipt of the transaction.
+func addRelay(t *testing.T, f *fixture.Fixture, relay structs.RelayInfo) *types.Receipt {
+	t.Helper()
+	tx, err := f.RelayRegistry.AddRelayInfo(f.TransactOpts(f.Owner), relay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Mine(tx); err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := f.Client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return receipt
+}
+
+func TestRelayRegistryCache(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	client := &flakyClient{ReorgClient: f.NewReorgClient(), failures: 2}
+
+	want := map[uint32]structs.RelayInfo{0: testRelay(0)}
+	addRelay(t, f, want[0])
+	cache, err := registry.NewRelayRegistryCache(context.Background(), f.Addresses.RelayRegistry, client, testConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.Relays(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Relays = %v, want %v", got, want)
+	}
+	if url, err := cache.URL(0); err != nil || url != want[0].RelayURL {
+		t.Errorf("URL(0) = %q, %v, want %q", url, err, want[0].RelayURL)
+	}
+	if _, err := cache.Address(1); !errors.Is(err, registry.ErrRelayNotFound) {
+		t.Errorf("Address(1) = %v, want %v", err, registry.ErrRelayNotFound)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- cache.Run(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("Run = %v", err)
+		}
+	}()
+
+	relaysAre := func(want map[uint32]structs.RelayInfo) func() bool {
+		return func() bool { return reflect.DeepEqual(cache.Relays(), want) }
+	}
+
+	// relays are polled while subscribing fails, and the subscription is retried after each poll
+	want[1] = testRelay(1)
+	addRelay(t, f, want[1])
+	waitUntil(t, "relay 1", relaysAre(want))
+	waitUntil(t, "a third subscription", func() bool { return client.Subscribes() >= 3 })
+
+	want[2] = testRelay(2)
+	addRelay(t, f, want[2])
+	waitUntil(t, "relay 2", relaysAre(want))
+
+	// a failed subscription falls back to polling and is then renewed
+	client.Fail()
+	want[3] = testRelay(3)
+	addRelay(t, f, want[3])
+	waitUntil(t, "relay 3", relaysAre(want))
+	waitUntil(t, "a fourth subscription", func() bool { return client.Subscribes() >= 4 })
+
+	// a relay added in a block that is reorged out is dropped when its event is removed
+	parent, err := f.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt := addRelay(t, f, testRelay(4))
+	waitUntil(t, "relay 4", func() bool { _, err := cache.Relay(4); return err == nil })
+	if err := f.Backend.Fork(parent.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	f.Backend.Commit()
+	f.Backend.Commit()
+	client.SendRemoved(receipt.Logs...)
+	waitUntil(t, "relay 4 to be dropped", relaysAre(want))
+
+	// the key is assigned again on the new chain
+	want[4] = testRelay(5)
+	addRelay(t, f, want[4])
+	waitUntil(t, "relay 4 on the new chain", relaysAre(want))
+}
@@ -0,0 +1,177 @@
+package reverts
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrExecutionReverted is matched by every decoded revert, including those without a reason.
+var ErrExecutionReverted = errors.New("execution reverted")
+
+// EigenDAServiceManager.confirmBatch.
+var (
+	ErrBatchNotInCalldata        = errors.New("header and nonsigner data must be in calldata")
+	ErrReferenceBlockInFuture    = errors.New("specified referenceBlockNumber is in future")
+	ErrReferenceBlockTooOld      = errors.New("specified referenceBlockNumber is too far in past")
+	ErrSignedStakeLengthMismatch = errors.New("quorumNumbers and signedStakeForQuorums must be same length")
+	ErrSignedStakeBelowThreshold = errors.New("signatories do not own threshold percentage of a quorum")
+)
+
+// PaymentVault. payments.ValidateReservation reports the reservation checks with these errors.
+var (
+	ErrPriceUpdateCooldown = errors.New("price update cooldown not surpassed")
+	ErrDepositTooLarge     = errors.New("amount must be less than or equal to 80 bits")
+
+	ErrQuorumSplitsLengthMismatch = errors.New("arrays must have the same length")
+	ErrQuorumSplitsSum            = errors.New("sum of quorumSplits must be 100")
+	ErrReservationWindowInvalid   = errors.New("end timestamp must be greater than start timestamp")
+)
+
+// EigenDACertVerificationUtils V1 cert verification. The batch variants of these reasons, reported
+// by _verifyDACertsForQuorums, decode to the same errors.
+var (
+	ErrBatchMetadataMismatch       = errors.New("EigenDACertVerificationUtils._verifyDACertForQuorums: batchMetadata does not match stored metadata")
+	ErrInclusionProofInvalidV1     = errors.New("EigenDACertVerificationUtils._verifyDACertForQuorums: inclusion proof is invalid")
+	ErrQuorumNumberMismatch        = errors.New("EigenDACertVerificationUtils._verifyDACertForQuorums: quorumNumber does not match")
+	ErrThresholdPercentagesInvalid = errors.New("EigenDACertVerificationUtils._verifyDACertForQuorums: threshold percentages are not valid")
+	ErrConfirmationThresholdNotMet = errors.New("EigenDACertVerificationUtils._verifyDACertForQuorums: confirmationThresholdPercentage is not met")
+	ErrRequiredQuorumsNotConfirmed = errors.New("EigenDACertVerificationUtils._verifyDACertForQuorums: required quorums are not a subset of the confirmed quorums")
+	ErrBlobProofsLengthMismatch    = errors.New("EigenDACertVerificationUtils._verifyDACertsForQuorums: blobHeaders and blobVerificationProofs length mismatch")
+)
+
+// EigenDACertVerificationUtils V2 cert verification, BLSSignatureChecker and BitmapUtils, which
+// the verification package reports with these errors.
+var (
+	ErrInclusionProofInvalid    = errors.New("EigenDACertVerificationUtils._verifyDACertV2ForQuorums: inclusion proof is invalid")
+	ErrBlobQuorumsNotSubset     = errors.New("EigenDACertVerificationUtils._verifyDACertV2ForQuorums: blob quorums are not a subset of the confirmed quorums")
+	ErrRequiredQuorumsNotSubset = errors.New("EigenDACertVerificationUtils._verifyDACertV2ForQuorums: required quorums are not a subset of the blob quorums")
+	ErrRelayKeyNotSet           = errors.New("EigenDACertVerificationUtils._verifyRelayKeysSet: relay key is not set")
+
+	ErrInvalidSecurityThresholds = errors.New("EigenDACertVerificationUtils._verifyDACertSecurityParams: confirmationThreshold must be greater than adversaryThreshold")
+	ErrSecurityAssumptionsNotMet = errors.New("EigenDACertVerificationUtils._verifyDACertSecurityParams: security assumptions are not met")
+
+	ErrEmptyQuorumInput            = errors.New("BLSSignatureChecker.checkSignatures: empty quorum input")
+	ErrQuorumLengthMismatch        = errors.New("BLSSignatureChecker.checkSignatures: input quorum length mismatch")
+	ErrNonSignerLengthMismatch     = errors.New("BLSSignatureChecker.checkSignatures: input nonsigner length mismatch")
+	ErrInvalidReferenceBlock       = errors.New("BLSSignatureChecker.checkSignatures: invalid reference block")
+	ErrNonSignerPubkeysNotSorted   = errors.New("BLSSignatureChecker.checkSignatures: nonSignerPubkeys not sorted")
+	ErrStaleStakes                 = errors.New("BLSSignatureChecker.checkSignatures: StakeRegistry updates must be within withdrawalDelayBlocks window")
+	ErrQuorumApkMismatch           = errors.New("BLSSignatureChecker.checkSignatures: quorumApk hash in storage does not match provided quorum apk")
+	ErrPairingPrecompileCallFailed = errors.New("BLSSignatureChecker.checkSignatures: pairing precompile call failed")
+	ErrSignatureInvalid            = errors.New("BLSSignatureChecker.checkSignatures: signature is invalid")
+
+	ErrBytesArrayTooLong    = errors.New("BitmapUtils.orderedBytesArrayToBitmap: orderedBytesArray is too long")
+	ErrBytesArrayNotOrdered = errors.New("BitmapUtils.orderedBytesArrayToBitmap: orderedBytesArray is not ordered")
+	ErrBitmapExceedsMax     = errors.New("BitmapUtils.orderedBytesArrayToBitmap: bitmap exceeds max value")
+
+	// ErrECAddFailed is the reason of the require in BN254.plus. The ecAdd precompile failing on an
+	// invalid point makes BN254.plus execute invalid() before it, so on chain the call reverts
+	// without any revert data.
+	ErrECAddFailed = errors.New("ec-add-failed")
+)
+
+// OperatorStateRetriever.getCheckSignaturesIndices and the registry lookups it makes, which
+// operatorstate.HistoryCache reports with these errors.
+var (
+	ErrNoQuorumBitmapUpdate      = errors.New("RegCoord.getQuorumBitmapIndexAtBlockNumber: no bitmap update found for operator at blockNumber")
+	ErrQuorumDoesNotExist        = errors.New("StakeRegistry.quorumExists: quorum does not exist")
+	ErrNoTotalStakeHistory       = errors.New("StakeRegistry.getTotalStakeIndicesAtBlockNumber: quorum has no stake history at blockNumber")
+	ErrOperatorNotRegistered     = errors.New("OperatorStateRetriever.getCheckSignaturesIndices: operator must be registered at blocknumber")
+	ErrNoStakeUpdate             = errors.New("StakeRegistry._getStakeUpdateIndexForOperatorAtBlockNumber: no stake update found for operatorId and quorumNumber at block number")
+	ErrBlockBeforeFirstApkUpdate = errors.New("BLSApkRegistry.getApkIndicesAtBlockNumber: blockNumber is before the first update")
+)
+
+// Access control and lifecycle checks the contracts inherit.
+var (
+	ErrNotOwner           = errors.New("Ownable: caller is not the owner")
+	ErrAlreadyInitialized = errors.New("Initializable: contract is already initialized")
+	ErrPaused             = errors.New("Pausable: index is paused")
+)
+
+// Solidity panics, decoded from their Panic(uint256) code. Panics carry no reason string, so
+// these messages follow the panic codes instead.
+var (
+	ErrPanicArithmetic     = errors.New("panic: arithmetic underflow or overflow (0x11)")
+	ErrPanicDivisionByZero = errors.New("panic: division or modulo by zero (0x12)")
+	ErrPanicOutOfBounds    = errors.New("panic: array out-of-bounds access (0x32)")
+)
+
+// reasons maps revert reasons to the errors they decode to.
+var reasons = make(map[string]error)
+
+// panics maps panic codes to the errors they decode to.
+var panics = map[uint64]error{
+	0x11: ErrPanicArithmetic,
+	0x12: ErrPanicDivisionByZero,
+	0x32: ErrPanicOutOfBounds,
+}
+
+func init() {
+	for _, err := range []error{
+		ErrBatchNotInCalldata,
+		ErrReferenceBlockInFuture,
+		ErrReferenceBlockTooOld,
+		ErrSignedStakeLengthMismatch,
+		ErrSignedStakeBelowThreshold,
+
+		ErrPriceUpdateCooldown,
+		ErrDepositTooLarge,
+		ErrQuorumSplitsLengthMismatch,
+		ErrQuorumSplitsSum,
+		ErrReservationWindowInvalid,
+
+		ErrBatchMetadataMismatch,
+		ErrInclusionProofInvalidV1,
+		ErrQuorumNumberMismatch,
+		ErrThresholdPercentagesInvalid,
+		ErrConfirmationThresholdNotMet,
+		ErrRequiredQuorumsNotConfirmed,
+		ErrBlobProofsLengthMismatch,
+
+		ErrInclusionProofInvalid,
+		ErrBlobQuorumsNotSubset,
+		ErrRequiredQuorumsNotSubset,
+		ErrRelayKeyNotSet,
+		ErrInvalidSecurityThresholds,
+		ErrSecurityAssumptionsNotMet,
+		ErrEmptyQuorumInput,
+		ErrQuorumLengthMismatch,
+		ErrNonSignerLengthMismatch,
+		ErrInvalidReferenceBlock,
+		ErrNonSignerPubkeysNotSorted,
+		ErrStaleStakes,
+		ErrQuorumApkMismatch,
+		ErrPairingPrecompileCallFailed,
+		ErrSignatureInvalid,
+		ErrBytesArrayTooLong,
+		ErrBytesArrayNotOrdered,
+		ErrBitmapExceedsMax,
+		ErrECAddFailed,
+
+		ErrNoQuorumBitmapUpdate,
+		ErrQuorumDoesNotExist,
+		ErrNoTotalStakeHistory,
+		ErrOperatorNotRegistered,
+		ErrNoStakeUpdate,
+		ErrBlockBeforeFirstApkUpdate,
+
+		ErrNotOwner,
+		ErrAlreadyInitialized,
+		ErrPaused,
+	} {
+		reasons[err.Error()] = err
+	}
+
+	const batchVariant = "EigenDACertVerificationUtils._verifyDACertsForQuorums: "
+	for _, err := range []error{
+		ErrBatchMetadataMismatch,
+		ErrInclusionProofInvalidV1,
+		ErrQuorumNumberMismatch,
+		ErrThresholdPercentagesInvalid,
+		ErrConfirmationThresholdNotMet,
+		ErrRequiredQuorumsNotConfirmed,
+	} {
+		reason := err.Error()
+		reasons[batchVariant+reason[strings.Index(reason, ": ")+2:]] = err
+	}
+}
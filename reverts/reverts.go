@@ -0,0 +1,152 @@
+// Package reverts decodes the revert data of failed EigenDA contract calls and transactions into
+// typed errors, so callers can branch with errors.Is instead of matching RPC error strings.
+//
+//	_, err := certVerifier.VerifyDACertV2(opts, batchHeader, blobInclusionInfo, nonSignerStakesAndSignature, signedQuorumNumbers)
+//	if errors.Is(reverts.Decode(err), reverts.ErrInclusionProofInvalid) {
+//		...
+//	}
+//
+// Known revert reasons map to the sentinel errors of this package, whose messages are the exact
+// reasons the contracts revert with.
+package reverts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	errorSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+
+	stringArguments  = abi.Arguments{{Type: mustNewType("string")}}
+	uint256Arguments = abi.Arguments{{Type: mustNewType("uint256")}}
+)
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
+
+// RevertError is a reverted call or transaction. It matches ErrExecutionReverted, the sentinel
+// error of its reason if the reason is known, and the error it was decoded from.
+type RevertError struct {
+	// Reason is the Error(string) message, empty for reverts without one.
+	Reason string
+	// PanicCode is the code of a Panic(uint256) revert, nil otherwise.
+	PanicCode *big.Int
+	// Data is the raw revert data.
+	Data []byte
+
+	known error
+	cause error
+}
+
+func (e *RevertError) Error() string {
+	switch {
+	case e.Reason != "":
+		return "execution reverted: " + e.Reason
+	case e.PanicCode != nil:
+		if e.known != nil {
+			return "execution reverted: " + e.known.Error()
+		}
+		return fmt.Sprintf("execution reverted: panic: code 0x%x", e.PanicCode)
+	case len(e.Data) > 0:
+		return "execution reverted: " + hexutil.Encode(e.Data)
+	default:
+		return "execution reverted"
+	}
+}
+
+func (e *RevertError) Unwrap() []error {
+	errs := []error{ErrExecutionReverted}
+	if e.known != nil {
+		errs = append(errs, e.known)
+	}
+	if e.cause != nil {
+		errs = append(errs, e.cause)
+	}
+	return errs
+}
+
+// Decode returns err as a *RevertError if it carries revert data, and err unchanged otherwise.
+func Decode(err error) error {
+	if err == nil {
+		return nil
+	}
+	var revertErr *RevertError
+	if errors.As(err, &revertErr) {
+		return err
+	}
+	data, ok := Data(err)
+	if !ok {
+		return err
+	}
+	revertErr = DecodeData(data)
+	revertErr.cause = err
+	return revertErr
+}
+
+// Data extracts the revert data from an RPC error, reporting false if err is not a revert.
+func Data(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	switch data := dataErr.ErrorData().(type) {
+	case string:
+		decoded, err := hexutil.Decode(data)
+		return decoded, err == nil
+	case []byte:
+		return data, true
+	case nil:
+		// A revert without a reason comes back without data.
+		return nil, strings.HasPrefix(dataErr.Error(), ErrExecutionReverted.Error())
+	default:
+		return nil, false
+	}
+}
+
+// DecodeData decodes raw revert data.
+func DecodeData(data []byte) *RevertError {
+	revertErr := &RevertError{Data: data}
+	if len(data) < 4 {
+		return revertErr
+	}
+	switch selector, payload := data[:4], data[4:]; {
+	case bytes.Equal(selector, errorSelector):
+		values, err := stringArguments.Unpack(payload)
+		if err != nil {
+			return revertErr
+		}
+		revertErr.Reason = values[0].(string)
+		revertErr.known = reasons[revertErr.Reason]
+	case bytes.Equal(selector, panicSelector):
+		values, err := uint256Arguments.Unpack(payload)
+		if err != nil {
+			return revertErr
+		}
+		revertErr.PanicCode = values[0].(*big.Int)
+		if revertErr.PanicCode.IsUint64() {
+			revertErr.known = panics[revertErr.PanicCode.Uint64()]
+		}
+	}
+	return revertErr
+}
+
+// Lookup returns the sentinel error of a revert reason.
+func Lookup(reason string) (error, bool) {
+	err, ok := reasons[reason]
+	return err, ok
+}
@@ -0,0 +1,245 @@
+package reverts_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Layr-Labs/eigenda/contracts/operatorstate"
+	"github.com/Layr-Labs/eigenda/contracts/payments"
+	"github.com/Layr-Labs/eigenda/contracts/reverts"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+	"github.com/Layr-Labs/eigenda/contracts/verification"
+)
+
+func encode(signature, typ string, value interface{}) []byte {
+	t, err := abi.NewType(typ, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	payload, err := abi.Arguments{{Type: t}}.Pack(value)
+	if err != nil {
+		panic(err)
+	}
+	return append(crypto.Keccak256([]byte(signature))[:4], payload...)
+}
+
+func errorData(reason string) []byte {
+	return encode("Error(string)", "string", reason)
+}
+
+func panicData(code int64) []byte {
+	return encode("Panic(uint256)", "uint256", big.NewInt(code))
+}
+
+// rpcError is the error an RPC client returns for a reverted call.
+type rpcError struct {
+	message string
+	data    interface{}
+}
+
+func (e *rpcError) Error() string          { return e.message }
+func (e *rpcError) ErrorCode() int         { return 3 }
+func (e *rpcError) ErrorData() interface{} { return e.data }
+
+func TestDecodeData(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		data      []byte
+		want      error
+		wantError string
+	}{
+		{
+			name:      "known reason",
+			data:      errorData("sum of quorumSplits must be 100"),
+			want:      reverts.ErrQuorumSplitsSum,
+			wantError: "execution reverted: sum of quorumSplits must be 100",
+		},
+		{
+			name:      "unknown reason",
+			data:      errorData("something else"),
+			wantError: "execution reverted: something else",
+		},
+		{
+			name:      "batch variant of a V1 reason",
+			data:      errorData("EigenDACertVerificationUtils._verifyDACertsForQuorums: inclusion proof is invalid"),
+			want:      reverts.ErrInclusionProofInvalidV1,
+			wantError: "execution reverted: EigenDACertVerificationUtils._verifyDACertsForQuorums: inclusion proof is invalid",
+		},
+		{
+			name:      "known panic",
+			data:      panicData(0x11),
+			want:      reverts.ErrPanicArithmetic,
+			wantError: "execution reverted: panic: arithmetic underflow or overflow (0x11)",
+		},
+		{
+			name:      "unknown panic",
+			data:      panicData(0x01),
+			wantError: "execution reverted: panic: code 0x1",
+		},
+		{
+			name:      "custom error",
+			data:      []byte{0xde, 0xad, 0xbe, 0xef},
+			wantError: "execution reverted: 0xdeadbeef",
+		},
+		{
+			name:      "malformed reason",
+			data:      errorData("reason")[:40],
+			wantError: "execution reverted: " + hexutil.Encode(errorData("reason")[:40]),
+		},
+		{
+			name:      "no data",
+			wantError: "execution reverted",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := reverts.DecodeData(tc.data)
+			if !errors.Is(err, reverts.ErrExecutionReverted) {
+				t.Errorf("%v does not match ErrExecutionReverted", err)
+			}
+			if tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf("%v does not match %v", err, tc.want)
+			}
+			if tc.want == nil && errors.Is(err, reverts.ErrQuorumSplitsSum) {
+				t.Errorf("%v matches an unrelated sentinel", err)
+			}
+			if err.Error() != tc.wantError {
+				t.Errorf("Error() = %q, want %q", err.Error(), tc.wantError)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	if reverts.Decode(nil) != nil {
+		t.Error("Decode(nil) != nil")
+	}
+
+	other := errors.New("connection refused")
+	if err := reverts.Decode(other); err != other {
+		t.Errorf("Decode of a non-revert = %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "hex data", err: &rpcError{"execution reverted: Pausable: index is paused", hexutil.Encode(errorData("Pausable: index is paused"))}, want: reverts.ErrPaused},
+		{name: "byte data", err: &rpcError{"execution reverted", panicData(0x32)}, want: reverts.ErrPanicOutOfBounds},
+		{name: "no data", err: &rpcError{"execution reverted", nil}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := reverts.Decode(tc.err)
+			var revertErr *reverts.RevertError
+			if !errors.As(err, &revertErr) {
+				t.Fatalf("Decode = %T %v, want a *RevertError", err, err)
+			}
+			if !errors.Is(err, tc.err) {
+				t.Errorf("%v does not match the error it was decoded from", err)
+			}
+			if tc.want != nil && !errors.Is(err, tc.want) {
+				t.Errorf("%v does not match %v", err, tc.want)
+			}
+			if again := reverts.Decode(err); again != err {
+				t.Errorf("decoding twice = %v", again)
+			}
+		})
+	}
+
+	// data that is not hex, and a data error that is not a revert, are left alone
+	for _, err := range []error{
+		&rpcError{"execution reverted", "not hex"},
+		&rpcError{"header not found", nil},
+	} {
+		if decoded := reverts.Decode(err); decoded != err {
+			t.Errorf("Decode(%v) = %v", err, decoded)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, want := range []error{
+		reverts.ErrBatchNotInCalldata,
+		reverts.ErrDepositTooLarge,
+		reverts.ErrReservationWindowInvalid,
+		reverts.ErrBlobProofsLengthMismatch,
+		reverts.ErrRelayKeyNotSet,
+		reverts.ErrECAddFailed,
+		reverts.ErrBlockBeforeFirstApkUpdate,
+		reverts.ErrNotOwner,
+	} {
+		if got, ok := reverts.Lookup(want.Error()); !ok || got != want {
+			t.Errorf("Lookup(%q) = %v, %v", want.Error(), got, ok)
+		}
+	}
+	if _, ok := reverts.Lookup("panic: arithmetic underflow or overflow (0x11)"); ok {
+		t.Error("panics are not revert reasons")
+	}
+}
+
+// TestAliases checks that the packages reporting contract checks in Go return the sentinels of
+// this package.
+func TestAliases(t *testing.T) {
+	for _, tc := range []struct {
+		alias, sentinel error
+	}{
+		{verification.ErrInclusionProofInvalid, reverts.ErrInclusionProofInvalid},
+		{verification.ErrSecurityAssumptionsNotMet, reverts.ErrSecurityAssumptionsNotMet},
+		{verification.ErrSignatureInvalid, reverts.ErrSignatureInvalid},
+		{verification.ErrBitmapExceedsMax, reverts.ErrBitmapExceedsMax},
+		{verification.ErrECAddFailed, reverts.ErrECAddFailed},
+		{verification.ErrPanicArithmetic, reverts.ErrPanicArithmetic},
+		{payments.ErrQuorumSplitsSum, reverts.ErrQuorumSplitsSum},
+		{payments.ErrQuorumSplitsOverflow, reverts.ErrPanicArithmetic},
+		{operatorstate.ErrOperatorNotRegistered, reverts.ErrOperatorNotRegistered},
+		{operatorstate.ErrBlockBeforeFirstApkUpdate, reverts.ErrBlockBeforeFirstApkUpdate},
+	} {
+		if !errors.Is(tc.alias, tc.sentinel) {
+			t.Errorf("%v does not match %v", tc.alias, tc.sentinel)
+		}
+	}
+}
+
+func TestDecodeContractReverts(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	reservation := structs.Reservation{
+		SymbolsPerSecond: 1,
+		StartTimestamp:   1,
+		EndTimestamp:     2,
+		QuorumNumbers:    []byte{0},
+		QuorumSplits:     []byte{100},
+	}
+
+	// not the owner
+	_, err := f.PaymentVault.SetReservation(f.TransactOpts(f.Confirmer), common.Address{1}, reservation)
+	if err := reverts.Decode(err); !errors.Is(err, reverts.ErrNotOwner) {
+		t.Errorf("setReservation from another account = %v, want %v", err, reverts.ErrNotOwner)
+	}
+
+	// already initialized
+	_, err = f.PaymentVault.Initialize(f.TransactOpts(f.Owner), f.Owner.Address, 1, 1, 1, 1, 1, 1)
+	if err := reverts.Decode(err); !errors.Is(err, reverts.ErrAlreadyInitialized) {
+		t.Errorf("initialize = %v, want %v", err, reverts.ErrAlreadyInitialized)
+	}
+
+	// a call rather than a transaction, at a block before the quorums had any stake
+	_, err = f.OperatorStateRetriever.GetCheckSignaturesIndices(
+		&bind.CallOpts{Context: context.Background()},
+		f.Addresses.RegistryCoordinator,
+		0,
+		[]byte{0},
+		[][32]byte{},
+	)
+	if err := reverts.Decode(err); !errors.Is(err, reverts.ErrNoTotalStakeHistory) || !errors.Is(err, operatorstate.ErrNoTotalStakeHistory) {
+		t.Errorf("getCheckSignaturesIndices at block 0 = %v, want %v", err, reverts.ErrNoTotalStakeHistory)
+	}
+}
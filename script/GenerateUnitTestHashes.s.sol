@@ -6,12 +6,14 @@ import "../src/interfaces/IEigenDAServiceManager.sol";
 import "forge-std/Script.sol";
 import "forge-std/console.sol";
 import "../src/interfaces/IEigenDAStructs.sol";
+import {EigenDAHasher} from "../src/libraries/EigenDAHasher.sol";
 
-// # To generate the hashes needed for core/serialization_test.go:
+// # To generate the hashes needed for core/serialization_test.go and hashing/testdata/golden_vectors.json:
 // forge script script/GenerateUnitTestHashes.s.sol  -v
 
 contract GenerateHashes is Script {
     string deployConfigPath = "script/input/eigenda_deploy_config.json";
+    string public constant GOLDEN_VECTORS_PATH = "hashing/testdata/golden_vectors.json";
 
     // deploy all the EigenDA contracts. Relies on many EL contracts having already been deployed.
     function run() external {
@@ -27,8 +29,20 @@ contract GenerateHashes is Script {
         bytes32 quorumBlobParamsHash = keccak256(abi.encode(quorumBlobParam));
         console.logBytes32(quorumBlobParamsHash);
 
-        BN254.G1Point memory commitment = BN254.G1Point({X: 1, Y: 2});
+        BlobHeader memory header = goldenBlobHeader();
+
+        console.logBytes(abi.encode(header));
+
+        bytes32 blobHeaderHash = keccak256(abi.encode(header));
+
+        console.logBytes32(blobHeaderHash);
+
+        _writeGoldenVectors();
+    }
 
+    /// @notice The V1 blob header hashed by the golden vectors
+    function goldenBlobHeader() public pure returns (BlobHeader memory) {
+        QuorumBlobParam[] memory quorumBlobParam = new QuorumBlobParam[](1);
         quorumBlobParam[0] = QuorumBlobParam({
             quorumNumber: 1,
             adversaryThresholdPercentage: 80,
@@ -36,13 +50,80 @@ contract GenerateHashes is Script {
             chunkLength: 10
         });
 
-        BlobHeader memory header =
-            BlobHeader({commitment: commitment, dataLength: 10, quorumBlobParams: quorumBlobParam});
+        BN254.G1Point memory commitment = BN254.G1Point({X: 1, Y: 2});
 
-        console.logBytes(abi.encode(header));
+        return BlobHeader({commitment: commitment, dataLength: 10, quorumBlobParams: quorumBlobParam});
+    }
 
-        bytes32 blobHeaderHash = keccak256(abi.encode(header));
+    /// @notice The V1 batch metadata hashed by the golden vectors
+    function goldenBatchMetadata() public pure returns (BatchMetadata memory) {
+        BatchHeader memory batchHeader = BatchHeader({
+            blobHeadersRoot: keccak256("blobHeadersRoot"),
+            quorumNumbers: hex"0001",
+            signedStakeForQuorums: hex"6450",
+            referenceBlockNumber: 100
+        });
 
-        console.logBytes32(blobHeaderHash);
+        return BatchMetadata({
+            batchHeader: batchHeader,
+            signatoryRecordHash: keccak256("signatoryRecordHash"),
+            confirmationBlockNumber: 101
+        });
+    }
+
+    /// @notice The V2 blob certificate hashed by the golden vectors
+    function goldenBlobCertificate() public pure returns (BlobCertificate memory) {
+        BN254.G2Point memory g2 = BN254.generatorG2();
+
+        BlobCommitment memory commitment = BlobCommitment({
+            commitment: BN254.G1Point({X: 1, Y: 2}),
+            lengthCommitment: g2,
+            lengthProof: g2,
+            length: 16
+        });
+
+        BlobHeaderV2 memory blobHeader = BlobHeaderV2({
+            version: 0,
+            quorumNumbers: hex"0001",
+            commitment: commitment,
+            paymentHeaderHash: keccak256("paymentHeaderHash")
+        });
+
+        uint32[] memory relayKeys = new uint32[](2);
+        relayKeys[0] = 0;
+        relayKeys[1] = 1;
+
+        return BlobCertificate({blobHeader: blobHeader, signature: hex"0102030405", relayKeys: relayKeys});
+    }
+
+    /// @notice The V2 batch header hashed by the golden vectors
+    function goldenBatchHeaderV2() public pure returns (BatchHeaderV2 memory) {
+        return BatchHeaderV2({batchRoot: keccak256("batchRoot"), referenceBlockNumber: 100});
+    }
+
+    function _writeGoldenVectors() internal {
+        BlobHeader memory blobHeader = goldenBlobHeader();
+        BatchMetadata memory batchMetadata = goldenBatchMetadata();
+        BlobCertificate memory blobCertificate = goldenBlobCertificate();
+        BatchHeaderV2 memory batchHeaderV2 = goldenBatchHeaderV2();
+
+        string memory obj = "vectors";
+        vm.serializeBytes(obj, "blobHeader", abi.encode(blobHeader));
+        vm.serializeBytes(obj, "batchMetadata", abi.encode(batchMetadata));
+        vm.serializeBytes(obj, "blobCertificate", abi.encode(blobCertificate));
+        vm.serializeBytes(obj, "batchHeaderV2", abi.encode(batchHeaderV2));
+
+        vm.serializeBytes32(obj, "hashBlobHeader", EigenDAHasher.hashBlobHeader(blobHeader));
+        vm.serializeBytes32(obj, "hashBatchHeader", EigenDAHasher.hashBatchHeaderMemory(batchMetadata.batchHeader));
+        vm.serializeBytes32(
+            obj, "hashReducedBatchHeader", EigenDAHasher.hashBatchHeaderToReducedBatchHeader(batchMetadata.batchHeader)
+        );
+        vm.serializeBytes32(obj, "hashBatchMetadata", EigenDAHasher.hashBatchMetadata(batchMetadata));
+        vm.serializeBytes32(obj, "hashBlobHeaderV2", EigenDAHasher.hashBlobHeaderV2(blobCertificate.blobHeader));
+        vm.serializeBytes32(obj, "hashBlobCertificate", EigenDAHasher.hashBlobCertificate(blobCertificate));
+        string memory json =
+            vm.serializeBytes32(obj, "hashBatchHeaderV2", EigenDAHasher.hashBatchHeaderV2(batchHeaderV2));
+
+        vm.writeJson(json, GOLDEN_VECTORS_PATH);
     }
 }
@@ -0,0 +1,93 @@
+// Package security evaluates the blob version security check of EigenDACertVerificationUtils
+// offline, and solves it for the boundary values governance needs when proposing new
+// VersionedBlobParams.
+package security
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/verification"
+)
+
+// ErrNoSolution is returned when no value of the solved parameter satisfies the check.
+var ErrNoSolution = errors.New("no parameter value satisfies the security assumptions")
+
+// Verify mirrors EigenDACertVerifier.verifyDACertSecurityParams(VersionedBlobParams, SecurityThresholds).
+func Verify(
+	blobParams structs.VersionedBlobParams,
+	securityThresholds structs.SecurityThresholds,
+) error {
+	return verification.VerifySecurityParams(blobParams, securityThresholds)
+}
+
+// MaxAdversaryThreshold returns the highest adversary threshold that still passes the check for
+// blobParams at the given confirmation threshold. A higher adversary threshold narrows the gap to
+// the confirmation threshold, so every lower adversary threshold passes as well.
+//
+// The search starts from an adversary threshold of zero, the widest gap. A failure there other
+// than the security assumptions not being met, such as a zero coding rate, is a fault of blobParams
+// and is returned as is. Past the first passing threshold, any failure ends the search, including
+// the arithmetic panic the contract hits once the gap gets too narrow for the coding rate.
+func MaxAdversaryThreshold(
+	blobParams structs.VersionedBlobParams,
+	confirmationThreshold uint8,
+) (uint8, error) {
+	if confirmationThreshold == 0 {
+		return 0, ErrNoSolution
+	}
+	if err := Verify(blobParams, structs.SecurityThresholds{ConfirmationThreshold: confirmationThreshold}); err != nil {
+		if errors.Is(err, verification.ErrSecurityAssumptionsNotMet) {
+			return 0, ErrNoSolution
+		}
+		return 0, err
+	}
+	adversaryThreshold := uint8(0)
+	for adversaryThreshold+1 < confirmationThreshold {
+		err := Verify(blobParams, structs.SecurityThresholds{
+			ConfirmationThreshold: confirmationThreshold,
+			AdversaryThreshold:    adversaryThreshold + 1,
+		})
+		if err != nil {
+			break
+		}
+		adversaryThreshold++
+	}
+	return adversaryThreshold, nil
+}
+
+// MinNumChunks returns the fewest chunks that pass the check for the given operator cap, coding
+// rate and thresholds. Only the NumChunks field of the result is solved for.
+func MinNumChunks(
+	maxNumOperators uint32,
+	codingRate uint8,
+	securityThresholds structs.SecurityThresholds,
+) (uint32, error) {
+	blobParams := structs.VersionedBlobParams{
+		MaxNumOperators: maxNumOperators,
+		NumChunks:       math.MaxUint32,
+		CodingRate:      codingRate,
+	}
+	// rule out failures that no chunk count can fix
+	if err := Verify(blobParams, securityThresholds); err != nil {
+		if errors.Is(err, verification.ErrSecurityAssumptionsNotMet) {
+			return 0, ErrNoSolution
+		}
+		return 0, err
+	}
+
+	gamma := uint64(securityThresholds.ConfirmationThreshold - securityThresholds.AdversaryThreshold)
+	perChunk := 10000 - (1_000_000/gamma)/uint64(codingRate)
+	required := uint64(maxNumOperators) * 10000
+	if required == 0 {
+		return 0, nil
+	}
+
+	numChunks := (required + perChunk - 1) / perChunk
+	blobParams.NumChunks = uint32(numChunks)
+	if err := Verify(blobParams, securityThresholds); err != nil {
+		return 0, err
+	}
+	return blobParams.NumChunks, nil
+}
@@ -0,0 +1,206 @@
+package security_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	"github.com/Layr-Labs/eigenda/contracts/reverts"
+	"github.com/Layr-Labs/eigenda/contracts/security"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+	"github.com/Layr-Labs/eigenda/contracts/verification"
+)
+
+// contractCheck runs EigenDACertVerifier.verifyDACertSecurityParams on the fixture.
+type contractCheck func(structs.VersionedBlobParams, structs.SecurityThresholds) error
+
+func newContractCheck(t *testing.T) contractCheck {
+	f := fixture.NewForTest(t, fixture.Config{})
+	opts := &bind.CallOpts{Context: context.Background()}
+	return func(blobParams structs.VersionedBlobParams, securityThresholds structs.SecurityThresholds) error {
+		return reverts.Decode(f.CertVerifier.VerifyDACertSecurityParams(opts, blobParams, securityThresholds))
+	}
+}
+
+func TestMaxAdversaryThreshold(t *testing.T) {
+	check := newContractCheck(t)
+
+	for _, tc := range []struct {
+		name                  string
+		blobParams            structs.VersionedBlobParams
+		confirmationThreshold uint8
+		want                  uint8
+		wantErr               error
+	}{
+		{
+			name:                  "default",
+			blobParams:            fixture.DefaultVersionedBlobParams,
+			confirmationThreshold: 55,
+			want:                  33,
+		},
+		{
+			name:                  "more chunks",
+			blobParams:            structs.VersionedBlobParams{MaxNumOperators: 3537, NumChunks: 16384, CodingRate: 8},
+			confirmationThreshold: 55,
+			want:                  39,
+		},
+		{
+			name:                  "higher coding rate",
+			blobParams:            structs.VersionedBlobParams{MaxNumOperators: 200, NumChunks: 8192, CodingRate: 16},
+			confirmationThreshold: 90,
+			want:                  83,
+		},
+		{
+			name:                  "too few chunks for any threshold",
+			blobParams:            structs.VersionedBlobParams{MaxNumOperators: 3537, NumChunks: 100, CodingRate: 8},
+			confirmationThreshold: 55,
+			wantErr:               security.ErrNoSolution,
+		},
+		{
+			name:                  "zero confirmation threshold",
+			blobParams:            fixture.DefaultVersionedBlobParams,
+			confirmationThreshold: 0,
+			wantErr:               security.ErrNoSolution,
+		},
+		{
+			name:                  "zero coding rate",
+			blobParams:            structs.VersionedBlobParams{MaxNumOperators: 3537, NumChunks: 8192, CodingRate: 0},
+			confirmationThreshold: 55,
+			wantErr:               verification.ErrPanicDivisionByZero,
+		},
+		{
+			name:                  "operator cap overflows",
+			blobParams:            structs.VersionedBlobParams{MaxNumOperators: 500_000, NumChunks: 8192, CodingRate: 8},
+			confirmationThreshold: 55,
+			wantErr:               verification.ErrPanicArithmetic,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := security.MaxAdversaryThreshold(tc.blobParams, tc.confirmationThreshold)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				// faults of the blob params are the contract's revert at the widest gap
+				if tc.wantErr != security.ErrNoSolution {
+					thresholds := structs.SecurityThresholds{ConfirmationThreshold: tc.confirmationThreshold}
+					if err := check(tc.blobParams, thresholds); !errors.Is(err, tc.wantErr) {
+						t.Errorf("contract = %v, want %v", err, tc.wantErr)
+					}
+				}
+				return
+			}
+			if got != tc.want {
+				t.Errorf("MaxAdversaryThreshold = %d, want %d", got, tc.want)
+			}
+
+			// the contract accepts the solution and rejects the next threshold up
+			thresholds := structs.SecurityThresholds{ConfirmationThreshold: tc.confirmationThreshold, AdversaryThreshold: got}
+			if err := check(tc.blobParams, thresholds); err != nil {
+				t.Errorf("contract rejects adversary threshold %d: %v", got, err)
+			}
+			thresholds.AdversaryThreshold++
+			if err := check(tc.blobParams, thresholds); err == nil {
+				t.Errorf("contract accepts adversary threshold %d", thresholds.AdversaryThreshold)
+			}
+		})
+	}
+}
+
+func TestMinNumChunks(t *testing.T) {
+	check := newContractCheck(t)
+
+	for _, tc := range []struct {
+		name            string
+		maxNumOperators uint32
+		codingRate      uint8
+		thresholds      structs.SecurityThresholds
+		want            uint32
+		wantErr         error
+	}{
+		{
+			name:            "default",
+			maxNumOperators: 3537,
+			codingRate:      8,
+			thresholds:      fixture.DefaultSecurityThresholds,
+			want:            8190,
+		},
+		{
+			name:            "single operator",
+			maxNumOperators: 1,
+			codingRate:      8,
+			thresholds:      fixture.DefaultSecurityThresholds,
+			want:            3,
+		},
+		{
+			name:            "no operators",
+			maxNumOperators: 0,
+			codingRate:      8,
+			thresholds:      fixture.DefaultSecurityThresholds,
+			want:            0,
+		},
+		{
+			name:            "coding rate too low for the gap",
+			maxNumOperators: 3537,
+			codingRate:      1,
+			thresholds:      fixture.DefaultSecurityThresholds,
+			wantErr:         verification.ErrPanicArithmetic,
+		},
+		{
+			name:            "operator cap overflows",
+			maxNumOperators: 500_000,
+			codingRate:      8,
+			thresholds:      fixture.DefaultSecurityThresholds,
+			wantErr:         verification.ErrPanicArithmetic,
+		},
+		{
+			name:            "invalid thresholds",
+			maxNumOperators: 3537,
+			codingRate:      8,
+			thresholds:      structs.SecurityThresholds{ConfirmationThreshold: 33, AdversaryThreshold: 55},
+			wantErr:         verification.ErrInvalidSecurityThresholds,
+		},
+		{
+			name:            "reduction uses the whole chunk",
+			maxNumOperators: 1,
+			codingRate:      1,
+			thresholds:      structs.SecurityThresholds{ConfirmationThreshold: 100, AdversaryThreshold: 0},
+			wantErr:         security.ErrNoSolution,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := security.MinNumChunks(tc.maxNumOperators, tc.codingRate, tc.thresholds)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+			if err != nil {
+				blobParams := structs.VersionedBlobParams{MaxNumOperators: tc.maxNumOperators, NumChunks: 1 << 31, CodingRate: tc.codingRate}
+				if tc.wantErr != security.ErrNoSolution {
+					if err := check(blobParams, tc.thresholds); !errors.Is(err, tc.wantErr) {
+						t.Errorf("contract = %v, want %v", err, tc.wantErr)
+					}
+				}
+				return
+			}
+			if got != tc.want {
+				t.Errorf("MinNumChunks = %d, want %d", got, tc.want)
+			}
+
+			// the contract accepts the solution and rejects one chunk fewer
+			blobParams := structs.VersionedBlobParams{MaxNumOperators: tc.maxNumOperators, NumChunks: got, CodingRate: tc.codingRate}
+			if err := check(blobParams, tc.thresholds); err != nil {
+				t.Errorf("contract rejects %d chunks: %v", got, err)
+			}
+			if got == 0 {
+				return
+			}
+			blobParams.NumChunks--
+			if err := check(blobParams, tc.thresholds); !errors.Is(err, verification.ErrSecurityAssumptionsNotMet) {
+				t.Errorf("contract with %d chunks = %v, want %v", blobParams.NumChunks, err, verification.ErrSecurityAssumptionsNotMet)
+			}
+		})
+	}
+}
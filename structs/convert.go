@@ -0,0 +1,97 @@
+package structs
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+// Convert copies src into a new value of type T. The two types must have the same shape:
+// structs with the same fields in the same order, slices and arrays of convertible elements,
+// and otherwise identical types. Every binding's copy of an IEigenDAStructs.sol struct has this
+// shape, so Convert moves a value between any two of them, e.g.
+//
+//	header, err := structs.Convert[contractEigenDAServiceManager.BatchHeader](batchHeader)
+//
+// The result is a deep copy: slices and *big.Int values are copied, so modifying either value
+// afterwards leaves the other unchanged.
+func Convert[T any](src any) (T, error) {
+	var dst T
+	if err := convertValue(reflect.ValueOf(&dst).Elem(), reflect.ValueOf(src), "value"); err != nil {
+		return dst, err
+	}
+	return dst, nil
+}
+
+// MustConvert is like Convert but panics when the types do not match. It is meant for
+// conversions between bindings of the same struct, which cannot fail.
+func MustConvert[T any](src any) T {
+	dst, err := Convert[T](src)
+	if err != nil {
+		panic(err)
+	}
+	return dst
+}
+
+func convertValue(dst, src reflect.Value, path string) error {
+	if !src.IsValid() {
+		return fmt.Errorf("%s: cannot convert nil to %s", path, dst.Type())
+	}
+
+	switch dst.Kind() {
+	case reflect.Struct:
+		if src.Kind() != reflect.Struct || src.NumField() != dst.NumField() {
+			return mismatch(dst, src, path)
+		}
+		for i := 0; i < dst.NumField(); i++ {
+			name := dst.Type().Field(i).Name
+			if src.Type().Field(i).Name != name {
+				return mismatch(dst, src, path)
+			}
+			if err := convertValue(dst.Field(i), src.Field(i), path+"."+name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		if src.Kind() != reflect.Slice {
+			return mismatch(dst, src, path)
+		}
+		if src.IsNil() {
+			return nil
+		}
+		dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			if err := convertValue(dst.Index(i), src.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		if src.Kind() != reflect.Array || src.Len() != dst.Len() {
+			return mismatch(dst, src, path)
+		}
+		for i := 0; i < src.Len(); i++ {
+			if err := convertValue(dst.Index(i), src.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Pointer:
+		if dst.Type() != bigIntType || src.Type() != bigIntType {
+			return mismatch(dst, src, path)
+		}
+		if !src.IsNil() {
+			dst.Set(reflect.ValueOf(new(big.Int).Set(src.Interface().(*big.Int))))
+		}
+	default:
+		if src.Kind() != dst.Kind() || !src.Type().ConvertibleTo(dst.Type()) {
+			return mismatch(dst, src, path)
+		}
+		dst.Set(src.Convert(dst.Type()))
+	}
+	return nil
+}
+
+func mismatch(dst, src reflect.Value, path string) error {
+	return fmt.Errorf("%s: cannot convert %s to %s", path, src.Type(), dst.Type())
+}
@@ -0,0 +1,222 @@
+package structs
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	contractEigenDAServiceManager "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	contractEigenDAThresholdRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAThresholdRegistry"
+	contractMockRollup "github.com/Layr-Labs/eigenda/contracts/bindings/MockRollup"
+	contractRegistryCoordinator "github.com/Layr-Labs/eigenda/contracts/bindings/RegistryCoordinator"
+)
+
+func testG1Point(x, y int64) G1Point {
+	return G1Point{X: big.NewInt(x), Y: big.NewInt(y)}
+}
+
+func testG2Point(v int64) G2Point {
+	return G2Point{
+		X: [2]*big.Int{big.NewInt(v), big.NewInt(v + 1)},
+		Y: [2]*big.Int{big.NewInt(v + 2), big.NewInt(v + 3)},
+	}
+}
+
+func TestConvertBetweenBindings(t *testing.T) {
+	batchHeader := BatchHeader{
+		BlobHeadersRoot:       [32]byte{1},
+		QuorumNumbers:         []byte{0, 1},
+		SignedStakeForQuorums: []byte{90, 80},
+		ReferenceBlockNumber:  12,
+	}
+	params := NonSignerStakesAndSignature{
+		NonSignerQuorumBitmapIndices: []uint32{3},
+		NonSignerPubkeys:             []G1Point{testG1Point(1, 2)},
+		QuorumApks:                   []G1Point{testG1Point(3, 4), testG1Point(5, 6)},
+		ApkG2:                        testG2Point(7),
+		Sigma:                        testG1Point(11, 12),
+		QuorumApkIndices:             []uint32{1, 2},
+		TotalStakeIndices:            []uint32{3, 4},
+		NonSignerStakeIndices:        [][]uint32{{5}, {}},
+	}
+	blobParams := VersionedBlobParams{MaxNumOperators: 3537, NumChunks: 8192, CodingRate: 8}
+
+	for _, tc := range []struct {
+		name      string
+		src       any
+		convert   func(any) (any, error)
+		roundTrip func(any) (any, error)
+	}{
+		{
+			name: "BatchHeader",
+			src:  batchHeader,
+			convert: func(v any) (any, error) {
+				return Convert[contractEigenDAServiceManager.BatchHeader](v)
+			},
+			roundTrip: func(v any) (any, error) { return Convert[BatchHeader](v) },
+		},
+		{
+			name: "NonSignerStakesAndSignature",
+			src:  params,
+			convert: func(v any) (any, error) {
+				return Convert[contractEigenDAServiceManager.IBLSSignatureCheckerNonSignerStakesAndSignature](v)
+			},
+			roundTrip: func(v any) (any, error) { return Convert[NonSignerStakesAndSignature](v) },
+		},
+		{
+			name: "G1Point",
+			src:  testG1Point(1, 2),
+			convert: func(v any) (any, error) {
+				return Convert[contractMockRollup.BN254G1Point](v)
+			},
+			roundTrip: func(v any) (any, error) { return Convert[G1Point](v) },
+		},
+		{
+			name: "G2Point",
+			src:  testG2Point(1),
+			convert: func(v any) (any, error) {
+				return Convert[contractRegistryCoordinator.BN254G2Point](v)
+			},
+			roundTrip: func(v any) (any, error) { return Convert[G2Point](v) },
+		},
+		{
+			name: "VersionedBlobParams",
+			src:  blobParams,
+			convert: func(v any) (any, error) {
+				return Convert[contractEigenDAThresholdRegistry.VersionedBlobParams](v)
+			},
+			roundTrip: func(v any) (any, error) { return Convert[VersionedBlobParams](v) },
+		},
+		{
+			name: "zero value",
+			src:  NonSignerStakesAndSignature{},
+			convert: func(v any) (any, error) {
+				return Convert[contractEigenDAServiceManager.IBLSSignatureCheckerNonSignerStakesAndSignature](v)
+			},
+			roundTrip: func(v any) (any, error) { return Convert[NonSignerStakesAndSignature](v) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			converted, err := tc.convert(tc.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			back, err := tc.roundTrip(converted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(back, tc.src) {
+				t.Errorf("round trip = %+v, want %+v", back, tc.src)
+			}
+		})
+	}
+}
+
+func TestConvertDeepCopies(t *testing.T) {
+	src := NonSignerStakesAndSignature{
+		NonSignerPubkeys:      []G1Point{testG1Point(1, 2)},
+		ApkG2:                 testG2Point(7),
+		Sigma:                 testG1Point(11, 12),
+		NonSignerStakeIndices: [][]uint32{{5}},
+	}
+	dst := MustConvert[contractEigenDAServiceManager.IBLSSignatureCheckerNonSignerStakesAndSignature](src)
+
+	src.NonSignerPubkeys[0].X.SetInt64(100)
+	src.ApkG2.Y[1].SetInt64(100)
+	src.Sigma.Y.SetInt64(100)
+	src.NonSignerStakeIndices[0][0] = 100
+
+	if dst.NonSignerPubkeys[0].X.Int64() != 1 || dst.ApkG2.Y[1].Int64() != 10 || dst.Sigma.Y.Int64() != 12 {
+		t.Errorf("converted points share their coordinates with the source: %+v", dst)
+	}
+	if dst.NonSignerStakeIndices[0][0] != 5 {
+		t.Errorf("converted indices share their backing array with the source")
+	}
+
+	// the same type is copied as well
+	g1 := testG1Point(1, 2)
+	copied := MustConvert[G1Point](g1)
+	g1.X.SetInt64(100)
+	if copied.X.Int64() != 1 {
+		t.Errorf("converting to the same type shares coordinates")
+	}
+}
+
+func TestConvertKeepsNil(t *testing.T) {
+	dst, err := Convert[contractEigenDAServiceManager.IBLSSignatureCheckerNonSignerStakesAndSignature](
+		NonSignerStakesAndSignature{Sigma: G1Point{X: big.NewInt(1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dst.NonSignerPubkeys != nil || dst.QuorumApks != nil {
+		t.Errorf("nil slices converted to %v, %v", dst.NonSignerPubkeys, dst.QuorumApks)
+	}
+	if dst.Sigma.Y != nil || dst.ApkG2.X[0] != nil {
+		t.Errorf("nil coordinates converted to %v, %v", dst.Sigma.Y, dst.ApkG2.X[0])
+	}
+}
+
+func TestConvertMismatch(t *testing.T) {
+	type renamed struct {
+		X, Z *big.Int
+	}
+	type shortArray struct {
+		X [1]*big.Int
+		Y [2]*big.Int
+	}
+	type wrongKind struct {
+		X, Y uint64
+	}
+
+	for _, tc := range []struct {
+		name    string
+		convert func() error
+		want    string
+	}{
+		{
+			name:    "field names differ",
+			convert: func() error { _, err := Convert[renamed](testG1Point(1, 2)); return err },
+			want:    "value: cannot convert",
+		},
+		{
+			name:    "array lengths differ",
+			convert: func() error { _, err := Convert[shortArray](testG2Point(1)); return err },
+			want:    "value.X: cannot convert [2]*big.Int to [1]*big.Int",
+		},
+		{
+			name:    "big.Int to integer",
+			convert: func() error { _, err := Convert[wrongKind](testG1Point(1, 2)); return err },
+			want:    "value.X: cannot convert *big.Int to uint64",
+		},
+		{
+			name:    "nil",
+			convert: func() error { _, err := Convert[G1Point](nil); return err },
+			want:    "value: cannot convert nil",
+		},
+		{
+			name: "nested",
+			convert: func() error {
+				_, err := Convert[[]renamed]([]G1Point{testG1Point(1, 2)})
+				return err
+			},
+			want: "value[0]: cannot convert",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.convert()
+			if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+				t.Errorf("error = %v, want prefix %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestMustConvertPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustConvert of mismatched types did not panic")
+		}
+	}()
+	MustConvert[G2Point](testG1Point(1, 2))
+}
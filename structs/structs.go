@@ -0,0 +1,67 @@
+// Package structs is the single Go vocabulary for the structs declared in
+// src/interfaces/IEigenDAStructs.sol and the middleware types EigenDA passes around.
+//
+// abigen emits a separate copy of every struct into each binding that uses it, so the same
+// BN254.G1Point exists as six unrelated Go types. The types here alias one copy of each, and
+// Convert moves values between the copies held by the other bindings.
+package structs
+
+import (
+	contractEigenDACertVerifier "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDACertVerifier"
+	contractEigenDADisperserRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDADisperserRegistry"
+	contractEigenDARelayRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDARelayRegistry"
+	contractEigenDAServiceManager "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	contractOperatorStateRetriever "github.com/Layr-Labs/eigenda/contracts/bindings/OperatorStateRetriever"
+	contractPaymentVault "github.com/Layr-Labs/eigenda/contracts/bindings/PaymentVault"
+)
+
+///////////////////////// BN254 ///////////////////////////////
+
+type (
+	G1Point = contractEigenDACertVerifier.BN254G1Point
+	G2Point = contractEigenDACertVerifier.BN254G2Point
+)
+
+///////////////////////// V1 ///////////////////////////////
+
+type (
+	QuorumBlobParam       = contractEigenDACertVerifier.QuorumBlobParam
+	BlobHeader            = contractEigenDACertVerifier.BlobHeader
+	BatchHeader           = contractEigenDACertVerifier.BatchHeader
+	BatchMetadata         = contractEigenDACertVerifier.BatchMetadata
+	BlobVerificationProof = contractEigenDACertVerifier.BlobVerificationProof
+)
+
+// ReducedBatchHeader mirrors the ReducedBatchHeader struct, which no contract exposes in its ABI.
+type ReducedBatchHeader struct {
+	BlobHeadersRoot      [32]byte
+	ReferenceBlockNumber uint32
+}
+
+///////////////////////// V2 ///////////////////////////////
+
+type (
+	VersionedBlobParams = contractEigenDACertVerifier.VersionedBlobParams
+	SecurityThresholds  = contractEigenDACertVerifier.SecurityThresholds
+	RelayInfo           = contractEigenDARelayRegistry.RelayInfo
+	DisperserInfo       = contractEigenDADisperserRegistry.DisperserInfo
+	BlobInclusionInfo   = contractEigenDACertVerifier.BlobInclusionInfo
+	BlobCertificate     = contractEigenDACertVerifier.BlobCertificate
+	BlobHeaderV2        = contractEigenDACertVerifier.BlobHeaderV2
+	BlobCommitment      = contractEigenDACertVerifier.BlobCommitment
+	SignedBatch         = contractEigenDACertVerifier.SignedBatch
+	BatchHeaderV2       = contractEigenDACertVerifier.BatchHeaderV2
+	Attestation         = contractEigenDACertVerifier.Attestation
+)
+
+///////////////////////// SIGNATURE VERIFIER ///////////////////////////////
+
+type (
+	NonSignerStakesAndSignature = contractEigenDACertVerifier.NonSignerStakesAndSignature
+	QuorumStakeTotals           = contractEigenDAServiceManager.IBLSSignatureCheckerQuorumStakeTotals
+	CheckSignaturesIndices      = contractOperatorStateRetriever.OperatorStateRetrieverCheckSignaturesIndices
+)
+
+///////////////////////// PAYMENTS ///////////////////////////////
+
+type Reservation = contractPaymentVault.IPaymentVaultReservation
@@ -0,0 +1,83 @@
+package structsjson
+
+import (
+	"reflect"
+
+	"github.com/Layr-Labs/eigenda/contracts/hashing"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// derivedHash is a hash the contracts derive from a struct, printed alongside its fields.
+type derivedHash struct {
+	key string
+	// hash is nil when the struct has unset integers, which the ABI encoding hashed cannot hold.
+	hash *[32]byte
+}
+
+// derive returns the derived hash of v, or nil for structs without one. Only the structs package
+// types match, which are the EigenDACertVerifier binding types; the copies other bindings
+// generate of the same Solidity structs are distinct Go types and print without a derived hash.
+func derive(v reflect.Value) (*derivedHash, error) {
+	var key string
+	var hash func() ([32]byte, error)
+	switch value := v.Interface().(type) {
+	case structs.BlobHeader:
+		key = "blobHeaderHash"
+		hash = func() ([32]byte, error) { return hashing.HashBlobHeader(value) }
+	case structs.BatchHeader:
+		// the hash operators sign and BatchConfirmed emits
+		key = "batchHeaderHash"
+		hash = func() ([32]byte, error) { return hashing.HashBatchHeaderToReducedBatchHeader(value) }
+	case structs.BatchMetadata:
+		key = "batchMetadataHash"
+		hash = func() ([32]byte, error) { return hashing.HashBatchMetadata(value) }
+	case structs.BatchHeaderV2:
+		key = "batchHeaderHash"
+		hash = func() ([32]byte, error) { return hashing.HashBatchHeaderV2(value) }
+	case structs.BlobHeaderV2:
+		// EigenDA identifies V2 blobs by the hash of their header
+		key = "blobKey"
+		hash = func() ([32]byte, error) { return hashing.HashBlobHeaderV2(value) }
+	case structs.BlobCertificate:
+		key = "blobCertificateHash"
+		hash = func() ([32]byte, error) { return hashing.HashBlobCertificate(value) }
+	default:
+		return nil, nil
+	}
+	// the ABI encoder panics on nil integers, which zero valued and partly filled structs have
+	if !allIntsSet(v) {
+		return &derivedHash{key: key}, nil
+	}
+	h, err := hash()
+	if err != nil {
+		return nil, err
+	}
+	return &derivedHash{key: key, hash: &h}, nil
+}
+
+// allIntsSet reports whether every *big.Int reachable from v is non-nil.
+func allIntsSet(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return v.Type() != bigIntType
+		}
+		return v.Type() == bigIntType || allIntsSet(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !allIntsSet(v.Field(i)) {
+				return false
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return true
+		}
+		for i := 0; i < v.Len(); i++ {
+			if !allIntsSet(v.Index(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,404 @@
+// Package structsjson marshals the EigenDA structs, and anything built from them such as certs, to
+// JSON that reads well when debugging and loads back into the same values, so certs can be kept
+// as test fixtures.
+//
+// Fields keep their Solidity names and order. BN254 point coordinates print as 32 byte hex, other
+// big integers as decimal strings, quorum number lists and other per-quorum byte strings as lists
+// of numbers, and all other bytes as hex. Nil slices and pointers print as null, so they stay nil
+// when loaded back.
+//
+// Structs with a hash the contracts derive from them gain it as an extra field: blobHeaderHash,
+// batchHeaderHash, batchMetadataHash, blobKey or blobCertificateHash. Only the structs package
+// types gain it, not the copies of the same structs in other bindings, and structs with unset
+// integers are left without it, as they cannot be hashed. Unmarshal ignores a missing derived
+// field but rejects one that does not match the loaded struct, which catches fixtures edited by
+// hand.
+package structsjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// byteListFields are the bytes fields holding one byte per quorum, which print as lists of numbers.
+var byteListFields = map[string]bool{
+	"QuorumNumbers":         true,
+	"SignedQuorumNumbers":   true,
+	"SignedStakeForQuorums": true,
+	"QuorumIndices":         true,
+	"QuorumSplits":          true,
+}
+
+var (
+	bigIntType          = reflect.TypeOf((*big.Int)(nil))
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Marshal returns the JSON encoding of v.
+func Marshal(v any) ([]byte, error) {
+	tree, err := encodeValue(reflect.ValueOf(v), "", false)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
+
+// MarshalIndent is like Marshal but indents the output like json.MarshalIndent.
+func MarshalIndent(v any, prefix, indent string) ([]byte, error) {
+	data, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, prefix, indent); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// Sprint returns v as indented JSON, the pretty printed form for logs and error messages. Values
+// that cannot be marshaled, or whose marshaling panics, fall back to %+v.
+func Sprint(v any) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("%+v (panic: %v)", v, r)
+		}
+	}()
+	data, err := MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%+v (%v)", v, err)
+	}
+	return string(data)
+}
+
+// Unmarshal loads the JSON encoding of a value written by Marshal into v, which must be a pointer.
+// Every field must be present and unknown fields are rejected.
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("structsjson: Unmarshal needs a non-nil pointer, got %T", v)
+	}
+	return decodeValue(json.RawMessage(data), rv.Elem(), "value", "", false)
+}
+
+// member is a field of an object, which marshals its members in order.
+type member struct {
+	key   string
+	value any
+}
+
+type object []member
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonName returns the Solidity name of a binding field, its Go name with the first letter lowered.
+func jsonName(field string) string {
+	r, size := utf8.DecodeRuneInString(field)
+	return string(unicode.ToLower(r)) + field[size:]
+}
+
+// isPoint reports whether t is one of the bindings' copies of BN254.G1Point or BN254.G2Point.
+func isPoint(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && (strings.HasSuffix(t.Name(), "G1Point") || strings.HasSuffix(t.Name(), "G2Point"))
+}
+
+// encodeValue returns the JSON tree of v. field is the name of the struct field v was read from,
+// and point is set inside BN254 points.
+func encodeValue(v reflect.Value, field string, point bool) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	t := v.Type()
+
+	if t == bigIntType {
+		if v.IsNil() {
+			return nil, nil
+		}
+		n := v.Interface().(*big.Int)
+		if point {
+			if n.Sign() < 0 || n.BitLen() > 256 {
+				return nil, fmt.Errorf("%s: coordinate %s does not fit 32 bytes", field, n)
+			}
+			return hexutil.Encode(n.FillBytes(make([]byte, 32))), nil
+		}
+		return n.String(), nil
+	}
+	if t.Implements(textMarshalerType) && t.Kind() != reflect.Pointer {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(v.Elem(), field, point)
+	case reflect.Struct:
+		o := make(object, 0, t.NumField()+1)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			value, err := encodeValue(v.Field(i), f.Name, point || isPoint(t))
+			if err != nil {
+				return nil, err
+			}
+			o = append(o, member{key: jsonName(f.Name), value: value})
+		}
+		derived, err := derive(v)
+		if err != nil {
+			return nil, err
+		}
+		if derived != nil && derived.hash != nil {
+			o = append(o, member{key: derived.key, value: hexutil.Encode(derived.hash[:])})
+		}
+		return o, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && !byteListFields[field] {
+			return hexutil.Encode(v.Bytes()), nil
+		}
+		fallthrough
+	case reflect.Array:
+		if t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return hexutil.Encode(b), nil
+		}
+		list := make([]any, v.Len())
+		for i := range list {
+			value, err := encodeValue(v.Index(i), field, point)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	case reflect.Bool, reflect.String,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Interface(), nil
+	default:
+		return nil, fmt.Errorf("%s: cannot marshal %s", field, t)
+	}
+}
+
+// decodeValue loads raw into v, which must be settable. path locates v in error messages.
+func decodeValue(raw json.RawMessage, v reflect.Value, path, field string, point bool) error {
+	t := v.Type()
+	isNull := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+
+	if t == bigIntType {
+		if isNull {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		n, ok := new(big.Int), false
+		if point {
+			if len(s) == 66 && strings.HasPrefix(s, "0x") {
+				n, ok = n.SetString(s[2:], 16)
+			}
+		} else if len(s) > 0 && s[0] != '+' {
+			n, ok = n.SetString(s, 10)
+		}
+		if !ok || n.Sign() < 0 {
+			return fmt.Errorf("%s: invalid integer %q", path, s)
+		}
+		v.Set(reflect.ValueOf(n))
+		return nil
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) && t.Kind() != reflect.Pointer {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if isNull {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		elem := reflect.New(t.Elem())
+		if err := decodeValue(raw, elem.Elem(), path, field, point); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	case reflect.Struct:
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &members); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if members == nil {
+			return fmt.Errorf("%s: expected an object", path)
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			key := jsonName(f.Name)
+			fieldRaw, ok := members[key]
+			if !ok {
+				return fmt.Errorf("%s: missing field %q", path, key)
+			}
+			delete(members, key)
+			if err := decodeValue(fieldRaw, v.Field(i), path+"."+key, f.Name, point || isPoint(t)); err != nil {
+				return err
+			}
+		}
+		derived, err := derive(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if derived != nil {
+			if derivedRaw, ok := members[derived.key]; ok {
+				delete(members, derived.key)
+				if derived.hash == nil {
+					return fmt.Errorf("%s.%s: cannot be checked against a struct with unset integers", path, derived.key)
+				}
+				var s string
+				if err := json.Unmarshal(derivedRaw, &s); err != nil {
+					return fmt.Errorf("%s.%s: %w", path, derived.key, err)
+				}
+				if want := hexutil.Encode(derived.hash[:]); s != want {
+					return fmt.Errorf("%s.%s: %s does not match the %s derived from the fields", path, derived.key, s, want)
+				}
+			}
+		}
+		for key := range members {
+			return fmt.Errorf("%s: unknown field %q", path, key)
+		}
+		return nil
+	case reflect.Slice:
+		if isNull {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && !byteListFields[field] {
+			b, err := decodeHex(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		var list []json.RawMessage
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.Set(reflect.MakeSlice(t, len(list), len(list)))
+		for i, elemRaw := range list {
+			if err := decodeValue(elemRaw, v.Index(i), fmt.Sprintf("%s[%d]", path, i), field, point); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b, err := decodeHex(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			if len(b) != t.Len() {
+				return fmt.Errorf("%s: expected %d bytes, got %d", path, t.Len(), len(b))
+			}
+			reflect.Copy(v, reflect.ValueOf(b))
+			return nil
+		}
+		var list []json.RawMessage
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if len(list) != t.Len() {
+			return fmt.Errorf("%s: expected %d elements, got %d", path, t.Len(), len(list))
+		}
+		for i, elemRaw := range list {
+			if err := decodeValue(elemRaw, v.Index(i), fmt.Sprintf("%s[%d]", path, i), field, point); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(string(bytes.TrimSpace(raw)), 10, t.Bits())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(string(bytes.TrimSpace(raw)), 10, t.Bits())
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Bool, reflect.String:
+		if err := json.Unmarshal(raw, v.Addr().Interface()); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s: cannot unmarshal into %s", path, t)
+	}
+}
+
+func decodeHex(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	if s == "0x" {
+		return []byte{}, nil
+	}
+	return hexutil.Decode(s)
+}
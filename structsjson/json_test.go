@@ -0,0 +1,239 @@
+package structsjson_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	contractMockRollup "github.com/Layr-Labs/eigenda/contracts/bindings/MockRollup"
+	"github.com/Layr-Labs/eigenda/contracts/cert"
+	"github.com/Layr-Labs/eigenda/contracts/hashing"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/structsjson"
+	"github.com/Layr-Labs/eigenda/contracts/test/certs"
+)
+
+// roundTrip checks that v marshals and loads back into a deeply equal value, and returns its JSON.
+func roundTrip[T any](t *testing.T, v T) []byte {
+	t.Helper()
+	data, err := structsjson.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded T
+	if err := structsjson.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal(%s) = %v", data, err)
+	}
+	if !equal(reflect.ValueOf(loaded), reflect.ValueOf(v)) {
+		t.Errorf("%s loads back as %+v, want %+v", data, loaded, v)
+	}
+	return data
+}
+
+// equal is reflect.DeepEqual with integers compared by value, as big.Int values equal as numbers
+// can differ in their internal representation. Nil and empty slices still differ.
+func equal(a, b reflect.Value) bool {
+	if a.Type() == reflect.TypeOf((*big.Int)(nil)) {
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() && b.IsNil()
+		}
+		return a.Interface().(*big.Int).Cmp(b.Interface().(*big.Int)) == 0
+	}
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() && b.IsNil()
+		}
+		return equal(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !equal(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a.Interface() == b.Interface()
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	// empty slices load back empty, not nil
+	full := certs.V2()
+	full.BlobInclusionInfo.InclusionProof = []byte{}
+	full.NonSignerStakesAndSignature.NonSignerStakeIndices[1] = []uint32{}
+
+	t.Run("v2", func(t *testing.T) { roundTrip(t, *full) })
+	t.Run("pointer", func(t *testing.T) { roundTrip(t, full) })
+	t.Run("v1", func(t *testing.T) { roundTrip(t, certs.V1()) })
+	t.Run("zero values", func(t *testing.T) {
+		roundTrip(t, cert.CertV2{})
+		roundTrip(t, structs.BlobHeader{})
+		roundTrip(t, structs.BlobCertificate{})
+		roundTrip(t, structs.BatchMetadata{})
+	})
+	t.Run("partially filled", func(t *testing.T) {
+		partial := certs.V2()
+		partial.NonSignerStakesAndSignature.ApkG2 = structs.G2Point{X: [2]*big.Int{big.NewInt(1), nil}}
+		partial.BlobInclusionInfo.BlobCertificate.BlobHeader.Commitment.Commitment.Y = nil
+		partial.SignedQuorumNumbers = nil
+		roundTrip(t, partial)
+		roundTrip(t, structs.BlobHeader{Commitment: structs.G1Point{X: big.NewInt(1)}, DataLength: 2})
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := structsjson.Marshal(certs.V1().BlobVerificationProof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	metadata := fields["batchMetadata"].(map[string]any)
+	header := metadata["batchHeader"].(map[string]any)
+	batchHeaderHash, err := hashing.HashBatchHeaderToReducedBatchHeader(certs.V1().BlobVerificationProof.BatchMetadata.BatchHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	batchMetadataHash, err := hashing.HashBatchMetadata(certs.V1().BlobVerificationProof.BatchMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct{ got, want any }{
+		"quorum numbers":    {header["quorumNumbers"], []any{0.0, 1.0}},
+		"signed stake":      {header["signedStakeForQuorums"], []any{80.0, 90.0}},
+		"inclusion proof":   {fields["inclusionProof"], hexutil.Encode(certs.V1().BlobVerificationProof.InclusionProof)},
+		"batch header hash": {header["batchHeaderHash"], hexutil.Encode(batchHeaderHash[:])},
+		"metadata hash":     {metadata["batchMetadataHash"], hexutil.Encode(batchMetadataHash[:])},
+	} {
+		if !reflect.DeepEqual(tc.got, tc.want) {
+			t.Errorf("%s = %v, want %v", name, tc.got, tc.want)
+		}
+	}
+
+	data, err = structsjson.Marshal(certs.V2().BlobInclusionInfo.BlobCertificate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobKey, err := hashing.HashBlobHeaderV2(certs.V2().BlobInclusionInfo.BlobCertificate.BlobHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"x":"`+hexutil.Encode(certs.MaxUint256().Bytes())+`"`) ||
+		!strings.Contains(string(data), `"blobKey":"`+hexutil.Encode(blobKey[:])+`"`) {
+		t.Errorf("blob certificate marshals to %s", data)
+	}
+
+	// only the structs types gain derived hashes, not the copies in other bindings
+	data, err = structsjson.Marshal(structs.MustConvert[contractMockRollup.BlobHeader](certs.V1().BlobHeader))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "blobHeaderHash") {
+		t.Errorf("MockRollup blob header marshals with a derived hash: %s", data)
+	}
+
+	// structs with unset integers cannot be hashed, so they print without a derived hash
+	data, err = structsjson.Marshal(structs.BlobHeader{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"commitment":{"x":null,"y":null},"dataLength":0,"quorumBlobParams":null}`; string(data) != want {
+		t.Errorf("zero blob header marshals to %s, want %s", data, want)
+	}
+
+	wide := certs.V1().BlobHeader
+	wide.Commitment.X = new(big.Int).Lsh(big.NewInt(1), 256)
+	if _, err := structsjson.Marshal(wide); err == nil {
+		t.Error("Marshal of a coordinate wider than 32 bytes succeeded")
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	data, err := structsjson.Marshal(certs.V1().BlobHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := string(data)
+	hash, err := hashing.HashBlobHeader(certs.V1().BlobHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nullX := `"x":null`
+	x := `"x":"0x0000000000000000000000000000000000000000000000000000000000000001"`
+
+	for _, tc := range []struct {
+		name string
+		json string
+		want string
+	}{
+		{name: "derived hash does not match", json: strings.Replace(valid, `"dataLength":3`, `"dataLength":4`, 1), want: "does not match"},
+		{name: "null coordinate with a derived hash", json: strings.Replace(valid, x, nullX, 1), want: "unset integers"},
+		{name: "unknown field", json: strings.Replace(valid, `{"commitment"`, `{"extra":1,"commitment"`, 1), want: "unknown field"},
+		{name: "missing field", json: strings.Replace(valid, `"dataLength":3,`, "", 1), want: "missing field"},
+		{name: "short coordinate", json: strings.Replace(valid, x, `"x":"0x01"`, 1), want: "invalid integer"},
+		{name: "null point", json: strings.Replace(valid, `"commitment":{`+x, `"commitment":null,"ignored":{`+x, 1), want: "expected an object"},
+		{name: "not json", json: "{", want: "unexpected end"},
+	} {
+		var loaded structs.BlobHeader
+		err := structsjson.Unmarshal([]byte(tc.json), &loaded)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: Unmarshal = %v, want an error containing %q", tc.name, err, tc.want)
+		}
+	}
+
+	// without the derived hash, null coordinates load as nil
+	withoutHash := strings.Replace(valid, `,"blobHeaderHash":"`+hexutil.Encode(hash[:])+`"`, "", 1)
+	var loaded structs.BlobHeader
+	if err := structsjson.Unmarshal([]byte(strings.Replace(withoutHash, x, nullX, 1)), &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Commitment.X != nil || loaded.Commitment.Y.Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("loaded commitment %v, want a nil x", loaded.Commitment)
+	}
+
+	if err := structsjson.Unmarshal(data, loaded); err == nil {
+		t.Error("Unmarshal into a non-pointer succeeded")
+	}
+}
+
+// panicText panics when marshaled as text.
+type panicText struct{}
+
+func (panicText) MarshalText() ([]byte, error) {
+	panic("cannot marshal")
+}
+
+func TestSprint(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		v    any
+		want string
+	}{
+		{name: "blob header", v: certs.V1().BlobHeader, want: `"blobHeaderHash": "0x`},
+		{name: "nil coordinates", v: structs.BlobHeader{}, want: `"x": null`},
+		{name: "nil", v: nil, want: "null"},
+		{name: "unsupported", v: make(chan int), want: "cannot marshal chan int"},
+		{name: "panic", v: panicText{}, want: "panic: cannot marshal"},
+	} {
+		if got := structsjson.Sprint(tc.v); !strings.Contains(got, tc.want) {
+			t.Errorf("%s: Sprint = %s, want it to contain %q", tc.name, got, tc.want)
+		}
+	}
+}
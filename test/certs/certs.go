@@ -0,0 +1,94 @@
+// Package certs holds the EigenDA cert fixtures shared by the tests of the packages that encode,
+// print and load certs. Every call returns a fresh cert, so tests may modify what they get.
+package certs
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/Layr-Labs/eigenda/contracts/cert"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// G1Point returns the G1 point (x, y). It need not be on the curve.
+func G1Point(x, y int64) structs.G1Point {
+	return structs.G1Point{X: big.NewInt(x), Y: big.NewInt(y)}
+}
+
+// G2Point returns the G2 point ((x0, x1), (y0, y1)). It need not be on the curve.
+func G2Point(x0, x1, y0, y1 int64) structs.G2Point {
+	return structs.G2Point{X: [2]*big.Int{big.NewInt(x0), big.NewInt(x1)}, Y: [2]*big.Int{big.NewInt(y0), big.NewInt(y1)}}
+}
+
+// MaxUint256 returns 2^256 - 1, the widest integer a cert holds.
+func MaxUint256() *big.Int {
+	return new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+}
+
+// V1 returns a V1 cert with every field set, blobbing two quorums.
+func V1() *cert.CertV1 {
+	return &cert.CertV1{
+		BlobHeader: structs.BlobHeader{
+			Commitment: G1Point(1, 2),
+			DataLength: 3,
+			QuorumBlobParams: []structs.QuorumBlobParam{
+				{QuorumNumber: 0, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55, ChunkLength: 4},
+				{QuorumNumber: 1, AdversaryThresholdPercentage: 33, ConfirmationThresholdPercentage: 55, ChunkLength: 5},
+			},
+		},
+		BlobVerificationProof: structs.BlobVerificationProof{
+			BatchId:   6,
+			BlobIndex: 7,
+			BatchMetadata: structs.BatchMetadata{
+				BatchHeader: structs.BatchHeader{
+					BlobHeadersRoot:       [32]byte{8},
+					QuorumNumbers:         []byte{0, 1},
+					SignedStakeForQuorums: []byte{80, 90},
+					ReferenceBlockNumber:  9,
+				},
+				SignatoryRecordHash:     [32]byte{10},
+				ConfirmationBlockNumber: 11,
+			},
+			InclusionProof: bytes.Repeat([]byte{12}, 64),
+			QuorumIndices:  []byte{0, 1},
+		},
+	}
+}
+
+// V2 returns a V2 cert with every field set. The x coordinate of its blob commitment is
+// MaxUint256, so encodings that truncate wide integers do not round trip it.
+func V2() *cert.CertV2 {
+	return &cert.CertV2{
+		BatchHeader: structs.BatchHeaderV2{BatchRoot: [32]byte{1}, ReferenceBlockNumber: 2},
+		BlobInclusionInfo: structs.BlobInclusionInfo{
+			BlobCertificate: structs.BlobCertificate{
+				BlobHeader: structs.BlobHeaderV2{
+					Version:       3,
+					QuorumNumbers: []byte{0, 1},
+					Commitment: structs.BlobCommitment{
+						Commitment:       structs.G1Point{X: MaxUint256(), Y: big.NewInt(4)},
+						LengthCommitment: G2Point(5, 6, 7, 8),
+						LengthProof:      G2Point(9, 10, 11, 12),
+						Length:           16,
+					},
+					PaymentHeaderHash: [32]byte{13},
+				},
+				Signature: bytes.Repeat([]byte{14}, 65),
+				RelayKeys: []uint32{15, 16},
+			},
+			BlobIndex:      17,
+			InclusionProof: bytes.Repeat([]byte{18}, 32),
+		},
+		NonSignerStakesAndSignature: structs.NonSignerStakesAndSignature{
+			NonSignerQuorumBitmapIndices: []uint32{19},
+			NonSignerPubkeys:             []structs.G1Point{G1Point(20, 21)},
+			QuorumApks:                   []structs.G1Point{G1Point(22, 23), G1Point(24, 25)},
+			ApkG2:                        G2Point(26, 27, 28, 29),
+			Sigma:                        G1Point(30, 31),
+			QuorumApkIndices:             []uint32{32, 33},
+			TotalStakeIndices:            []uint32{34, 35},
+			NonSignerStakeIndices:        [][]uint32{{36}, {37}},
+		},
+		SignedQuorumNumbers: []byte{0, 1},
+	}
+}
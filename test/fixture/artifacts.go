@@ -0,0 +1,115 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrArtifactsNotBuilt is returned by New when the forge artifacts the fixture deploys from are
+// missing. Run forge build at the repository root first.
+var ErrArtifactsNotBuilt = errors.New("forge artifacts not built")
+
+// Contracts the fixture deploys from their forge artifacts because they have no binding: the
+// OpenZeppelin proxies, the EigenLayer mocks MockAVSDeployer stands the middleware up with, and the
+// library EigenDACertVerifier links against. forge build compiles all of them for
+// test/MockEigenDADeployer.sol.
+const (
+	transparentUpgradeableProxy  = "TransparentUpgradeableProxy"
+	proxyAdmin                   = "ProxyAdmin"
+	emptyContract                = "EmptyContract"
+	pauserRegistry               = "PauserRegistry"
+	delegationMock               = "DelegationMock"
+	avsDirectoryMock             = "AVSDirectoryMock"
+	rewardsCoordinatorMock       = "RewardsCoordinatorMock"
+	eigenDACertVerificationUtils = "EigenDACertVerificationUtils"
+)
+
+// artifact is the part of a forge build artifact the fixture deploys from.
+type artifact struct {
+	ABI      abi.ABI
+	Bytecode string
+}
+
+// defaultArtifactsDir is the forge output directory at the root of the repository.
+func defaultArtifactsDir() string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return "out"
+	}
+	return filepath.Join(filepath.Dir(file), "..", "..", "out")
+}
+
+// artifact returns the artifact of contract name, read from <ArtifactsDir>/<name>.sol/<name>.json.
+func (f *Fixture) artifact(name string) (*artifact, error) {
+	if a, ok := f.artifacts[name]; ok {
+		return a, nil
+	}
+
+	path := filepath.Join(f.artifactsDir, name+".sol", name+".json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: no artifact %s", ErrArtifactsNotBuilt, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		ABI      json.RawMessage `json:"abi"`
+		Bytecode struct {
+			Object string `json:"object"`
+		} `json:"bytecode"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing artifact %s: %w", path, err)
+	}
+	parsed, err := abi.JSON(bytes.NewReader(raw.ABI))
+	if err != nil {
+		return nil, fmt.Errorf("parsing abi of artifact %s: %w", path, err)
+	}
+
+	a := &artifact{ABI: parsed, Bytecode: raw.Bytecode.Object}
+	f.artifacts[name] = a
+	return a, nil
+}
+
+// deployArtifact deploys contract name from its artifact with constructor params, sent by account.
+func (f *Fixture) deployArtifact(name string, account *Account, params ...interface{}) (common.Address, *bind.BoundContract, error) {
+	a, err := f.artifact(name)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return f.deployLinked(name, account, a.ABI, a.Bytecode, params...)
+}
+
+// deployLinked deploys creation code given as hex, which must have every library placeholder linked.
+func (f *Fixture) deployLinked(name string, account *Account, parsed abi.ABI, bytecode string, params ...interface{}) (common.Address, *bind.BoundContract, error) {
+	code, err := hexutil.Decode(bytecode)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("bytecode of %s: %w", name, err)
+	}
+	address, tx, contract, err := bind.DeployContract(f.TransactOpts(account), parsed, code, f.Client, params...)
+	if err := f.confirm("deploying "+name, tx, err); err != nil {
+		return common.Address{}, nil, err
+	}
+	return address, contract, nil
+}
+
+// bindArtifact binds contract name at address.
+func (f *Fixture) bindArtifact(name string, address common.Address) (*bind.BoundContract, error) {
+	a, err := f.artifact(name)
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, a.ABI, f.Client, f.Client, f.Client), nil
+}
@@ -0,0 +1,117 @@
+package fixture
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	contractEigenDAServiceManager "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	contractMockRollup "github.com/Layr-Labs/eigenda/contracts/bindings/MockRollup"
+	"github.com/Layr-Labs/eigenda/contracts/bls"
+	"github.com/Layr-Labs/eigenda/contracts/hashing"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/verification"
+)
+
+// ConfirmBatch confirms batchHeader on the ServiceManager with a signature of every operator, and
+// returns the batch id and the metadata whose hash it stored. Every operator is registered in every
+// quorum, so the aggregate key of the signature is the sum of the operator keys once per quorum of
+// batchHeader. Its reference block must be a past block.
+func (f *Fixture) ConfirmBatch(batchHeader structs.BatchHeader) (uint32, structs.BatchMetadata, error) {
+	ctx := context.Background()
+	opts := &bind.CallOpts{Context: ctx}
+
+	batchID, err := f.ServiceManager.BatchId(opts)
+	if err != nil {
+		return 0, structs.BatchMetadata{}, err
+	}
+	msgHash, err := hashing.HashBatchHeaderToReducedBatchHeader(batchHeader)
+	if err != nil {
+		return 0, structs.BatchMetadata{}, err
+	}
+
+	var privateKey, operatorKey fr.Element
+	for _, operator := range f.Operators {
+		operatorKey.SetBigInt(operator.BLSPrivateKey)
+		privateKey.Add(&privateKey, &operatorKey)
+	}
+	var numQuorums fr.Element
+	numQuorums.SetUint64(uint64(len(batchHeader.QuorumNumbers)))
+	privateKey.Mul(&privateKey, &numQuorums)
+	aggregateKey := privateKey.BigInt(new(big.Int))
+
+	_, _, _, g2Gen := bn254.Generators()
+	var apkG2 bn254.G2Affine
+	apkG2.ScalarMultiplication(&g2Gen, aggregateKey)
+
+	indices, err := f.OperatorStateRetriever.GetCheckSignaturesIndices(
+		opts,
+		f.Addresses.RegistryCoordinator,
+		batchHeader.ReferenceBlockNumber,
+		batchHeader.QuorumNumbers,
+		[][32]byte{},
+	)
+	if err != nil {
+		return 0, structs.BatchMetadata{}, fmt.Errorf("fetching check signatures indices: %w", err)
+	}
+	quorumApks := make([]structs.G1Point, len(batchHeader.QuorumNumbers))
+	for i, quorumNumber := range batchHeader.QuorumNumbers {
+		apk, err := f.BLSApkRegistry.GetApk(opts, quorumNumber)
+		if err != nil {
+			return 0, structs.BatchMetadata{}, err
+		}
+		quorumApks[i] = structs.MustConvert[structs.G1Point](apk)
+	}
+	params := structs.NonSignerStakesAndSignature{
+		NonSignerQuorumBitmapIndices: indices.NonSignerQuorumBitmapIndices,
+		NonSignerPubkeys:             []structs.G1Point{},
+		QuorumApks:                   quorumApks,
+		ApkG2:                        bls.G2Point(&apkG2),
+		Sigma:                        bls.Sign(aggregateKey, msgHash),
+		QuorumApkIndices:             indices.QuorumApkIndices,
+		TotalStakeIndices:            indices.TotalStakeIndices,
+		NonSignerStakeIndices:        indices.NonSignerStakeIndices,
+	}
+
+	transactOpts := f.TransactOpts(f.Confirmer)
+	// Gas estimation leaves too little headroom for the pairing precompile call of checkSignatures.
+	transactOpts.GasLimit = 5_000_000
+	tx, err := f.ServiceManager.ConfirmBatch(
+		transactOpts,
+		structs.MustConvert[contractEigenDAServiceManager.BatchHeader](batchHeader),
+		structs.MustConvert[contractEigenDAServiceManager.IBLSSignatureCheckerNonSignerStakesAndSignature](params),
+	)
+	if err := f.confirm(fmt.Sprintf("confirming batch %d", batchID), tx, err); err != nil {
+		return 0, structs.BatchMetadata{}, err
+	}
+	receipt, err := f.Client.TransactionReceipt(ctx, tx.Hash())
+	if err != nil {
+		return 0, structs.BatchMetadata{}, err
+	}
+
+	return batchID, structs.BatchMetadata{
+		BatchHeader:             batchHeader,
+		SignatoryRecordHash:     verification.SignatoryRecordHash(batchHeader.ReferenceBlockNumber, nil),
+		ConfirmationBlockNumber: uint32(receipt.BlockNumber.Uint64()),
+	}, nil
+}
+
+// DeployMockRollup deploys test/rollupV1/MockRollup against the ServiceManager, checking openings
+// with tau.
+func (f *Fixture) DeployMockRollup(tau structs.G1Point) (common.Address, *contractMockRollup.ContractMockRollup, error) {
+	address, tx, rollup, err := contractMockRollup.DeployContractMockRollup(
+		f.TransactOpts(f.Owner),
+		f.Client,
+		f.Addresses.ServiceManager,
+		structs.MustConvert[contractMockRollup.BN254G1Point](tau),
+	)
+	if err := f.confirm("deploying MockRollup", tx, err); err != nil {
+		return common.Address{}, nil, err
+	}
+	return address, rollup, nil
+}
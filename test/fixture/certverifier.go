@@ -0,0 +1,51 @@
+package fixture
+
+import (
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	contractEigenDACertVerifier "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDACertVerifier"
+)
+
+// certVerificationUtils is the fully qualified name of the library EigenDACertVerifier links against.
+const certVerificationUtils = "src/libraries/EigenDACertVerificationUtils.sol:EigenDACertVerificationUtils"
+
+// deployCertVerifier deploys EigenDACertVerificationUtils from its forge artifact and an
+// EigenDACertVerifier from its binding, linked against the library.
+func (f *Fixture) deployCertVerifier() error {
+	library, _, err := f.deployArtifact(eigenDACertVerificationUtils, f.Owner)
+	if err != nil {
+		return err
+	}
+
+	// solc leaves __$<first 17 bytes of keccak256(name)>$__ wherever the library address goes.
+	placeholder := "__$" + crypto.Keccak256Hash([]byte(certVerificationUtils)).Hex()[2:36] + "$__"
+	bin := strings.ReplaceAll(contractEigenDACertVerifier.ContractEigenDACertVerifierMetaData.Bin, placeholder,
+		strings.ToLower(library.Hex()[2:]))
+
+	parsed, err := contractEigenDACertVerifier.ContractEigenDACertVerifierMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+	address, _, err := f.deployLinked(
+		"EigenDACertVerifier",
+		f.Owner,
+		*parsed,
+		bin,
+		f.Addresses.ThresholdRegistry,
+		f.Addresses.ServiceManager,
+		f.Addresses.ServiceManager,
+		f.Addresses.RelayRegistry,
+		f.Addresses.OperatorStateRetriever,
+		f.Addresses.RegistryCoordinator,
+		DefaultSecurityThresholds,
+		QuorumNumbersRequired,
+	)
+	if err != nil {
+		return err
+	}
+	f.Addresses.CertVerifier = address
+	f.CertVerifier, err = contractEigenDACertVerifier.NewContractEigenDACertVerifier(address, f.Client)
+	return err
+}
@@ -0,0 +1,649 @@
+// Package fixture stands up the EigenDA contracts on go-ethereum's simulated backend for Go
+// integration tests, the way test/MockEigenDADeployer.sol does for forge tests.
+//
+// The EigenDA and middleware registries are deployed from their bindings behind OpenZeppelin
+// TransparentUpgradeableProxy contracts and initialized with the same parameters as
+// MockEigenDADeployer. The proxies and the EigenLayer mocks the registries call into
+// (DelegationMock, AVSDirectoryMock, RewardsCoordinatorMock, PauserRegistry) have no bindings and
+// are deployed from the forge artifacts, so forge build must have run first. The operators are
+// registered with fresh BLS keys.
+package fixture
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+
+	contractIndexRegistry "github.com/Layr-Labs/eigensdk-go/contracts/bindings/IndexRegistry"
+
+	contractBLSApkRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/BLSApkRegistry"
+	contractEigenDACertVerifier "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDACertVerifier"
+	contractEigenDADisperserRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDADisperserRegistry"
+	contractEigenDARelayRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDARelayRegistry"
+	contractEigenDAServiceManager "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAServiceManager"
+	contractEigenDAThresholdRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDAThresholdRegistry"
+	contractOperatorStateRetriever "github.com/Layr-Labs/eigenda/contracts/bindings/OperatorStateRetriever"
+	contractPaymentVault "github.com/Layr-Labs/eigenda/contracts/bindings/PaymentVault"
+	contractRegistryCoordinator "github.com/Layr-Labs/eigenda/contracts/bindings/RegistryCoordinator"
+	contractSocketRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/SocketRegistry"
+	contractStakeRegistry "github.com/Layr-Labs/eigenda/contracts/bindings/StakeRegistry"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// blockGasLimit leaves room for the largest contracts, whose deployments cost over 5M gas each.
+const blockGasLimit = 60_000_000
+
+// Config describes the deployment. The zero value of each field falls back to DefaultConfig.
+type Config struct {
+	// NumOperators is the number of operators registered in every quorum.
+	NumOperators int
+	// NumQuorums is the number of quorums created in the RegistryCoordinator, numbered from 0.
+	NumQuorums uint8
+	// OperatorShares is the delegated shares every operator is given in the DelegationMock for the
+	// single strategy of each quorum, which with a multiplier of 1e18 is also its stake.
+	OperatorShares *big.Int
+
+	// ArtifactsDir is the forge output directory, by default out at the root of the repository.
+	ArtifactsDir string
+}
+
+// DefaultConfig mirrors the setup of MockEigenDADeployer.
+func DefaultConfig() Config {
+	return Config{
+		NumOperators:   4,
+		NumQuorums:     2,
+		OperatorShares: big.NewInt(params.Ether),
+		ArtifactsDir:   defaultArtifactsDir(),
+	}
+}
+
+// Parameters MockEigenDADeployer initializes the EigenDA contracts with.
+var (
+	QuorumAdversaryThresholdPercentages    = []byte{0x21, 0x21, 0x21}
+	QuorumConfirmationThresholdPercentages = []byte{0x37, 0x37, 0x37}
+	QuorumNumbersRequired                  = []byte{0x00, 0x01}
+	DefaultSecurityThresholds              = structs.SecurityThresholds{ConfirmationThreshold: 55, AdversaryThreshold: 33}
+	DefaultVersionedBlobParams             = structs.VersionedBlobParams{MaxNumOperators: 3537, NumChunks: 8192, CodingRate: 8}
+
+	MinNumSymbols             uint64 = 1
+	PricePerSymbol            uint64 = 3
+	PriceUpdateCooldown       uint64 = 6 * 24 * 60 * 60
+	GlobalSymbolsPerPeriod    uint64 = 2
+	ReservationPeriodInterval uint64 = 4
+	GlobalRatePeriodInterval  uint64 = 5
+)
+
+// Account is a funded externally owned account on the simulated chain.
+type Account struct {
+	Key     *ecdsa.PrivateKey
+	Address common.Address
+}
+
+// Addresses holds the address every handle of a Fixture is bound to. Proxied contracts are
+// listed by proxy address.
+type Addresses struct {
+	ServiceManager         common.Address
+	ThresholdRegistry      common.Address
+	RelayRegistry          common.Address
+	DisperserRegistry      common.Address
+	PaymentVault           common.Address
+	RegistryCoordinator    common.Address
+	StakeRegistry          common.Address
+	BLSApkRegistry         common.Address
+	IndexRegistry          common.Address
+	SocketRegistry         common.Address
+	OperatorStateRetriever common.Address
+	CertVerifier           common.Address
+
+	ProxyAdmin         common.Address
+	EmptyContract      common.Address
+	DelegationManager  common.Address
+	AVSDirectory       common.Address
+	RewardsCoordinator common.Address
+	PauserRegistry     common.Address
+}
+
+// Fixture is a deployed EigenDA stack on a simulated chain.
+type Fixture struct {
+	Backend *simulated.Backend
+	Client  simulated.Client
+	ChainID *big.Int
+
+	// Owner owns every contract, ProxyAdminOwner owns the ProxyAdmin of the proxies and Confirmer
+	// is the only batch confirmer of the ServiceManager.
+	Owner           *Account
+	ProxyAdminOwner *Account
+	Confirmer       *Account
+	Operators       []*Operator
+
+	Addresses Addresses
+
+	// WithdrawalDelayBlocks is what DelegationMock.minWithdrawalDelayBlocks reports, bounding how
+	// old a reference block may be while stale stakes are forbidden.
+	WithdrawalDelayBlocks uint32
+
+	ServiceManager         *contractEigenDAServiceManager.ContractEigenDAServiceManager
+	ThresholdRegistry      *contractEigenDAThresholdRegistry.ContractEigenDAThresholdRegistry
+	RelayRegistry          *contractEigenDARelayRegistry.ContractEigenDARelayRegistry
+	DisperserRegistry      *contractEigenDADisperserRegistry.ContractEigenDADisperserRegistry
+	PaymentVault           *contractPaymentVault.ContractPaymentVault
+	RegistryCoordinator    *contractRegistryCoordinator.ContractRegistryCoordinator
+	StakeRegistry          *contractStakeRegistry.ContractStakeRegistry
+	BLSApkRegistry         *contractBLSApkRegistry.ContractBLSApkRegistry
+	IndexRegistry          *contractIndexRegistry.ContractIndexRegistry
+	SocketRegistry         *contractSocketRegistry.ContractSocketRegistry
+	OperatorStateRetriever *contractOperatorStateRetriever.ContractOperatorStateRetriever
+	CertVerifier           *contractEigenDACertVerifier.ContractEigenDACertVerifier
+
+	proxyAdmin *bind.BoundContract
+	delegation *bind.BoundContract
+
+	artifactsDir string
+	artifacts    map[string]*artifact
+}
+
+// New starts a simulated chain and deploys the EigenDA stack on it. It returns an error wrapping
+// ErrArtifactsNotBuilt if the forge artifacts are missing. Callers must Close the fixture.
+func New(config Config) (*Fixture, error) {
+	config = withDefaults(config)
+
+	f := &Fixture{
+		Owner:           newAccount("owner"),
+		ProxyAdminOwner: newAccount("proxyAdmin"),
+		Confirmer:       newAccount("confirmer"),
+		artifactsDir:    config.ArtifactsDir,
+		artifacts:       make(map[string]*artifact),
+	}
+	for i := 0; i < config.NumOperators; i++ {
+		operator, err := newOperator(i)
+		if err != nil {
+			return nil, err
+		}
+		f.Operators = append(f.Operators, operator)
+	}
+
+	alloc := make(types.GenesisAlloc)
+	balance := new(big.Int).Mul(big.NewInt(1_000_000), big.NewInt(params.Ether))
+	for _, account := range f.accounts() {
+		alloc[account.Address] = types.Account{Balance: balance}
+	}
+	f.Backend = simulated.NewBackend(alloc, simulated.WithBlockGasLimit(blockGasLimit))
+	f.Client = f.Backend.Client()
+
+	err := f.setup(config)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// NewForTest is New for tests. It skips t when the forge artifacts are missing, fails it on any
+// other error, and closes the fixture when t finishes.
+func NewForTest(t testing.TB, config Config) *Fixture {
+	t.Helper()
+	f, err := New(config)
+	if errors.Is(err, ErrArtifactsNotBuilt) {
+		t.Skipf("%v; run forge build", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+func withDefaults(config Config) Config {
+	defaults := DefaultConfig()
+	if config.NumOperators == 0 {
+		config.NumOperators = defaults.NumOperators
+	}
+	if config.NumQuorums == 0 {
+		config.NumQuorums = defaults.NumQuorums
+	}
+	if config.OperatorShares == nil {
+		config.OperatorShares = defaults.OperatorShares
+	}
+	if config.ArtifactsDir == "" {
+		config.ArtifactsDir = defaults.ArtifactsDir
+	}
+	return config
+}
+
+func (f *Fixture) setup(config Config) error {
+	chainID, err := f.Client.ChainID(context.Background())
+	if err != nil {
+		return err
+	}
+	f.ChainID = chainID
+
+	if err := f.deployEigenLayer(); err != nil {
+		return err
+	}
+	if err := f.deployMiddleware(config); err != nil {
+		return err
+	}
+	if err := f.deployEigenDA(); err != nil {
+		return err
+	}
+	if err := f.deployCertVerifier(); err != nil {
+		return err
+	}
+	if err := f.setOperatorShares(config); err != nil {
+		return err
+	}
+	for _, operator := range f.Operators {
+		if err := f.registerOperator(operator, quorumNumbers(config.NumQuorums)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close shuts the simulated chain down.
+func (f *Fixture) Close() error {
+	return f.Backend.Close()
+}
+
+// TransactOpts returns transaction options that sign with account.
+func (f *Fixture) TransactOpts(account *Account) *bind.TransactOpts {
+	opts, err := bind.NewKeyedTransactorWithChainID(account.Key, f.ChainID)
+	if err != nil {
+		// only fails for a nil chain ID
+		panic(err)
+	}
+	return opts
+}
+
+// Mine commits the pending transactions into a new block and fails if tx did not succeed.
+func (f *Fixture) Mine(tx *types.Transaction) error {
+	f.Backend.Commit()
+	receipt, err := f.Client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s reverted", tx.Hash())
+	}
+	return nil
+}
+
+// confirm mines tx, returning the error of the call that sent it if there was one.
+func (f *Fixture) confirm(what string, tx *types.Transaction, err error) error {
+	if err == nil {
+		err = f.Mine(tx)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	return nil
+}
+
+func (f *Fixture) accounts() []*Account {
+	accounts := []*Account{f.Owner, f.ProxyAdminOwner, f.Confirmer}
+	for _, operator := range f.Operators {
+		accounts = append(accounts, &operator.Account)
+	}
+	return accounts
+}
+
+// deployEigenLayer deploys the ProxyAdmin and the EigenLayer mocks, as MockAVSDeployer does.
+func (f *Fixture) deployEigenLayer() error {
+	var err error
+	if f.Addresses.ProxyAdmin, f.proxyAdmin, err = f.deployArtifact(proxyAdmin, f.ProxyAdminOwner); err != nil {
+		return err
+	}
+	if f.Addresses.EmptyContract, _, err = f.deployArtifact(emptyContract, f.Owner); err != nil {
+		return err
+	}
+	if f.Addresses.PauserRegistry, _, err = f.deployArtifact(
+		pauserRegistry, f.Owner, []common.Address{f.Owner.Address}, f.Owner.Address); err != nil {
+		return err
+	}
+	if f.Addresses.DelegationManager, f.delegation, err = f.deployArtifact(delegationMock, f.Owner); err != nil {
+		return err
+	}
+	if f.Addresses.AVSDirectory, _, err = f.deployArtifact(avsDirectoryMock, f.Owner); err != nil {
+		return err
+	}
+	if f.Addresses.RewardsCoordinator, _, err = f.deployArtifact(rewardsCoordinatorMock, f.Owner); err != nil {
+		return err
+	}
+
+	var delay []interface{}
+	if err := f.delegation.Call(&bind.CallOpts{Context: context.Background()}, &delay, "minWithdrawalDelayBlocks"); err != nil {
+		return fmt.Errorf("reading minWithdrawalDelayBlocks: %w", err)
+	}
+	f.WithdrawalDelayBlocks = uint32(delay[0].(*big.Int).Uint64())
+	return nil
+}
+
+// deployMiddleware deploys and initializes the registries of eigenlayer-middleware.
+func (f *Fixture) deployMiddleware(config Config) error {
+	var err error
+	for _, proxy := range []struct {
+		name    string
+		address *common.Address
+	}{
+		{"RegistryCoordinator", &f.Addresses.RegistryCoordinator},
+		{"StakeRegistry", &f.Addresses.StakeRegistry},
+		{"BLSApkRegistry", &f.Addresses.BLSApkRegistry},
+		{"IndexRegistry", &f.Addresses.IndexRegistry},
+	} {
+		if *proxy.address, err = f.deployProxy(proxy.name); err != nil {
+			return err
+		}
+	}
+
+	owner := f.TransactOpts(f.Owner)
+
+	implementation, tx, _, err := contractStakeRegistry.DeployContractStakeRegistry(
+		owner, f.Client, f.Addresses.RegistryCoordinator, f.Addresses.DelegationManager)
+	if err := f.confirm("deploying StakeRegistry", tx, err); err != nil {
+		return err
+	}
+	if err := f.upgradeProxy(f.Addresses.StakeRegistry, implementation); err != nil {
+		return err
+	}
+
+	implementation, tx, _, err = contractBLSApkRegistry.DeployContractBLSApkRegistry(
+		owner, f.Client, f.Addresses.RegistryCoordinator)
+	if err := f.confirm("deploying BLSApkRegistry", tx, err); err != nil {
+		return err
+	}
+	if err := f.upgradeProxy(f.Addresses.BLSApkRegistry, implementation); err != nil {
+		return err
+	}
+
+	implementation, tx, _, err = contractIndexRegistry.DeployContractIndexRegistry(
+		owner, f.Client, f.Addresses.RegistryCoordinator)
+	if err := f.confirm("deploying IndexRegistry", tx, err); err != nil {
+		return err
+	}
+	if err := f.upgradeProxy(f.Addresses.IndexRegistry, implementation); err != nil {
+		return err
+	}
+
+	f.Addresses.SocketRegistry, tx, f.SocketRegistry, err = contractSocketRegistry.DeployContractSocketRegistry(
+		owner, f.Client, f.Addresses.RegistryCoordinator)
+	if err := f.confirm("deploying SocketRegistry", tx, err); err != nil {
+		return err
+	}
+
+	// The ServiceManager proxy is deployed now because the RegistryCoordinator takes it as an immutable.
+	if f.Addresses.ServiceManager, err = f.deployProxy("EigenDAServiceManager"); err != nil {
+		return err
+	}
+	implementation, tx, _, err = contractRegistryCoordinator.DeployContractRegistryCoordinator(
+		owner,
+		f.Client,
+		f.Addresses.ServiceManager,
+		f.Addresses.StakeRegistry,
+		f.Addresses.BLSApkRegistry,
+		f.Addresses.IndexRegistry,
+		f.Addresses.SocketRegistry,
+	)
+	if err := f.confirm("deploying RegistryCoordinator", tx, err); err != nil {
+		return err
+	}
+	if err := f.upgradeProxy(f.Addresses.RegistryCoordinator, implementation); err != nil {
+		return err
+	}
+
+	if f.RegistryCoordinator, err = contractRegistryCoordinator.NewContractRegistryCoordinator(
+		f.Addresses.RegistryCoordinator, f.Client); err != nil {
+		return err
+	}
+	if f.StakeRegistry, err = contractStakeRegistry.NewContractStakeRegistry(f.Addresses.StakeRegistry, f.Client); err != nil {
+		return err
+	}
+	if f.BLSApkRegistry, err = contractBLSApkRegistry.NewContractBLSApkRegistry(f.Addresses.BLSApkRegistry, f.Client); err != nil {
+		return err
+	}
+	if f.IndexRegistry, err = contractIndexRegistry.NewContractIndexRegistry(f.Addresses.IndexRegistry, f.Client); err != nil {
+		return err
+	}
+
+	var (
+		operatorSetParams = make([]contractRegistryCoordinator.IRegistryCoordinatorOperatorSetParam, config.NumQuorums)
+		minimumStakes     = make([]*big.Int, config.NumQuorums)
+		strategyParams    = make([][]contractRegistryCoordinator.IStakeRegistryStrategyParams, config.NumQuorums)
+	)
+	for i := range operatorSetParams {
+		operatorSetParams[i] = contractRegistryCoordinator.IRegistryCoordinatorOperatorSetParam{
+			MaxOperatorCount:        uint32(config.NumOperators),
+			KickBIPsOfOperatorStake: 11000,
+			KickBIPsOfTotalStake:    1001,
+		}
+		minimumStakes[i] = big.NewInt(0)
+		strategyParams[i] = []contractRegistryCoordinator.IStakeRegistryStrategyParams{{
+			Strategy:   strategy(i),
+			Multiplier: big.NewInt(params.Ether),
+		}}
+	}
+	tx, err = f.RegistryCoordinator.Initialize(
+		owner,
+		f.Owner.Address,
+		f.Owner.Address,
+		f.Owner.Address,
+		f.Addresses.PauserRegistry,
+		big.NewInt(0),
+		operatorSetParams,
+		minimumStakes,
+		strategyParams,
+	)
+	if err := f.confirm("initializing RegistryCoordinator", tx, err); err != nil {
+		return err
+	}
+
+	f.Addresses.OperatorStateRetriever, tx, f.OperatorStateRetriever, err =
+		contractOperatorStateRetriever.DeployContractOperatorStateRetriever(owner, f.Client)
+	return f.confirm("deploying OperatorStateRetriever", tx, err)
+}
+
+// setOperatorShares gives every operator config.OperatorShares of the strategy of each quorum in
+// the DelegationMock, which the StakeRegistry reads the operator stakes from.
+func (f *Fixture) setOperatorShares(config Config) error {
+	for _, operator := range f.Operators {
+		for quorum := 0; quorum < int(config.NumQuorums); quorum++ {
+			tx, err := f.delegation.Transact(
+				f.TransactOpts(f.Owner), "setOperatorShares", operator.Address, strategy(quorum), config.OperatorShares)
+			if err := f.confirm(fmt.Sprintf("setting shares of operator %s", operator.Address), tx, err); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deployEigenDA deploys and initializes the EigenDA contracts as MockEigenDADeployer._deployDA does.
+func (f *Fixture) deployEigenDA() error {
+	var err error
+	for _, proxy := range []struct {
+		name    string
+		address *common.Address
+	}{
+		{"EigenDAThresholdRegistry", &f.Addresses.ThresholdRegistry},
+		{"EigenDARelayRegistry", &f.Addresses.RelayRegistry},
+		{"EigenDADisperserRegistry", &f.Addresses.DisperserRegistry},
+		{"PaymentVault", &f.Addresses.PaymentVault},
+	} {
+		if *proxy.address, err = f.deployProxy(proxy.name); err != nil {
+			return err
+		}
+	}
+
+	owner := f.TransactOpts(f.Owner)
+
+	implementation, tx, _, err := contractEigenDAThresholdRegistry.DeployContractEigenDAThresholdRegistry(owner, f.Client)
+	if err := f.confirm("deploying EigenDAThresholdRegistry", tx, err); err != nil {
+		return err
+	}
+	if err := f.upgradeProxy(f.Addresses.ThresholdRegistry, implementation); err != nil {
+		return err
+	}
+	if f.ThresholdRegistry, err = contractEigenDAThresholdRegistry.NewContractEigenDAThresholdRegistry(
+		f.Addresses.ThresholdRegistry, f.Client); err != nil {
+		return err
+	}
+	tx, err = f.ThresholdRegistry.Initialize(
+		owner,
+		f.Owner.Address,
+		QuorumAdversaryThresholdPercentages,
+		QuorumConfirmationThresholdPercentages,
+		QuorumNumbersRequired,
+		[]contractEigenDAThresholdRegistry.VersionedBlobParams{
+			structs.MustConvert[contractEigenDAThresholdRegistry.VersionedBlobParams](DefaultVersionedBlobParams),
+		},
+	)
+	if err := f.confirm("initializing EigenDAThresholdRegistry", tx, err); err != nil {
+		return err
+	}
+
+	implementation, tx, _, err = contractEigenDARelayRegistry.DeployContractEigenDARelayRegistry(owner, f.Client)
+	if err := f.confirm("deploying EigenDARelayRegistry", tx, err); err != nil {
+		return err
+	}
+	if err := f.upgradeProxy(f.Addresses.RelayRegistry, implementation); err != nil {
+		return err
+	}
+	if f.RelayRegistry, err = contractEigenDARelayRegistry.NewContractEigenDARelayRegistry(
+		f.Addresses.RelayRegistry, f.Client); err != nil {
+		return err
+	}
+	tx, err = f.RelayRegistry.Initialize(owner, f.Owner.Address)
+	if err := f.confirm("initializing EigenDARelayRegistry", tx, err); err != nil {
+		return err
+	}
+
+	implementation, tx, _, err = contractEigenDADisperserRegistry.DeployContractEigenDADisperserRegistry(owner, f.Client)
+	if err := f.confirm("deploying EigenDADisperserRegistry", tx, err); err != nil {
+		return err
+	}
+	if err := f.upgradeProxy(f.Addresses.DisperserRegistry, implementation); err != nil {
+		return err
+	}
+	if f.DisperserRegistry, err = contractEigenDADisperserRegistry.NewContractEigenDADisperserRegistry(
+		f.Addresses.DisperserRegistry, f.Client); err != nil {
+		return err
+	}
+	tx, err = f.DisperserRegistry.Initialize(owner, f.Owner.Address)
+	if err := f.confirm("initializing EigenDADisperserRegistry", tx, err); err != nil {
+		return err
+	}
+
+	implementation, tx, _, err = contractPaymentVault.DeployContractPaymentVault(owner, f.Client)
+	if err := f.confirm("deploying PaymentVault", tx, err); err != nil {
+		return err
+	}
+	if err := f.upgradeProxy(f.Addresses.PaymentVault, implementation); err != nil {
+		return err
+	}
+	if f.PaymentVault, err = contractPaymentVault.NewContractPaymentVault(f.Addresses.PaymentVault, f.Client); err != nil {
+		return err
+	}
+	tx, err = f.PaymentVault.Initialize(
+		owner,
+		f.Owner.Address,
+		MinNumSymbols,
+		PricePerSymbol,
+		PriceUpdateCooldown,
+		GlobalSymbolsPerPeriod,
+		ReservationPeriodInterval,
+		GlobalRatePeriodInterval,
+	)
+	if err := f.confirm("initializing PaymentVault", tx, err); err != nil {
+		return err
+	}
+
+	implementation, tx, _, err = contractEigenDAServiceManager.DeployContractEigenDAServiceManager(
+		owner,
+		f.Client,
+		f.Addresses.AVSDirectory,
+		f.Addresses.RewardsCoordinator,
+		f.Addresses.RegistryCoordinator,
+		f.Addresses.StakeRegistry,
+		f.Addresses.ThresholdRegistry,
+		f.Addresses.RelayRegistry,
+		f.Addresses.PaymentVault,
+		f.Addresses.DisperserRegistry,
+	)
+	if err := f.confirm("deploying EigenDAServiceManager", tx, err); err != nil {
+		return err
+	}
+	if err := f.upgradeProxy(f.Addresses.ServiceManager, implementation); err != nil {
+		return err
+	}
+	if f.ServiceManager, err = contractEigenDAServiceManager.NewContractEigenDAServiceManager(
+		f.Addresses.ServiceManager, f.Client); err != nil {
+		return err
+	}
+	tx, err = f.ServiceManager.Initialize(
+		owner,
+		f.Addresses.PauserRegistry,
+		big.NewInt(0),
+		f.Owner.Address,
+		[]common.Address{f.Confirmer.Address},
+		f.Owner.Address,
+	)
+	return f.confirm("initializing EigenDAServiceManager", tx, err)
+}
+
+// deployProxy deploys a TransparentUpgradeableProxy administered by the ProxyAdmin, pointing at
+// the EmptyContract until it is upgraded, as MockAVSDeployer does.
+func (f *Fixture) deployProxy(name string) (common.Address, error) {
+	address, _, err := f.deployArtifact(transparentUpgradeableProxy, f.Owner,
+		f.Addresses.EmptyContract, f.Addresses.ProxyAdmin, []byte{})
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%s proxy: %w", name, err)
+	}
+	return address, nil
+}
+
+// upgradeProxy points proxy at implementation through ProxyAdmin.upgrade.
+func (f *Fixture) upgradeProxy(proxy, implementation common.Address) error {
+	tx, err := f.proxyAdmin.Transact(f.TransactOpts(f.ProxyAdminOwner), "upgrade", proxy, implementation)
+	return f.confirm(fmt.Sprintf("upgrading proxy %s", proxy), tx, err)
+}
+
+// ProxyImplementation returns the implementation a proxy deployed by the fixture delegates to.
+func (f *Fixture) ProxyImplementation(proxy common.Address) (common.Address, error) {
+	var out []interface{}
+	if err := f.proxyAdmin.Call(&bind.CallOpts{Context: context.Background()}, &out, "getProxyImplementation", proxy); err != nil {
+		return common.Address{}, err
+	}
+	return out[0].(common.Address), nil
+}
+
+// newAccount derives a deterministic account from name, so addresses are stable across runs.
+func newAccount(name string) *Account {
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("eigenda fixture " + name)))
+	if err != nil {
+		// a keccak digest is a valid secp256k1 key with overwhelming probability
+		panic(err)
+	}
+	return &Account{Key: key, Address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// strategy is the single strategy of quorum, weighted with a multiplier of 1e18.
+func strategy(quorum int) common.Address {
+	return common.BigToAddress(big.NewInt(int64(quorum) + 1))
+}
+
+func quorumNumbers(numQuorums uint8) []byte {
+	quorums := make([]byte, numQuorums)
+	for i := range quorums {
+		quorums[i] = byte(i)
+	}
+	return quorums
+}
@@ -0,0 +1,158 @@
+package fixture_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Layr-Labs/eigenda/contracts/hashing"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+)
+
+// operatorStatusRegistered is IRegistryCoordinator.OperatorStatus.REGISTERED.
+const operatorStatusRegistered = 1
+
+func TestNew(t *testing.T) {
+	config := fixture.Config{NumOperators: 3, NumQuorums: 3, OperatorShares: big.NewInt(5e18)}
+	f := fixture.NewForTest(t, config)
+	opts := &bind.CallOpts{Context: context.Background()}
+
+	quorumCount, err := f.RegistryCoordinator.QuorumCount(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quorumCount != config.NumQuorums {
+		t.Errorf("quorum count = %d, want %d", quorumCount, config.NumQuorums)
+	}
+
+	if len(f.Operators) != config.NumOperators {
+		t.Fatalf("%d operators, want %d", len(f.Operators), config.NumOperators)
+	}
+	for _, operator := range f.Operators {
+		status, err := f.RegistryCoordinator.GetOperatorStatus(opts, operator.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != operatorStatusRegistered {
+			t.Errorf("operator %s has status %d", operator.Address, status)
+		}
+		id, err := f.RegistryCoordinator.GetOperatorId(opts, operator.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != operator.OperatorID {
+			t.Errorf("operator %s has id %x, want %x", operator.Address, id, operator.OperatorID)
+		}
+		for quorum := uint8(0); quorum < config.NumQuorums; quorum++ {
+			stake, err := f.StakeRegistry.GetCurrentStake(opts, operator.OperatorID, quorum)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stake.Cmp(config.OperatorShares) != 0 {
+				t.Errorf("operator %s has stake %s in quorum %d, want %s", operator.Address, stake, quorum, config.OperatorShares)
+			}
+		}
+	}
+	totalStake, err := f.StakeRegistry.GetCurrentTotalStake(opts, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := new(big.Int).Mul(config.OperatorShares, big.NewInt(int64(config.NumOperators))); totalStake.Cmp(want) != 0 {
+		t.Errorf("total stake = %s, want %s", totalStake, want)
+	}
+
+	for name, proxy := range map[string]common.Address{
+		"ServiceManager":      f.Addresses.ServiceManager,
+		"ThresholdRegistry":   f.Addresses.ThresholdRegistry,
+		"RelayRegistry":       f.Addresses.RelayRegistry,
+		"DisperserRegistry":   f.Addresses.DisperserRegistry,
+		"PaymentVault":        f.Addresses.PaymentVault,
+		"RegistryCoordinator": f.Addresses.RegistryCoordinator,
+		"StakeRegistry":       f.Addresses.StakeRegistry,
+		"BLSApkRegistry":      f.Addresses.BLSApkRegistry,
+		"IndexRegistry":       f.Addresses.IndexRegistry,
+	} {
+		implementation, err := f.ProxyImplementation(proxy)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if implementation == f.Addresses.EmptyContract || implementation == (common.Address{}) {
+			t.Errorf("%s proxy was not upgraded", name)
+		}
+	}
+
+	if f.WithdrawalDelayBlocks == 0 {
+		t.Error("no withdrawal delay read from the DelegationMock")
+	}
+}
+
+func TestCertVerifier(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	opts := &bind.CallOpts{Context: context.Background()}
+
+	thresholds, err := f.CertVerifier.SecurityThresholdsV2(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thresholds.ConfirmationThreshold != fixture.DefaultSecurityThresholds.ConfirmationThreshold ||
+		thresholds.AdversaryThreshold != fixture.DefaultSecurityThresholds.AdversaryThreshold {
+		t.Errorf("security thresholds = %+v, want %+v", thresholds, fixture.DefaultSecurityThresholds)
+	}
+	required, err := f.CertVerifier.QuorumNumbersRequiredV2(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(required, fixture.QuorumNumbersRequired) {
+		t.Errorf("required quorums = %x, want %x", required, fixture.QuorumNumbersRequired)
+	}
+	blobParams, err := f.CertVerifier.GetBlobParams(opts, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blobParams != fixture.DefaultVersionedBlobParams {
+		t.Errorf("blob params of version 0 = %+v, want %+v", blobParams, fixture.DefaultVersionedBlobParams)
+	}
+}
+
+func TestConfirmBatch(t *testing.T) {
+	f := fixture.NewForTest(t, fixture.Config{})
+	opts := &bind.CallOpts{Context: context.Background()}
+
+	referenceBlock, err := f.Client.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Backend.Commit()
+
+	batchHeader := structs.BatchHeader{
+		BlobHeadersRoot:       [32]byte{1},
+		QuorumNumbers:         []byte{0, 1},
+		SignedStakeForQuorums: []byte{100, 100},
+		ReferenceBlockNumber:  uint32(referenceBlock),
+	}
+	for i := uint32(0); i < 2; i++ {
+		batchID, metadata, err := f.ConfirmBatch(batchHeader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if batchID != i {
+			t.Errorf("batch id = %d, want %d", batchID, i)
+		}
+		want, err := hashing.HashBatchMetadata(metadata)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored, err := f.ServiceManager.BatchIdToBatchMetadataHash(opts, batchID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored != want {
+			t.Errorf("batch %d has metadata hash %x, want %x", batchID, stored, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package fixture
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	contractRegistryCoordinator "github.com/Layr-Labs/eigenda/contracts/bindings/RegistryCoordinator"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// Operator is an operator registered in every quorum of the fixture.
+type Operator struct {
+	Account
+
+	// BLSPrivateKey is the scalar the BLS public keys are multiples of.
+	BLSPrivateKey *big.Int
+	PubkeyG1      structs.G1Point
+	PubkeyG2      structs.G2Point
+	// OperatorID is BN254.hashG1Point(PubkeyG1), the id the registries know the operator by.
+	OperatorID [32]byte
+	Socket     string
+}
+
+// newOperator derives the ECDSA and BLS keys of the i-th operator deterministically.
+func newOperator(i int) (*Operator, error) {
+	account := newAccount(fmt.Sprintf("operator %d", i))
+
+	var sk fr.Element
+	sk.SetBytes(crypto.Keccak256([]byte(fmt.Sprintf("eigenda fixture operator %d bls", i))))
+	if sk.IsZero() {
+		return nil, fmt.Errorf("operator %d: zero BLS private key", i)
+	}
+	blsPrivateKey := sk.BigInt(new(big.Int))
+
+	_, _, g1, g2 := bn254.Generators()
+	var pubkeyG1 bn254.G1Affine
+	pubkeyG1.ScalarMultiplication(&g1, blsPrivateKey)
+	var pubkeyG2 bn254.G2Affine
+	pubkeyG2.ScalarMultiplication(&g2, blsPrivateKey)
+
+	operator := &Operator{
+		Account:       *account,
+		BLSPrivateKey: blsPrivateKey,
+		PubkeyG1:      g1Point(&pubkeyG1),
+		PubkeyG2:      g2Point(&pubkeyG2),
+		Socket:        fmt.Sprintf("operator%d.eigenda.test:32005;32006", i),
+	}
+	x, y := pubkeyG1.X.Bytes(), pubkeyG1.Y.Bytes()
+	operator.OperatorID = [32]byte(crypto.Keccak256Hash(x[:], y[:]))
+	return operator, nil
+}
+
+// registerOperator registers operator in quorums through RegistryCoordinator.registerOperator,
+// proving possession of its BLS key over the registration message hash.
+func (f *Fixture) registerOperator(operator *Operator, quorums []byte) error {
+	messageHash, err := f.RegistryCoordinator.PubkeyRegistrationMessageHash(&bind.CallOpts{Context: context.Background()}, operator.Address)
+	if err != nil {
+		return fmt.Errorf("operator %s: %w", operator.Address, err)
+	}
+	var hash bn254.G1Affine
+	hash.X.SetBigInt(messageHash.X)
+	hash.Y.SetBigInt(messageHash.Y)
+	var signature bn254.G1Affine
+	signature.ScalarMultiplication(&hash, operator.BLSPrivateKey)
+
+	params := contractRegistryCoordinator.IBLSApkRegistryPubkeyRegistrationParams{
+		PubkeyRegistrationSignature: structs.MustConvert[contractRegistryCoordinator.BN254G1Point](g1Point(&signature)),
+		PubkeyG1:                    structs.MustConvert[contractRegistryCoordinator.BN254G1Point](operator.PubkeyG1),
+		PubkeyG2:                    structs.MustConvert[contractRegistryCoordinator.BN254G2Point](operator.PubkeyG2),
+	}
+	opts := f.TransactOpts(&operator.Account)
+	// Gas estimation leaves too little headroom for the pairing precompile call of the pubkey check.
+	opts.GasLimit = 5_000_000
+	// The AVSDirectoryMock accepts any operator signature.
+	tx, err := f.RegistryCoordinator.RegisterOperator(
+		opts,
+		quorums,
+		operator.Socket,
+		params,
+		contractRegistryCoordinator.ISignatureUtilsSignatureWithSaltAndExpiry{Expiry: big.NewInt(0)},
+	)
+	return f.confirm(fmt.Sprintf("registering operator %s", operator.Address), tx, err)
+}
+
+// DeregisterOperator deregisters operator from quorums through RegistryCoordinator.deregisterOperator.
+func (f *Fixture) DeregisterOperator(operator *Operator, quorums []byte) error {
+	opts := f.TransactOpts(&operator.Account)
+	// As for registration, gas estimation leaves too little headroom.
+	opts.GasLimit = 5_000_000
+	tx, err := f.RegistryCoordinator.DeregisterOperator(opts, quorums)
+	return f.confirm(fmt.Sprintf("deregistering operator %s", operator.Address), tx, err)
+}
+
+// SetOperatorShares gives operator shares of the strategy of quorum in the DelegationMock and
+// applies them to its stakes through RegistryCoordinator.updateOperators.
+func (f *Fixture) SetOperatorShares(operator *Operator, quorum uint8, shares *big.Int) error {
+	tx, err := f.delegation.Transact(
+		f.TransactOpts(f.Owner), "setOperatorShares", operator.Address, strategy(int(quorum)), shares)
+	if err := f.confirm(fmt.Sprintf("setting shares of operator %s", operator.Address), tx, err); err != nil {
+		return err
+	}
+	tx, err = f.RegistryCoordinator.UpdateOperators(f.TransactOpts(f.Owner), []common.Address{operator.Address})
+	return f.confirm(fmt.Sprintf("updating stakes of operator %s", operator.Address), tx, err)
+}
+
+func g1Point(p *bn254.G1Affine) structs.G1Point {
+	return structs.G1Point{
+		X: p.X.BigInt(new(big.Int)),
+		Y: p.Y.BigInt(new(big.Int)),
+	}
+}
+
+// g2Point encodes p the way BN254.G2Point does, with the imaginary coefficient first.
+func g2Point(p *bn254.G2Affine) structs.G2Point {
+	return structs.G2Point{
+		X: [2]*big.Int{p.X.A1.BigInt(new(big.Int)), p.X.A0.BigInt(new(big.Int))},
+		Y: [2]*big.Int{p.Y.A1.BigInt(new(big.Int)), p.Y.A0.BigInt(new(big.Int))},
+	}
+}
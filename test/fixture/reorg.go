@@ -0,0 +1,89 @@
+package fixture
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// ReorgClient is a client of the simulated chain that can deliver removed logs to the log
+// subscriptions made through it. The simulated backend never does so itself: its Fork rewinds the
+// chain rather than reorganizing it, so a test forks the chain and then calls SendRemoved with the
+// logs a node would have reported as removed.
+type ReorgClient struct {
+	simulated.Client
+
+	mu   sync.Mutex
+	subs []*logSubscription
+}
+
+type logSubscription struct {
+	ctx   context.Context
+	query ethereum.FilterQuery
+	ch    chan<- types.Log
+	sub   ethereum.Subscription
+}
+
+// NewReorgClient returns a ReorgClient of the fixture's chain.
+func (f *Fixture) NewReorgClient() *ReorgClient {
+	return &ReorgClient{Client: f.Client}
+}
+
+// SubscribeFilterLogs implements ethereum.LogFilterer.
+func (c *ReorgClient) SubscribeFilterLogs(
+	ctx context.Context,
+	query ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	sub, err := c.Client.SubscribeFilterLogs(ctx, query, ch)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.subs = append(c.subs, &logSubscription{ctx: ctx, query: query, ch: ch, sub: sub})
+	c.mu.Unlock()
+	return sub, nil
+}
+
+// SendRemoved delivers each of logs with Removed set to the subscriptions whose query matches it,
+// blocking until they have been received.
+func (c *ReorgClient) SendRemoved(logs ...*types.Log) {
+	c.mu.Lock()
+	subs := slices.Clone(c.subs)
+	c.mu.Unlock()
+
+	for _, log := range logs {
+		removed := *log
+		removed.Removed = true
+		for _, s := range subs {
+			if !matches(s.query, &removed) {
+				continue
+			}
+			select {
+			case s.ch <- removed:
+			case <-s.ctx.Done():
+			case <-s.sub.Err():
+			}
+		}
+	}
+}
+
+// matches reports whether query selects log, ignoring its block range.
+func matches(query ethereum.FilterQuery, log *types.Log) bool {
+	if len(query.Addresses) > 0 && !slices.Contains(query.Addresses, log.Address) {
+		return false
+	}
+	if len(query.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, topics := range query.Topics {
+		if len(topics) > 0 && !slices.Contains(topics, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
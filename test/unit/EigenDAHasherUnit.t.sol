@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: MIT
+pragma solidity =0.8.12;
+
+import "forge-std/Test.sol";
+import {EigenDAHasher} from "../../src/libraries/EigenDAHasher.sol";
+import {GenerateHashes} from "../../script/GenerateUnitTestHashes.s.sol";
+import "../../src/interfaces/IEigenDAStructs.sol";
+
+contract EigenDAHasherUnit is Test {
+    GenerateHashes generateHashes;
+    string goldenVectors;
+
+    function setUp() public virtual {
+        generateHashes = new GenerateHashes();
+        goldenVectors = vm.readFile(generateHashes.GOLDEN_VECTORS_PATH());
+    }
+
+    function test_goldenVectors_V1() public {
+        BlobHeader memory blobHeader = generateHashes.goldenBlobHeader();
+        BatchMetadata memory batchMetadata = generateHashes.goldenBatchMetadata();
+
+        assertEq(vm.parseJsonBytes(goldenVectors, ".blobHeader"), abi.encode(blobHeader));
+        assertEq(vm.parseJsonBytes(goldenVectors, ".batchMetadata"), abi.encode(batchMetadata));
+
+        assertEq(vm.parseJsonBytes32(goldenVectors, ".hashBlobHeader"), EigenDAHasher.hashBlobHeader(blobHeader));
+        assertEq(
+            vm.parseJsonBytes32(goldenVectors, ".hashBatchHeader"),
+            EigenDAHasher.hashBatchHeaderMemory(batchMetadata.batchHeader)
+        );
+        assertEq(
+            vm.parseJsonBytes32(goldenVectors, ".hashReducedBatchHeader"),
+            EigenDAHasher.hashBatchHeaderToReducedBatchHeader(batchMetadata.batchHeader)
+        );
+        assertEq(
+            vm.parseJsonBytes32(goldenVectors, ".hashBatchMetadata"), EigenDAHasher.hashBatchMetadata(batchMetadata)
+        );
+    }
+
+    function test_goldenVectors_V2() public {
+        BlobCertificate memory blobCertificate = generateHashes.goldenBlobCertificate();
+        BatchHeaderV2 memory batchHeaderV2 = generateHashes.goldenBatchHeaderV2();
+
+        assertEq(vm.parseJsonBytes(goldenVectors, ".blobCertificate"), abi.encode(blobCertificate));
+        assertEq(vm.parseJsonBytes(goldenVectors, ".batchHeaderV2"), abi.encode(batchHeaderV2));
+
+        assertEq(
+            vm.parseJsonBytes32(goldenVectors, ".hashBlobHeaderV2"),
+            EigenDAHasher.hashBlobHeaderV2(blobCertificate.blobHeader)
+        );
+        assertEq(
+            vm.parseJsonBytes32(goldenVectors, ".hashBlobCertificate"),
+            EigenDAHasher.hashBlobCertificate(blobCertificate)
+        );
+        assertEq(
+            vm.parseJsonBytes32(goldenVectors, ".hashBatchHeaderV2"), EigenDAHasher.hashBatchHeaderV2(batchHeaderV2)
+        );
+    }
+}
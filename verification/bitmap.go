@@ -0,0 +1,56 @@
+package verification
+
+import "math/big"
+
+// maxByteArrayLength is BitmapUtils.MAX_BYTE_ARRAY_LENGTH.
+const maxByteArrayLength = 256
+
+// orderedBytesArrayToBitmap mirrors BitmapUtils.orderedBytesArrayToBitmap(bytes).
+func orderedBytesArrayToBitmap(orderedBytesArray []byte) (*big.Int, error) {
+	if len(orderedBytesArray) > maxByteArrayLength {
+		return nil, ErrBytesArrayTooLong
+	}
+
+	bitmap := new(big.Int)
+	if len(orderedBytesArray) == 0 {
+		return bitmap, nil
+	}
+
+	bitmap.SetBit(bitmap, int(orderedBytesArray[0]), 1)
+	for i := 1; i < len(orderedBytesArray); i++ {
+		bitMask := new(big.Int).Lsh(big.NewInt(1), uint(orderedBytesArray[i]))
+		if bitMask.Cmp(bitmap) <= 0 {
+			return nil, ErrBytesArrayNotOrdered
+		}
+		bitmap.Or(bitmap, bitMask)
+	}
+	return bitmap, nil
+}
+
+// orderedBytesArrayToBitmapWithUpperBound mirrors BitmapUtils.orderedBytesArrayToBitmap(bytes, uint8).
+func orderedBytesArrayToBitmapWithUpperBound(orderedBytesArray []byte, bitUpperBound uint8) (*big.Int, error) {
+	bitmap, err := orderedBytesArrayToBitmap(orderedBytesArray)
+	if err != nil {
+		return nil, err
+	}
+	if new(big.Int).Rsh(bitmap, uint(bitUpperBound)).Sign() != 0 {
+		return nil, ErrBitmapExceedsMax
+	}
+	return bitmap, nil
+}
+
+// isSubsetOf mirrors BitmapUtils.isSubsetOf.
+func isSubsetOf(a, b *big.Int) bool {
+	return new(big.Int).AndNot(a, b).Sign() == 0
+}
+
+// countNumOnes mirrors BitmapUtils.countNumOnes.
+func countNumOnes(bitmap *big.Int) uint64 {
+	var count uint64
+	for _, word := range bitmap.Bits() {
+		for w := uint64(word); w != 0; w &= w - 1 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,37 @@
+package verification
+
+import "github.com/Layr-Labs/eigenda/contracts/reverts"
+
+// Errors returned by the verifier. They are the sentinels the reverts package decodes the
+// corresponding on-chain reverts to, so a cert rejected here is rejected by the contract for the
+// same reason and both errors match with errors.Is.
+var (
+	ErrInclusionProofInvalid    = reverts.ErrInclusionProofInvalid
+	ErrBlobQuorumsNotSubset     = reverts.ErrBlobQuorumsNotSubset
+	ErrRequiredQuorumsNotSubset = reverts.ErrRequiredQuorumsNotSubset
+	ErrRelayKeyNotSet           = reverts.ErrRelayKeyNotSet
+
+	ErrInvalidSecurityThresholds = reverts.ErrInvalidSecurityThresholds
+	ErrSecurityAssumptionsNotMet = reverts.ErrSecurityAssumptionsNotMet
+
+	ErrEmptyQuorumInput            = reverts.ErrEmptyQuorumInput
+	ErrQuorumLengthMismatch        = reverts.ErrQuorumLengthMismatch
+	ErrNonSignerLengthMismatch     = reverts.ErrNonSignerLengthMismatch
+	ErrInvalidReferenceBlock       = reverts.ErrInvalidReferenceBlock
+	ErrNonSignerPubkeysNotSorted   = reverts.ErrNonSignerPubkeysNotSorted
+	ErrStaleStakes                 = reverts.ErrStaleStakes
+	ErrQuorumApkMismatch           = reverts.ErrQuorumApkMismatch
+	ErrPairingPrecompileCallFailed = reverts.ErrPairingPrecompileCallFailed
+	ErrSignatureInvalid            = reverts.ErrSignatureInvalid
+
+	ErrBytesArrayTooLong    = reverts.ErrBytesArrayTooLong
+	ErrBytesArrayNotOrdered = reverts.ErrBytesArrayNotOrdered
+	ErrBitmapExceedsMax     = reverts.ErrBitmapExceedsMax
+
+	// ErrECAddFailed has no on-chain counterpart to decode: BN254.plus reverts without revert data.
+	ErrECAddFailed = reverts.ErrECAddFailed
+
+	ErrPanicArithmetic     = reverts.ErrPanicArithmetic
+	ErrPanicDivisionByZero = reverts.ErrPanicDivisionByZero
+	ErrPanicOutOfBounds    = reverts.ErrPanicOutOfBounds
+)
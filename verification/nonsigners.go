@@ -0,0 +1,96 @@
+package verification
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	contractOperatorStateRetriever "github.com/Layr-Labs/eigenda/contracts/bindings/OperatorStateRetriever"
+	"github.com/Layr-Labs/eigenda/contracts/bls"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// IndexSource supplies the registry indices of OperatorStateRetriever.getCheckSignaturesIndices.
+// RetrieverIndexSource asks the contract, and operatorstate.HistoryCache computes them from
+// cached registry histories.
+type IndexSource interface {
+	CheckSignaturesIndices(
+		ctx context.Context,
+		referenceBlockNumber uint32,
+		quorumNumbers []byte,
+		nonSignerOperatorIds [][32]byte,
+	) (structs.CheckSignaturesIndices, error)
+}
+
+// RetrieverIndexSource is an IndexSource calling OperatorStateRetriever.getCheckSignaturesIndices
+// for the registries of RegistryCoordinator.
+type RetrieverIndexSource struct {
+	Retriever           *contractOperatorStateRetriever.ContractOperatorStateRetrieverCaller
+	RegistryCoordinator common.Address
+}
+
+// CheckSignaturesIndices implements IndexSource.
+func (s *RetrieverIndexSource) CheckSignaturesIndices(
+	ctx context.Context,
+	referenceBlockNumber uint32,
+	quorumNumbers []byte,
+	nonSignerOperatorIds [][32]byte,
+) (structs.CheckSignaturesIndices, error) {
+	return s.Retriever.GetCheckSignaturesIndices(
+		&bind.CallOpts{Context: ctx},
+		s.RegistryCoordinator,
+		referenceBlockNumber,
+		quorumNumbers,
+		nonSignerOperatorIds,
+	)
+}
+
+// OperatorID mirrors BN254.hashG1Point, which derives the id the registries know an operator by
+// from its G1 pubkey.
+func OperatorID(pubkey structs.G1Point) [32]byte {
+	return bls.HashG1Point(pubkey)
+}
+
+// GetNonSignerStakesAndSignature mirrors EigenDACertVerificationUtils._getNonSignerStakesAndSignature,
+// turning the attestation of signedBatch into the verifyDACertV2 input with indices from indices.
+// It also returns the signed quorum numbers, packed as bytes.
+func GetNonSignerStakesAndSignature(
+	ctx context.Context,
+	indices IndexSource,
+	signedBatch structs.SignedBatch,
+) (structs.NonSignerStakesAndSignature, []byte, error) {
+	attestation := signedBatch.Attestation
+
+	nonSignerOperatorIds := make([][32]byte, len(attestation.NonSignerPubkeys))
+	for i, pubkey := range attestation.NonSignerPubkeys {
+		nonSignerOperatorIds[i] = OperatorID(pubkey)
+	}
+
+	// quorum numbers are uint32 in the attestation and are truncated to uint8 when packed
+	signedQuorumNumbers := make([]byte, len(attestation.QuorumNumbers))
+	for i, quorumNumber := range attestation.QuorumNumbers {
+		signedQuorumNumbers[i] = uint8(quorumNumber)
+	}
+
+	checkSignaturesIndices, err := indices.CheckSignaturesIndices(
+		ctx,
+		signedBatch.BatchHeader.ReferenceBlockNumber,
+		signedQuorumNumbers,
+		nonSignerOperatorIds,
+	)
+	if err != nil {
+		return structs.NonSignerStakesAndSignature{}, nil, err
+	}
+
+	return structs.NonSignerStakesAndSignature{
+		NonSignerQuorumBitmapIndices: checkSignaturesIndices.NonSignerQuorumBitmapIndices,
+		NonSignerPubkeys:             attestation.NonSignerPubkeys,
+		QuorumApks:                   attestation.QuorumApks,
+		ApkG2:                        attestation.ApkG2,
+		Sigma:                        attestation.Sigma,
+		QuorumApkIndices:             checkSignaturesIndices.QuorumApkIndices,
+		TotalStakeIndices:            checkSignaturesIndices.TotalStakeIndices,
+		NonSignerStakeIndices:        checkSignaturesIndices.NonSignerStakeIndices,
+	}, signedQuorumNumbers, nil
+}
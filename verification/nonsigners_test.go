@@ -0,0 +1,131 @@
+package verification_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Layr-Labs/eigenda/contracts/operatorstate"
+	"github.com/Layr-Labs/eigenda/contracts/reverts"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/verification"
+)
+
+// failingIndexSource is an IndexSource that always fails with err.
+type failingIndexSource struct {
+	err error
+}
+
+func (s failingIndexSource) CheckSignaturesIndices(context.Context, uint32, []byte, [][32]byte) (structs.CheckSignaturesIndices, error) {
+	return structs.CheckSignaturesIndices{}, s.err
+}
+
+func TestOperatorID(t *testing.T) {
+	f := newCertFixture(t)
+	for _, operator := range f.Operators {
+		id, err := f.RegistryCoordinator.GetOperatorId(f.opts, operator.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := verification.OperatorID(operator.PubkeyG1); got != id {
+			t.Errorf("OperatorID of %s = %x, registered as %x", operator.Address, got, id)
+		}
+	}
+}
+
+// signedBatch returns the SignedBatch carrying the attestation of c, with its quorum numbers
+// replaced by quorumNumbers.
+func signedBatch(c *cert, quorumNumbers ...uint32) structs.SignedBatch {
+	return structs.SignedBatch{
+		BatchHeader: c.batchHeader,
+		Attestation: structs.Attestation{
+			NonSignerPubkeys: c.params.NonSignerPubkeys,
+			QuorumApks:       c.params.QuorumApks,
+			Sigma:            c.params.Sigma,
+			ApkG2:            c.params.ApkG2,
+			QuorumNumbers:    quorumNumbers,
+		},
+	}
+}
+
+func TestGetNonSignerStakesAndSignature(t *testing.T) {
+	f := newCertFixture(t)
+	ctx := context.Background()
+
+	historyCache, err := operatorstate.NewHistoryCache(ctx, f.Client, f.Addresses.RegistryCoordinator, operatorstate.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := map[string]verification.IndexSource{
+		"retriever": &verification.RetrieverIndexSource{
+			Retriever:           &f.OperatorStateRetriever.ContractOperatorStateRetrieverCaller,
+			RegistryCoordinator: f.Addresses.RegistryCoordinator,
+		},
+		"history cache": historyCache,
+	}
+
+	for _, tc := range []struct {
+		name          string
+		nonSigners    []int
+		quorumNumbers []uint32
+		wantQuorums   []byte
+	}{
+		{name: "all signed", quorumNumbers: []uint32{0, 1}, wantQuorums: []byte{0, 1}},
+		{name: "one non-signer", nonSigners: []int{3}, quorumNumbers: []uint32{0, 1}, wantQuorums: []byte{0, 1}},
+		{name: "quorum numbers are truncated", nonSigners: []int{1}, quorumNumbers: []uint32{256, 257}, wantQuorums: []byte{0, 1}},
+	} {
+		for name, source := range sources {
+			t.Run(tc.name+"/"+name, func(t *testing.T) {
+				c := f.cert(t, blobAllQuorums, tc.nonSigners...)
+				batch := signedBatch(c, tc.quorumNumbers...)
+
+				params, signedQuorumNumbers, err := verification.GetNonSignerStakesAndSignature(ctx, source, batch)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(signedQuorumNumbers, tc.wantQuorums) {
+					t.Errorf("signed quorum numbers = %v, want %v", signedQuorumNumbers, tc.wantQuorums)
+				}
+				want, err := f.CertVerifier.GetNonSignerStakesAndSignature(f.opts, batch)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// compared as printed, since the contract returns empty slices where Go keeps nil ones
+				if fmt.Sprintf("%+v", params) != fmt.Sprintf("%+v", want) {
+					t.Errorf("GetNonSignerStakesAndSignature = %+v, contract %+v", params, want)
+				}
+
+				// the result is the input verifyDACertV2 needs
+				c.params, c.signedQuorumNumbers = params, signedQuorumNumbers
+				f.check(t, c, nil)
+			})
+		}
+	}
+
+	t.Run("unregistered non-signer", func(t *testing.T) {
+		c := f.cert(t, blobAllQuorums)
+		batch := signedBatch(c, 0, 1)
+		batch.Attestation.NonSignerPubkeys = []structs.G1Point{c.params.Sigma}
+
+		_, contractErr := f.CertVerifier.GetNonSignerStakesAndSignature(f.opts, batch)
+		if err := reverts.Decode(contractErr); !errors.Is(err, reverts.ErrNoQuorumBitmapUpdate) {
+			t.Fatalf("contract = %v, want %v", err, reverts.ErrNoQuorumBitmapUpdate)
+		}
+		for name, source := range sources {
+			_, _, err := verification.GetNonSignerStakesAndSignature(ctx, source, batch)
+			if err := reverts.Decode(err); !errors.Is(err, reverts.ErrNoQuorumBitmapUpdate) {
+				t.Errorf("%s: GetNonSignerStakesAndSignature = %v, want %v", name, err, reverts.ErrNoQuorumBitmapUpdate)
+			}
+		}
+	})
+
+	t.Run("index source error", func(t *testing.T) {
+		sourceErr := errors.New("index source unavailable")
+		_, _, err := verification.GetNonSignerStakesAndSignature(ctx, failingIndexSource{sourceErr}, signedBatch(f.cert(t, blobAllQuorums), 0, 1))
+		if !errors.Is(err, sourceErr) {
+			t.Errorf("GetNonSignerStakesAndSignature = %v, want %v", err, sourceErr)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package verification
+
+import (
+	"math"
+
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// VerifySecurityParams mirrors EigenDACertVerificationUtils._verifyDACertSecurityParams, including
+// the checked arithmetic panics the contract hits for degenerate blob params.
+func VerifySecurityParams(
+	blobParams structs.VersionedBlobParams,
+	securityThresholds structs.SecurityThresholds,
+) error {
+	if securityThresholds.ConfirmationThreshold <= securityThresholds.AdversaryThreshold {
+		return ErrInvalidSecurityThresholds
+	}
+	gamma := uint64(securityThresholds.ConfirmationThreshold - securityThresholds.AdversaryThreshold)
+
+	if blobParams.CodingRate == 0 {
+		return ErrPanicDivisionByZero
+	}
+	reduction := (1_000_000 / gamma) / uint64(blobParams.CodingRate)
+	if reduction > 10000 {
+		return ErrPanicArithmetic
+	}
+	n := (10000 - reduction) * uint64(blobParams.NumChunks)
+
+	// maxNumOperators * 10000 is evaluated as a uint32 on chain
+	required := uint64(blobParams.MaxNumOperators) * 10000
+	if required > math.MaxUint32 {
+		return ErrPanicArithmetic
+	}
+	if n < required {
+		return ErrSecurityAssumptionsNotMet
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package verification
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/Layr-Labs/eigenda/contracts/bls"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// maxUint96 bounds the stakes tracked by the StakeRegistry.
+var maxUint96 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 96), big.NewInt(1))
+
+// SignatureState is the registry state at a cert's reference block which
+// BLSSignatureChecker.checkSignatures looks up through the indices in NonSignerStakesAndSignature.
+// The indices themselves are not checked; the state is taken to be what they point at.
+type SignatureState struct {
+	// NonSignerQuorumBitmaps[j] is the quorum bitmap of nonSignerPubkeys[j] at the reference block.
+	NonSignerQuorumBitmaps []*big.Int
+	// QuorumApks[i] is the aggregate pubkey of signedQuorumNumbers[i] in the BLSApkRegistry.
+	QuorumApks []structs.G1Point
+	// TotalStakes[i] is the total stake of signedQuorumNumbers[i].
+	TotalStakes []*big.Int
+	// NonSignerStakes[i][k] is the stake of the k-th non-signer that belongs to signedQuorumNumbers[i],
+	// laid out like nonSignerStakeIndices.
+	NonSignerStakes [][]*big.Int
+	// QuorumUpdateBlockNumbers[i] is RegistryCoordinator.quorumUpdateBlockNumber of signedQuorumNumbers[i].
+	// It is only read when stale stakes are forbidden.
+	QuorumUpdateBlockNumbers []uint32
+}
+
+// checkSignatures mirrors BLSSignatureChecker.checkSignatures with the registry lookups served from state.
+func checkSignatures(
+	registry *RegistryState,
+	state *SignatureState,
+	msgHash [32]byte,
+	quorumNumbers []byte,
+	referenceBlockNumber uint32,
+	params structs.NonSignerStakesAndSignature,
+) (*structs.QuorumStakeTotals, [32]byte, error) {
+	if len(quorumNumbers) == 0 {
+		return nil, [32]byte{}, ErrEmptyQuorumInput
+	}
+	if len(quorumNumbers) != len(params.QuorumApks) ||
+		len(quorumNumbers) != len(params.QuorumApkIndices) ||
+		len(quorumNumbers) != len(params.TotalStakeIndices) ||
+		len(quorumNumbers) != len(params.NonSignerStakeIndices) {
+		return nil, [32]byte{}, ErrQuorumLengthMismatch
+	}
+	if len(params.NonSignerPubkeys) != len(params.NonSignerQuorumBitmapIndices) {
+		return nil, [32]byte{}, ErrNonSignerLengthMismatch
+	}
+	if referenceBlockNumber >= registry.BlockNumber {
+		return nil, [32]byte{}, ErrInvalidReferenceBlock
+	}
+	if err := state.validate(len(quorumNumbers), len(params.NonSignerPubkeys), registry.StaleStakesForbidden); err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	signingQuorumBitmap, err := orderedBytesArrayToBitmapWithUpperBound(quorumNumbers, registry.QuorumCount)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	// the points are summed by bls.SignersApk below; they are validated here so that the first
+	// failing check is the one the contract reverts on
+	pubkeyHashes := make([][32]byte, len(params.NonSignerPubkeys))
+	for j, pubkey := range params.NonSignerPubkeys {
+		pubkeyHashes[j] = bls.HashG1Point(pubkey)
+		if j != 0 && new(big.Int).SetBytes(pubkeyHashes[j][:]).Cmp(new(big.Int).SetBytes(pubkeyHashes[j-1][:])) <= 0 {
+			return nil, [32]byte{}, ErrNonSignerPubkeysNotSorted
+		}
+
+		// scalar_mul_tiny never touches the point when the non-signer is in none of the signed quorums
+		if countNumOnes(new(big.Int).And(state.NonSignerQuorumBitmaps[j], signingQuorumBitmap)) == 0 {
+			continue
+		}
+		if _, ok := bls.G1Affine(pubkey); !ok {
+			return nil, [32]byte{}, ErrECAddFailed
+		}
+	}
+
+	stakeTotals := &structs.QuorumStakeTotals{
+		SignedStakeForQuorum: make([]*big.Int, len(quorumNumbers)),
+		TotalStakeForQuorum:  make([]*big.Int, len(quorumNumbers)),
+	}
+	for i, quorumNumber := range quorumNumbers {
+		if registry.StaleStakesForbidden &&
+			uint64(state.QuorumUpdateBlockNumbers[i])+uint64(registry.WithdrawalDelayBlocks) <= uint64(referenceBlockNumber) {
+			return nil, [32]byte{}, ErrStaleStakes
+		}
+
+		providedApkHash := bls.HashG1Point(params.QuorumApks[i])
+		storedApkHash := bls.HashG1Point(state.QuorumApks[i])
+		if [24]byte(providedApkHash[:24]) != [24]byte(storedApkHash[:24]) {
+			return nil, [32]byte{}, ErrQuorumApkMismatch
+		}
+		if _, ok := bls.G1Affine(params.QuorumApks[i]); !ok {
+			return nil, [32]byte{}, ErrECAddFailed
+		}
+
+		stakeTotals.TotalStakeForQuorum[i] = new(big.Int).Set(state.TotalStakes[i])
+		signedStake := new(big.Int).Set(state.TotalStakes[i])
+		nonSignerForQuorumIndex := 0
+		for j := range params.NonSignerPubkeys {
+			if state.NonSignerQuorumBitmaps[j].Bit(int(quorumNumber)) == 0 {
+				continue
+			}
+			if nonSignerForQuorumIndex >= len(state.NonSignerStakes[i]) {
+				return nil, [32]byte{}, ErrPanicOutOfBounds
+			}
+			signedStake.Sub(signedStake, state.NonSignerStakes[i][nonSignerForQuorumIndex])
+			if signedStake.Sign() < 0 {
+				return nil, [32]byte{}, ErrPanicArithmetic
+			}
+			nonSignerForQuorumIndex++
+		}
+		stakeTotals.SignedStakeForQuorum[i] = signedStake
+	}
+
+	apk, ok := bls.SignersApk(quorumNumbers, params.QuorumApks, params.NonSignerPubkeys, state.NonSignerQuorumBitmaps)
+	if !ok {
+		return nil, [32]byte{}, ErrECAddFailed
+	}
+	// trySignatureAndApkVerification adds sigma to the scaled apk before it pairs, reverting on an invalid sigma
+	if _, ok := bls.G1Affine(params.Sigma); !ok {
+		return nil, [32]byte{}, ErrECAddFailed
+	}
+	pairingSuccessful, signatureIsValid := bls.TrySignatureAndApkVerification(msgHash, apk, params.ApkG2, params.Sigma)
+	if !pairingSuccessful {
+		return nil, [32]byte{}, ErrPairingPrecompileCallFailed
+	}
+	if !signatureIsValid {
+		return nil, [32]byte{}, ErrSignatureInvalid
+	}
+
+	return stakeTotals, hashSignatoryRecord(referenceBlockNumber, pubkeyHashes), nil
+}
+
+// SignatoryRecordHash returns the signatoryRecordHash BLSSignatureChecker.checkSignatures
+// commits to for a batch referencing referenceBlockNumber and signed by all but nonSignerPubkeys.
+func SignatoryRecordHash(referenceBlockNumber uint32, nonSignerPubkeys []structs.G1Point) [32]byte {
+	pubkeyHashes := make([][32]byte, len(nonSignerPubkeys))
+	for j, pubkey := range nonSignerPubkeys {
+		pubkeyHashes[j] = bls.HashG1Point(pubkey)
+	}
+	return hashSignatoryRecord(referenceBlockNumber, pubkeyHashes)
+}
+
+// hashSignatoryRecord mirrors keccak256(abi.encodePacked(referenceBlockNumber, nonSignerPubkeyHashes)).
+func hashSignatoryRecord(referenceBlockNumber uint32, pubkeyHashes [][32]byte) [32]byte {
+	packed := make([]byte, 4, 4+32*len(pubkeyHashes))
+	packed[0] = byte(referenceBlockNumber >> 24)
+	packed[1] = byte(referenceBlockNumber >> 16)
+	packed[2] = byte(referenceBlockNumber >> 8)
+	packed[3] = byte(referenceBlockNumber)
+	for _, pubkeyHash := range pubkeyHashes {
+		packed = append(packed, pubkeyHash[:]...)
+	}
+	return [32]byte(crypto.Keccak256Hash(packed))
+}
+
+func (s *SignatureState) validate(numQuorums, numNonSigners int, staleStakesForbidden bool) error {
+	if len(s.QuorumApks) != numQuorums || len(s.TotalStakes) != numQuorums || len(s.NonSignerStakes) != numQuorums {
+		return fmt.Errorf("signature state has quorum data for %d quorums, expected %d", len(s.TotalStakes), numQuorums)
+	}
+	if staleStakesForbidden && len(s.QuorumUpdateBlockNumbers) != numQuorums {
+		return fmt.Errorf("signature state has update block numbers for %d quorums, expected %d", len(s.QuorumUpdateBlockNumbers), numQuorums)
+	}
+	if len(s.NonSignerQuorumBitmaps) != numNonSigners {
+		return fmt.Errorf("signature state has bitmaps for %d non-signers, expected %d", len(s.NonSignerQuorumBitmaps), numNonSigners)
+	}
+	for j, bitmap := range s.NonSignerQuorumBitmaps {
+		if bitmap == nil || bitmap.Sign() < 0 {
+			return fmt.Errorf("invalid quorum bitmap for non-signer %d", j)
+		}
+	}
+	for i, stake := range s.TotalStakes {
+		if !isUint96(stake) {
+			return fmt.Errorf("total stake of quorum index %d is not a uint96", i)
+		}
+		for k, nonSignerStake := range s.NonSignerStakes[i] {
+			if !isUint96(nonSignerStake) {
+				return fmt.Errorf("stake of non-signer %d in quorum index %d is not a uint96", k, i)
+			}
+		}
+	}
+	return nil
+}
+
+func isUint96(v *big.Int) bool {
+	return v != nil && v.Sign() >= 0 && v.Cmp(maxUint96) <= 0
+}
@@ -0,0 +1,564 @@
+package verification_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/Layr-Labs/eigenda/contracts/bls"
+	"github.com/Layr-Labs/eigenda/contracts/hashing"
+	"github.com/Layr-Labs/eigenda/contracts/merkle"
+	"github.com/Layr-Labs/eigenda/contracts/reverts"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+	"github.com/Layr-Labs/eigenda/contracts/test/fixture"
+	"github.com/Layr-Labs/eigenda/contracts/verification"
+)
+
+// relayKey is the key of the only relay registered by newCertFixture.
+const relayKey = 0
+
+// Blob indices of the certificates in the batch of newCertFixture.
+const (
+	blobAllQuorums = iota
+	blobFirstQuorum
+	blobUnknownRelay
+	blobUnknownVersion
+	blobTooManyQuorums
+)
+
+type certFixture struct {
+	*fixture.Fixture
+
+	opts           *bind.CallOpts
+	registry       *verification.RegistryState
+	verifier       *verification.CertVerifier
+	certificates   []structs.BlobCertificate
+	tree           *merkle.Tree
+	referenceBlock uint32
+}
+
+// newCertFixture deploys the fixture, registers a relay and mines past the reference block of the
+// certs built by cert.
+func newCertFixture(t *testing.T) *certFixture {
+	t.Helper()
+	f := &certFixture{Fixture: fixture.NewForTest(t, fixture.Config{})}
+	ctx := context.Background()
+	f.opts = &bind.CallOpts{Context: ctx}
+
+	relayAddress := common.HexToAddress("0x4e1a7")
+	tx, err := f.RelayRegistry.AddRelayInfo(f.TransactOpts(f.Owner),
+		structs.RelayInfo{RelayAddress: relayAddress, RelayURL: "relay0.eigenda.test:32007"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Mine(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	referenceBlock, err := f.Client.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.referenceBlock = uint32(referenceBlock)
+	f.Backend.Commit()
+
+	f.certificates = []structs.BlobCertificate{
+		blobAllQuorums:     blobCertificate(0, []byte{0, 1}, relayKey),
+		blobFirstQuorum:    blobCertificate(0, []byte{0}, relayKey),
+		blobUnknownRelay:   blobCertificate(0, []byte{0, 1}, 7),
+		blobUnknownVersion: blobCertificate(1, []byte{0, 1}, relayKey),
+		blobTooManyQuorums: blobCertificate(0, make([]byte, 257), relayKey),
+	}
+	if f.tree, err = merkle.NewTreeFromBlobCertificates(f.certificates); err != nil {
+		t.Fatal(err)
+	}
+
+	f.registry = f.registryState(t)
+	if f.verifier, err = verification.NewCertVerifierFromContract(
+		f.opts, &f.CertVerifier.ContractEigenDACertVerifierCaller, f.registry); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// registryState reads the RegistryState of the latest block, which calls are evaluated at.
+func (f *certFixture) registryState(t *testing.T) *verification.RegistryState {
+	t.Helper()
+	blockNumber, err := f.Client.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	quorumCount, err := f.RegistryCoordinator.QuorumCount(f.opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	staleStakesForbidden, err := f.ServiceManager.StaleStakesForbidden(f.opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	relayAddress, err := f.RelayRegistry.RelayKeyToAddress(f.opts, relayKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &verification.RegistryState{
+		BlockNumber:           uint32(blockNumber),
+		QuorumCount:           quorumCount,
+		BlobParams:            map[uint16]structs.VersionedBlobParams{0: fixture.DefaultVersionedBlobParams},
+		RelayAddresses:        map[uint32]common.Address{relayKey: relayAddress},
+		StaleStakesForbidden:  staleStakesForbidden,
+		WithdrawalDelayBlocks: f.WithdrawalDelayBlocks,
+	}
+}
+
+func blobCertificate(version uint16, quorumNumbers []byte, relayKeys ...uint32) structs.BlobCertificate {
+	_, _, g1, g2 := bn254.Generators()
+	return structs.BlobCertificate{
+		BlobHeader: structs.BlobHeaderV2{
+			Version:       version,
+			QuorumNumbers: quorumNumbers,
+			Commitment: structs.BlobCommitment{
+				Commitment:       bls.G1Point(&g1),
+				LengthCommitment: bls.G2Point(&g2),
+				LengthProof:      bls.G2Point(&g2),
+				Length:           16,
+			},
+			PaymentHeaderHash: [32]byte{byte(version), byte(len(quorumNumbers))},
+		},
+		Signature: []byte{0x51},
+		RelayKeys: relayKeys,
+	}
+}
+
+// cert is a V2 cert and the signature state its indices point at.
+type cert struct {
+	batchHeader         structs.BatchHeaderV2
+	blobInclusionInfo   structs.BlobInclusionInfo
+	params              structs.NonSignerStakesAndSignature
+	signedQuorumNumbers []byte
+	state               *verification.SignatureState
+}
+
+// cert signs a batch of the fixture's certificates on quorums 0 and 1 with every operator but
+// nonSigners, and proves the inclusion of the certificate at blobIndex.
+func (f *certFixture) cert(t *testing.T, blobIndex uint32, nonSigners ...int) *cert {
+	t.Helper()
+	c := &cert{
+		batchHeader: structs.BatchHeaderV2{
+			BatchRoot:            f.tree.Root(),
+			ReferenceBlockNumber: f.referenceBlock,
+		},
+		signedQuorumNumbers: []byte{0, 1},
+	}
+	proof, err := f.tree.Proof(blobIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.blobInclusionInfo = structs.BlobInclusionInfo{
+		BlobCertificate: f.certificates[blobIndex],
+		BlobIndex:       blobIndex,
+		InclusionProof:  proof,
+	}
+
+	isNonSigner := make(map[int]bool)
+	for _, i := range nonSigners {
+		isNonSigner[i] = true
+	}
+	var signersKey, operatorKey fr.Element
+	var nonSignerPubkeys []structs.G1Point
+	for i, operator := range f.Operators {
+		if isNonSigner[i] {
+			nonSignerPubkeys = append(nonSignerPubkeys, operator.PubkeyG1)
+			continue
+		}
+		operatorKey.SetBigInt(operator.BLSPrivateKey)
+		signersKey.Add(&signersKey, &operatorKey)
+	}
+	// every operator is in every quorum, so the signers sign once per quorum
+	var numQuorums fr.Element
+	numQuorums.SetUint64(uint64(len(c.signedQuorumNumbers)))
+	signersKey.Mul(&signersKey, &numQuorums)
+	privateKey := signersKey.BigInt(new(big.Int))
+
+	sort.Slice(nonSignerPubkeys, func(i, j int) bool {
+		a, b := verification.OperatorID(nonSignerPubkeys[i]), verification.OperatorID(nonSignerPubkeys[j])
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	nonSignerIDs := make([][32]byte, len(nonSignerPubkeys))
+	for j, pubkey := range nonSignerPubkeys {
+		nonSignerIDs[j] = verification.OperatorID(pubkey)
+	}
+
+	indices, err := f.OperatorStateRetriever.GetCheckSignaturesIndices(
+		f.opts, f.Addresses.RegistryCoordinator, f.referenceBlock, c.signedQuorumNumbers, nonSignerIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	quorumApks := make([]structs.G1Point, len(c.signedQuorumNumbers))
+	for i, quorumNumber := range c.signedQuorumNumbers {
+		apk, err := f.BLSApkRegistry.GetApk(f.opts, quorumNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		quorumApks[i] = structs.MustConvert[structs.G1Point](apk)
+	}
+
+	msgHash, err := hashing.HashBatchHeaderV2(c.batchHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, _, g2 := bn254.Generators()
+	var apkG2 bn254.G2Affine
+	apkG2.ScalarMultiplication(&g2, privateKey)
+	c.params = structs.NonSignerStakesAndSignature{
+		NonSignerQuorumBitmapIndices: indices.NonSignerQuorumBitmapIndices,
+		NonSignerPubkeys:             nonSignerPubkeys,
+		QuorumApks:                   quorumApks,
+		ApkG2:                        bls.G2Point(&apkG2),
+		Sigma:                        bls.Sign(privateKey, msgHash),
+		QuorumApkIndices:             indices.QuorumApkIndices,
+		TotalStakeIndices:            indices.TotalStakeIndices,
+		NonSignerStakeIndices:        indices.NonSignerStakeIndices,
+	}
+	c.state = f.signatureState(t, c)
+	return c
+}
+
+// signatureState reads what the indices of c point at from the registries.
+func (f *certFixture) signatureState(t *testing.T, c *cert) *verification.SignatureState {
+	t.Helper()
+	referenceBlock := c.batchHeader.ReferenceBlockNumber
+	state := &verification.SignatureState{}
+	for j, pubkey := range c.params.NonSignerPubkeys {
+		bitmap, err := f.RegistryCoordinator.GetQuorumBitmapAtBlockNumberByIndex(
+			f.opts, verification.OperatorID(pubkey), referenceBlock, big.NewInt(int64(c.params.NonSignerQuorumBitmapIndices[j])))
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.NonSignerQuorumBitmaps = append(state.NonSignerQuorumBitmaps, bitmap)
+	}
+	for i, quorumNumber := range c.signedQuorumNumbers {
+		apk, err := f.BLSApkRegistry.GetApk(f.opts, quorumNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.QuorumApks = append(state.QuorumApks, structs.MustConvert[structs.G1Point](apk))
+
+		totalStake, err := f.StakeRegistry.GetTotalStakeAtBlockNumberFromIndex(
+			f.opts, quorumNumber, referenceBlock, big.NewInt(int64(c.params.TotalStakeIndices[i])))
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.TotalStakes = append(state.TotalStakes, totalStake)
+
+		var stakes []*big.Int
+		k := 0
+		for j, pubkey := range c.params.NonSignerPubkeys {
+			if state.NonSignerQuorumBitmaps[j].Bit(int(quorumNumber)) == 0 {
+				continue
+			}
+			stake, err := f.StakeRegistry.GetStakeAtBlockNumberAndIndex(
+				f.opts, quorumNumber, referenceBlock, verification.OperatorID(pubkey),
+				big.NewInt(int64(c.params.NonSignerStakeIndices[i][k])))
+			if err != nil {
+				t.Fatal(err)
+			}
+			stakes = append(stakes, stake)
+			k++
+		}
+		state.NonSignerStakes = append(state.NonSignerStakes, stakes)
+
+		updateBlockNumber, err := f.RegistryCoordinator.QuorumUpdateBlockNumber(f.opts, quorumNumber)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.QuorumUpdateBlockNumbers = append(state.QuorumUpdateBlockNumbers, uint32(updateBlockNumber.Uint64()))
+	}
+	return state
+}
+
+// check verifies c with the verification package and with EigenDACertVerifier.verifyDACertV2,
+// and fails unless both report want.
+func (f *certFixture) check(t *testing.T, c *cert, want error) {
+	t.Helper()
+	err := f.verifier.VerifyDACertV2(c.state, c.batchHeader, c.blobInclusionInfo, c.params, c.signedQuorumNumbers)
+	if !errors.Is(err, want) {
+		t.Errorf("VerifyDACertV2 = %v, want %v", err, want)
+	}
+
+	err = reverts.Decode(f.CertVerifier.VerifyDACertV2(f.opts, c.batchHeader, c.blobInclusionInfo, c.params, c.signedQuorumNumbers))
+	if errors.Is(want, verification.ErrECAddFailed) {
+		// BN254.plus reverts with invalid() before its require
+		var revertErr *reverts.RevertError
+		if !errors.As(err, &revertErr) || len(revertErr.Data) != 0 {
+			t.Errorf("contract verifyDACertV2 = %v, want a revert without data", err)
+		}
+		return
+	}
+	if !errors.Is(err, want) {
+		t.Errorf("contract verifyDACertV2 = %v, want %v", err, want)
+	}
+}
+
+func TestVerifyDACertV2ForQuorums(t *testing.T) {
+	f := newCertFixture(t)
+
+	notOnCurve := structs.G1Point{X: big.NewInt(1), Y: big.NewInt(1)}
+	_, _, g1, _ := bn254.Generators()
+
+	for _, tc := range []struct {
+		name       string
+		blobIndex  uint32
+		nonSigners []int
+		mutate     func(c *cert)
+		want       error
+	}{
+		{
+			name:      "all signed",
+			blobIndex: blobAllQuorums,
+		},
+		{
+			name:       "one non-signer",
+			blobIndex:  blobAllQuorums,
+			nonSigners: []int{2},
+		},
+		{
+			name:      "blob in the first quorum only",
+			blobIndex: blobFirstQuorum,
+			want:      verification.ErrRequiredQuorumsNotSubset,
+		},
+		{
+			name:      "inclusion proof for another blob",
+			blobIndex: blobAllQuorums,
+			mutate:    func(c *cert) { c.blobInclusionInfo.BlobIndex = blobFirstQuorum },
+			want:      verification.ErrInclusionProofInvalid,
+		},
+		{
+			name:      "no signed quorums",
+			blobIndex: blobAllQuorums,
+			mutate:    func(c *cert) { c.signedQuorumNumbers = []byte{} },
+			want:      verification.ErrEmptyQuorumInput,
+		},
+		{
+			name:      "quorum apk missing",
+			blobIndex: blobAllQuorums,
+			mutate:    func(c *cert) { c.params.QuorumApks = c.params.QuorumApks[:1] },
+			want:      verification.ErrQuorumLengthMismatch,
+		},
+		{
+			name:       "extra non-signer bitmap index",
+			blobIndex:  blobAllQuorums,
+			nonSigners: []int{1},
+			mutate: func(c *cert) {
+				c.params.NonSignerQuorumBitmapIndices = append(c.params.NonSignerQuorumBitmapIndices, 0)
+			},
+			want: verification.ErrNonSignerLengthMismatch,
+		},
+		{
+			name:      "reference block not in the past",
+			blobIndex: blobAllQuorums,
+			mutate:    func(c *cert) { c.batchHeader.ReferenceBlockNumber = f.registry.BlockNumber },
+			want:      verification.ErrInvalidReferenceBlock,
+		},
+		{
+			name:      "signed quorums out of order",
+			blobIndex: blobAllQuorums,
+			mutate:    func(c *cert) { c.signedQuorumNumbers = []byte{1, 0} },
+			want:      verification.ErrBytesArrayNotOrdered,
+		},
+		{
+			name:      "signed quorum that does not exist",
+			blobIndex: blobAllQuorums,
+			mutate: func(c *cert) {
+				c.signedQuorumNumbers = []byte{0, 1, 2}
+				c.params.QuorumApks = append(c.params.QuorumApks, c.params.QuorumApks[1])
+				c.params.QuorumApkIndices = append(c.params.QuorumApkIndices, c.params.QuorumApkIndices[1])
+				c.params.TotalStakeIndices = append(c.params.TotalStakeIndices, c.params.TotalStakeIndices[1])
+				c.params.NonSignerStakeIndices = append(c.params.NonSignerStakeIndices, c.params.NonSignerStakeIndices[1])
+				c.state.QuorumApks = append(c.state.QuorumApks, c.state.QuorumApks[1])
+				c.state.TotalStakes = append(c.state.TotalStakes, c.state.TotalStakes[1])
+				c.state.NonSignerStakes = append(c.state.NonSignerStakes, c.state.NonSignerStakes[1])
+				c.state.QuorumUpdateBlockNumbers = append(c.state.QuorumUpdateBlockNumbers, c.state.QuorumUpdateBlockNumbers[1])
+			},
+			want: verification.ErrBitmapExceedsMax,
+		},
+		{
+			name:       "non-signers not sorted",
+			blobIndex:  blobAllQuorums,
+			nonSigners: []int{0, 3},
+			mutate: func(c *cert) {
+				c.params.NonSignerPubkeys[0], c.params.NonSignerPubkeys[1] = c.params.NonSignerPubkeys[1], c.params.NonSignerPubkeys[0]
+			},
+			want: verification.ErrNonSignerPubkeysNotSorted,
+		},
+		{
+			name:      "wrong quorum apk",
+			blobIndex: blobAllQuorums,
+			mutate:    func(c *cert) { c.params.QuorumApks[1] = bls.G1Point(&g1) },
+			want:      verification.ErrQuorumApkMismatch,
+		},
+		{
+			name:       "non-signer stake index missing",
+			blobIndex:  blobAllQuorums,
+			nonSigners: []int{1},
+			mutate: func(c *cert) {
+				c.params.NonSignerStakeIndices[0] = []uint32{}
+				c.state.NonSignerStakes[0] = []*big.Int{}
+			},
+			want: verification.ErrPanicOutOfBounds,
+		},
+		{
+			name:      "sigma not on the curve",
+			blobIndex: blobAllQuorums,
+			mutate:    func(c *cert) { c.params.Sigma = notOnCurve },
+			want:      verification.ErrECAddFailed,
+		},
+		{
+			name:      "apkG2 not on the curve",
+			blobIndex: blobAllQuorums,
+			mutate: func(c *cert) {
+				c.params.ApkG2 = structs.G2Point{
+					X: [2]*big.Int{big.NewInt(1), big.NewInt(1)},
+					Y: [2]*big.Int{big.NewInt(1), big.NewInt(1)},
+				}
+			},
+			want: verification.ErrPairingPrecompileCallFailed,
+		},
+		{
+			name:      "signature of another message",
+			blobIndex: blobAllQuorums,
+			mutate:    func(c *cert) { c.params.Sigma = bls.Sign(big.NewInt(7), [32]byte{1}) },
+			want:      verification.ErrSignatureInvalid,
+		},
+		{
+			name:      "relay key not set",
+			blobIndex: blobUnknownRelay,
+			want:      verification.ErrRelayKeyNotSet,
+		},
+		{
+			name:      "blob version without params",
+			blobIndex: blobUnknownVersion,
+			want:      verification.ErrPanicDivisionByZero,
+		},
+		{
+			name:       "half the stake did not sign",
+			blobIndex:  blobAllQuorums,
+			nonSigners: []int{0, 1},
+			want:       verification.ErrBlobQuorumsNotSubset,
+		},
+		{
+			name:      "blob quorum numbers too long",
+			blobIndex: blobTooManyQuorums,
+			want:      verification.ErrBytesArrayTooLong,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := f.cert(t, tc.blobIndex, tc.nonSigners...)
+			if tc.mutate != nil {
+				tc.mutate(c)
+			}
+			f.check(t, c, tc.want)
+		})
+	}
+}
+
+// TestVerifyDACertV2StaleStakes forbids stale stakes and mines past the withdrawal delay, after
+// which a quorum that was never updated has stale stakes.
+func TestVerifyDACertV2StaleStakes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("mines a withdrawal delay worth of blocks")
+	}
+	f := newCertFixture(t)
+	// BLSSignatureChecker forbids stale stakes in its constructor, which the proxy does not run
+	tx, err := f.ServiceManager.SetStaleStakesForbidden(f.TransactOpts(f.Owner), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Mine(tx); err != nil {
+		t.Fatal(err)
+	}
+	f.registry.StaleStakesForbidden = true
+
+	for i := uint32(0); i <= f.WithdrawalDelayBlocks; i++ {
+		f.Backend.Commit()
+	}
+	blockNumber, err := f.Client.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.referenceBlock = uint32(blockNumber)
+	f.Backend.Commit()
+	f.registry.BlockNumber = uint32(blockNumber) + 1
+
+	f.check(t, f.cert(t, blobAllQuorums), verification.ErrStaleStakes)
+}
+
+func TestVerifySecurityParams(t *testing.T) {
+	f := newCertFixture(t)
+
+	for _, tc := range []struct {
+		name       string
+		blobParams structs.VersionedBlobParams
+		thresholds structs.SecurityThresholds
+		want       error
+	}{
+		{
+			name:       "default",
+			blobParams: fixture.DefaultVersionedBlobParams,
+			thresholds: fixture.DefaultSecurityThresholds,
+		},
+		{
+			name:       "adversary threshold above confirmation threshold",
+			blobParams: fixture.DefaultVersionedBlobParams,
+			thresholds: structs.SecurityThresholds{ConfirmationThreshold: 33, AdversaryThreshold: 55},
+			want:       verification.ErrInvalidSecurityThresholds,
+		},
+		{
+			name:       "equal thresholds",
+			blobParams: fixture.DefaultVersionedBlobParams,
+			thresholds: structs.SecurityThresholds{ConfirmationThreshold: 55, AdversaryThreshold: 55},
+			want:       verification.ErrInvalidSecurityThresholds,
+		},
+		{
+			name:       "too few chunks",
+			blobParams: structs.VersionedBlobParams{MaxNumOperators: 3537, NumChunks: 4096, CodingRate: 8},
+			thresholds: fixture.DefaultSecurityThresholds,
+			want:       verification.ErrSecurityAssumptionsNotMet,
+		},
+		{
+			name:       "coding rate too low for the gap",
+			blobParams: structs.VersionedBlobParams{MaxNumOperators: 3537, NumChunks: 8192, CodingRate: 1},
+			thresholds: fixture.DefaultSecurityThresholds,
+			want:       verification.ErrPanicArithmetic,
+		},
+		{
+			name:       "max operators overflow",
+			blobParams: structs.VersionedBlobParams{MaxNumOperators: 500_000, NumChunks: 8192, CodingRate: 8},
+			thresholds: fixture.DefaultSecurityThresholds,
+			want:       verification.ErrPanicArithmetic,
+		},
+		{
+			name:       "zero coding rate",
+			thresholds: fixture.DefaultSecurityThresholds,
+			want:       verification.ErrPanicDivisionByZero,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := verification.VerifySecurityParams(tc.blobParams, tc.thresholds)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("VerifySecurityParams = %v, want %v", err, tc.want)
+			}
+			err = reverts.Decode(f.CertVerifier.VerifyDACertSecurityParams(f.opts, tc.blobParams, tc.thresholds))
+			if !errors.Is(err, tc.want) {
+				t.Errorf("contract verifyDACertSecurityParams = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
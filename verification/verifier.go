@@ -0,0 +1,199 @@
+// Package verification checks EigenDA V2 blob certificates offline. It mirrors
+// EigenDACertVerificationUtils._verifyDACertV2ForQuorums step for step, serving every
+// contract read from state supplied by the caller instead of an RPC node.
+package verification
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	contractEigenDACertVerifier "github.com/Layr-Labs/eigenda/contracts/bindings/EigenDACertVerifier"
+	"github.com/Layr-Labs/eigenda/contracts/hashing"
+	"github.com/Layr-Labs/eigenda/contracts/merkle"
+	"github.com/Layr-Labs/eigenda/contracts/structs"
+)
+
+// thresholdDenominator is EigenDACertVerificationUtils.THRESHOLD_DENOMINATOR.
+const thresholdDenominator = 100
+
+// RegistryState is the contract state a V2 cert is verified against that does not depend on
+// the cert's reference block.
+type RegistryState struct {
+	// BlockNumber is the block verification is evaluated at; reference blocks must precede it.
+	BlockNumber uint32
+	// QuorumCount is RegistryCoordinator.quorumCount.
+	QuorumCount uint8
+	// BlobParams holds EigenDAThresholdRegistry.getBlobParams for each blob version.
+	// Missing versions read as the zero value, as they do on chain.
+	BlobParams map[uint16]structs.VersionedBlobParams
+	// RelayAddresses holds EigenDARelayRegistry.relayKeyToAddress for each registered relay key.
+	RelayAddresses map[uint32]common.Address
+	// StaleStakesForbidden is BLSSignatureChecker.staleStakesForbidden.
+	StaleStakesForbidden bool
+	// WithdrawalDelayBlocks is DelegationManager.minWithdrawalDelayBlocks.
+	WithdrawalDelayBlocks uint32
+}
+
+// VerifyDACertV2ForQuorums mirrors EigenDACertVerificationUtils._verifyDACertV2ForQuorums.
+// The checks run in the same order as on chain so the first failure matches the contract's revert.
+func VerifyDACertV2ForQuorums(
+	registry *RegistryState,
+	signatureState *SignatureState,
+	batchHeader structs.BatchHeaderV2,
+	blobInclusionInfo structs.BlobInclusionInfo,
+	nonSignerStakesAndSignature structs.NonSignerStakesAndSignature,
+	securityThresholds structs.SecurityThresholds,
+	requiredQuorumNumbers []byte,
+	signedQuorumNumbers []byte,
+) error {
+	// check blob inclusion in the batch from merkle proof
+	blobCertificateHash, err := hashing.HashBlobCertificate(blobInclusionInfo.BlobCertificate)
+	if err != nil {
+		return err
+	}
+	included, err := merkle.VerifyInclusionKeccak(
+		blobInclusionInfo.InclusionProof,
+		batchHeader.BatchRoot,
+		merkle.LeafHash(blobCertificateHash),
+		uint64(blobInclusionInfo.BlobIndex),
+	)
+	if err != nil {
+		return err
+	}
+	if !included {
+		return ErrInclusionProofInvalid
+	}
+
+	// check BLS signature and get stake signed for batch quorums
+	batchHeaderHash, err := hashing.HashBatchHeaderV2(batchHeader)
+	if err != nil {
+		return err
+	}
+	quorumStakeTotals, _, err := checkSignatures(
+		registry,
+		signatureState,
+		batchHeaderHash,
+		signedQuorumNumbers,
+		batchHeader.ReferenceBlockNumber,
+		nonSignerStakesAndSignature,
+	)
+	if err != nil {
+		return err
+	}
+
+	// check relay keys are set
+	for _, relayKey := range blobInclusionInfo.BlobCertificate.RelayKeys {
+		if registry.RelayAddresses[relayKey] == (common.Address{}) {
+			return ErrRelayKeyNotSet
+		}
+	}
+
+	// check the blob version is valid with security thresholds
+	err = VerifySecurityParams(registry.BlobParams[blobInclusionInfo.BlobCertificate.BlobHeader.Version], securityThresholds)
+	if err != nil {
+		return err
+	}
+
+	// record confirmed quorums where signatories own at least the threshold percentage of the quorum
+	confirmedQuorumsBitmap := new(big.Int)
+	for i, quorumNumber := range signedQuorumNumbers {
+		signed := new(big.Int).Mul(quorumStakeTotals.SignedStakeForQuorum[i], big.NewInt(thresholdDenominator))
+		// total stake times the threshold is evaluated as a uint96 on chain
+		required := new(big.Int).Mul(quorumStakeTotals.TotalStakeForQuorum[i], big.NewInt(int64(securityThresholds.ConfirmationThreshold)))
+		if required.Cmp(maxUint96) > 0 {
+			return ErrPanicArithmetic
+		}
+		if signed.Cmp(required) >= 0 {
+			confirmedQuorumsBitmap.SetBit(confirmedQuorumsBitmap, int(quorumNumber), 1)
+		}
+	}
+
+	blobQuorumsBitmap, err := orderedBytesArrayToBitmap(blobInclusionInfo.BlobCertificate.BlobHeader.QuorumNumbers)
+	if err != nil {
+		return err
+	}
+
+	// check if the blob quorums are a subset of the confirmed quorums
+	if !isSubsetOf(blobQuorumsBitmap, confirmedQuorumsBitmap) {
+		return ErrBlobQuorumsNotSubset
+	}
+
+	// check if the required quorums are a subset of the blob quorums
+	requiredQuorumsBitmap, err := orderedBytesArrayToBitmap(requiredQuorumNumbers)
+	if err != nil {
+		return err
+	}
+	if !isSubsetOf(requiredQuorumsBitmap, blobQuorumsBitmap) {
+		return ErrRequiredQuorumsNotSubset
+	}
+	return nil
+}
+
+// CertVerifier mirrors an EigenDACertVerifier deployment, whose V2 security thresholds and
+// required quorums are fixed at construction.
+type CertVerifier struct {
+	registry              *RegistryState
+	securityThresholds    structs.SecurityThresholds
+	quorumNumbersRequired []byte
+}
+
+// NewCertVerifier returns a CertVerifier checking certs against registry with the given
+// security thresholds and required quorums.
+func NewCertVerifier(
+	registry *RegistryState,
+	securityThresholds structs.SecurityThresholds,
+	quorumNumbersRequired []byte,
+) *CertVerifier {
+	return &CertVerifier{
+		registry:              registry,
+		securityThresholds:    securityThresholds,
+		quorumNumbersRequired: quorumNumbersRequired,
+	}
+}
+
+// NewCertVerifierFromContract returns a CertVerifier using the security thresholds and required
+// quorums of a deployed EigenDACertVerifier.
+func NewCertVerifierFromContract(
+	opts *bind.CallOpts,
+	caller *contractEigenDACertVerifier.ContractEigenDACertVerifierCaller,
+	registry *RegistryState,
+) (*CertVerifier, error) {
+	securityThresholds, err := caller.SecurityThresholdsV2(opts)
+	if err != nil {
+		return nil, err
+	}
+	quorumNumbersRequired, err := caller.QuorumNumbersRequiredV2(opts)
+	if err != nil {
+		return nil, err
+	}
+	return NewCertVerifier(
+		registry,
+		structs.SecurityThresholds{
+			ConfirmationThreshold: securityThresholds.ConfirmationThreshold,
+			AdversaryThreshold:    securityThresholds.AdversaryThreshold,
+		},
+		quorumNumbersRequired,
+	), nil
+}
+
+// VerifyDACertV2 mirrors EigenDACertVerifier.verifyDACertV2.
+func (v *CertVerifier) VerifyDACertV2(
+	signatureState *SignatureState,
+	batchHeader structs.BatchHeaderV2,
+	blobInclusionInfo structs.BlobInclusionInfo,
+	nonSignerStakesAndSignature structs.NonSignerStakesAndSignature,
+	signedQuorumNumbers []byte,
+) error {
+	return VerifyDACertV2ForQuorums(
+		v.registry,
+		signatureState,
+		batchHeader,
+		blobInclusionInfo,
+		nonSignerStakesAndSignature,
+		v.securityThresholds,
+		v.quorumNumbersRequired,
+		signedQuorumNumbers,
+	)
+}